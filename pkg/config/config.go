@@ -5,11 +5,19 @@ import (
 )
 
 var (
+	DB_DRIVER            = os.Getenv("DB_DRIVER")
+	SQLITE_PATH          = os.Getenv("SQLITE_PATH")
 	D1_ACCOUNT_ID        = os.Getenv("D1_ACCOUNT_ID")
 	D1_DATABASE_ID       = os.Getenv("D1_DATABASE_ID")
 	CLOUDFLARE_API_TOKEN = os.Getenv("CLOUDFLARE_API_TOKEN")
 	YOUTUBE_API_KEY      = os.Getenv("YOUTUBE_API_KEY")
+	YOUTUBE_QUOTA_BUDGET = os.Getenv("YOUTUBE_QUOTA_BUDGET")
 	IGDB_CLIENT_ID       = os.Getenv("IGDB_CLIENT_ID")
 	IGDB_CLIENT_SECRET   = os.Getenv("IGDB_CLIENT_SECRET")
 	SCHEDULE_CRON        = os.Getenv("SCHEDULE_CRON")
+	STATS_REFRESH_CRON   = os.Getenv("STATS_REFRESH_CRON")
+	WEBSUB_CALLBACK_URL  = os.Getenv("WEBSUB_CALLBACK_URL")
+	WEBSUB_SECRET        = os.Getenv("WEBSUB_SECRET")
+	WEBSUB_HUB_URL       = os.Getenv("WEBSUB_HUB_URL")
+	SLOW_QUERY_MS        = os.Getenv("SLOW_QUERY_MS")
 )
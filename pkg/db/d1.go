@@ -0,0 +1,441 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v6"
+	"github.com/cloudflare/cloudflare-go/v6/d1"
+	"github.com/cloudflare/cloudflare-go/v6/option"
+)
+
+const (
+	sqliteDateTimeFormat = "2006-01-02 15:04:05"
+	sqliteDateFormat     = "2006-01-02"
+
+	deferForeignKeysPragma = "PRAGMA defer_foreign_keys = on"
+)
+
+// D1Database talks to Cloudflare D1 over the REST API.
+type D1Database struct {
+	client     *cloudflare.Client
+	accountID  string
+	databaseID string
+	sqlDB      *sql.DB
+	retry      RetryPolicy
+	observers  []QueryObserver
+}
+
+// NewD1Database connects to a D1 database. Extra options are passed to the
+// Cloudflare client, e.g. option.WithBaseURL to target a local stand-in.
+//
+// The client's own retries are disabled: it retries POSTs on any 5xx or
+// dropped connection, which is unsafe for writes. Requests are retried
+// according to DefaultRetryPolicy instead; see RetryPolicy.
+func NewD1Database(accountID, databaseID, apiToken string, opts ...option.RequestOption) (*D1Database, error) {
+	if err := validateDatabaseConfig(accountID, databaseID, apiToken); err != nil {
+		return nil, err
+	}
+
+	opts = append([]option.RequestOption{option.WithAPIToken(apiToken), option.WithMaxRetries(0)}, opts...)
+	client := cloudflare.NewClient(opts...)
+
+	d := &D1Database{
+		client:     client,
+		accountID:  accountID,
+		databaseID: databaseID,
+		retry:      DefaultRetryPolicy,
+	}
+	d.sqlDB = sql.OpenDB(&d1Driver{database: d})
+
+	return d, nil
+}
+
+func validateDatabaseConfig(accountID, databaseID, apiToken string) error {
+	if accountID == "" || databaseID == "" || apiToken == "" {
+		return fmt.Errorf("accountID, databaseID, and apiToken are required")
+	}
+	return nil
+}
+
+func (d *D1Database) Close() error {
+	return d.sqlDB.Close()
+}
+
+func (d *D1Database) Conn() *sql.DB {
+	return d.sqlDB
+}
+
+// BeginTx starts a buffered transaction. See d1Tx for its semantics.
+func (d *D1Database) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return d.sqlDB.BeginTx(ctx, opts)
+}
+
+func (d *D1Database) PingContext(ctx context.Context) error {
+	return d.withRetry(ctx, true, func() error {
+		_, err := d.client.D1.Database.Query(ctx, d.databaseID, d1.DatabaseQueryParams{
+			AccountID: cloudflare.F(d.accountID),
+			Sql:       cloudflare.F("SELECT 1"),
+		})
+		return classifyD1Error(err)
+	})
+}
+
+// D1 Result structures. Rows are kept in the array form returned by the /raw
+// endpoint so column order matches the SELECT list.
+type QueryResult struct {
+	Success bool            `json:"success"`
+	Meta    QueryResultMeta `json:"meta"`
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+type QueryResultMeta struct {
+	ChangedDB   bool    `json:"changed_db"`
+	Changes     float64 `json:"changes"`
+	Duration    float64 `json:"duration"`
+	LastRowID   float64 `json:"last_row_id"`
+	RowsRead    float64 `json:"rows_read"`
+	RowsWritten float64 `json:"rows_written"`
+	SizeAfter   float64 `json:"size_after"`
+}
+
+// D1Result implements sql.Result interface
+type D1Result struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (r D1Result) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r D1Result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+func (d *D1Database) executeQuery(ctx context.Context, query string, args ...any) (*QueryResult, error) {
+	// Writes are sent as a batch behind a PRAGMA that defers foreign key
+	// checks; placeholders can't be mixed into a multi-statement sql string.
+	if isWriteOperation(query) {
+		results, err := d.executeBatch(ctx, []d1Statement{{query: query, args: args}})
+		if err != nil {
+			return nil, err
+		}
+		return &results[0], nil
+	}
+
+	q, err := newD1Query(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(q)
+	if err != nil {
+		return nil, fmt.Errorf("encode D1 query: %w", err)
+	}
+
+	results, err := d.raw(ctx, body, true)
+	if err == nil && len(results) == 0 {
+		err = errNoResults
+	}
+	if err == nil && !results[len(results)-1].Success {
+		err = &Error{Message: "query failed"}
+	}
+	if err != nil {
+		d.record(ctx, query, QueryResultMeta{}, err)
+		return nil, err
+	}
+
+	result := convertD1RawResult(results[len(results)-1])
+	d.record(ctx, query, result.Meta, nil)
+
+	return result, nil
+}
+
+// raw posts body to the /raw endpoint, retrying according to d.retry.
+// idempotent reports whether the request may safely run more than once.
+func (d *D1Database) raw(ctx context.Context, body []byte, idempotent bool) ([]d1.DatabaseRawResponse, error) {
+	var results []d1.DatabaseRawResponse
+
+	err := d.withRetry(ctx, idempotent, func() error {
+		resp, err := d.client.D1.Database.Raw(ctx, d.databaseID, d1.DatabaseRawParams{
+			AccountID: cloudflare.F(d.accountID),
+		}, option.WithRequestBody("application/json", body))
+		if err != nil {
+			return classifyD1Error(err)
+		}
+		results = resp.Result
+		return nil
+	})
+
+	return results, err
+}
+
+// isWriteOperation reports whether query changes the database. A statement
+// starting with WITH is judged by the statement after its common table
+// expressions.
+func isWriteOperation(query string) bool {
+	switch statementKeyword(query) {
+	case "INSERT", "UPDATE", "DELETE", "REPLACE":
+		return true
+	}
+	return false
+}
+
+// statementKeyword returns the keyword query starts with, in upper case. For
+// a WITH statement it is the first statement keyword outside of the common
+// table expressions, whose bodies are in parentheses. Quoted strings and
+// names and comments are skipped.
+func statementKeyword(query string) string {
+	with := false
+	depth := 0
+
+	for i := 0; i < len(query); {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				return ""
+			}
+			i += end + 2
+		case c == '[':
+			end := strings.IndexByte(query[i+1:], ']')
+			if end < 0 {
+				return ""
+			}
+			i += end + 2
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return ""
+			}
+			i += end + 1
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return ""
+			}
+			i += end + 4
+		case c == '(':
+			depth++
+			i++
+		case c == ')':
+			depth--
+			i++
+		case isKeywordByte(c):
+			start := i
+			for i < len(query) && isKeywordByte(query[i]) {
+				i++
+			}
+			if depth > 0 {
+				continue
+			}
+
+			word := strings.ToUpper(query[start:i])
+			if !with {
+				if word != "WITH" {
+					return word
+				}
+				with = true
+				continue
+			}
+			switch word {
+			case "SELECT", "VALUES", "INSERT", "UPDATE", "DELETE", "REPLACE":
+				return word
+			}
+		default:
+			i++
+		}
+	}
+
+	return ""
+}
+
+func isKeywordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func convertD1RawResult(r d1.DatabaseRawResponse) *QueryResult {
+	return &QueryResult{
+		Success: r.Success,
+		Meta:    convertD1Meta(r.Meta),
+		Columns: r.Results.Columns,
+		Rows:    r.Results.Rows,
+	}
+}
+
+func convertD1Meta(meta d1.DatabaseRawResponseMeta) QueryResultMeta {
+	return QueryResultMeta{
+		ChangedDB:   meta.ChangedDB,
+		Changes:     meta.Changes,
+		Duration:    meta.Duration,
+		LastRowID:   meta.LastRowID,
+		RowsRead:    meta.RowsRead,
+		RowsWritten: meta.RowsWritten,
+		SizeAfter:   meta.SizeAfter,
+	}
+}
+
+// D1 driver implementation. d1Driver doubles as the driver.Connector handed
+// to sql.OpenDB so no global driver registration is needed.
+type d1Driver struct {
+	database *D1Database
+}
+
+func (drv *d1Driver) Open(name string) (driver.Conn, error) {
+	return &d1Conn{database: drv.database}, nil
+}
+
+func (drv *d1Driver) Connect(ctx context.Context) (driver.Conn, error) {
+	return drv.Open("")
+}
+
+func (drv *d1Driver) Driver() driver.Driver {
+	return drv
+}
+
+type d1Conn struct {
+	database *D1Database
+	tx       *d1Tx
+}
+
+func (c *d1Conn) Prepare(query string) (driver.Stmt, error) {
+	return &d1Stmt{conn: c, query: query}, nil
+}
+
+func (c *d1Conn) Close() error {
+	return nil
+}
+
+func (c *d1Conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *d1Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, fmt.Errorf("transaction already in progress")
+	}
+
+	// D1 batches always run serializably, so anything weaker is fine too.
+	if opts.Isolation > driver.IsolationLevel(sql.LevelSerializable) {
+		return nil, fmt.Errorf("isolation level %d not supported with D1", opts.Isolation)
+	}
+
+	c.tx = &d1Tx{conn: c, ctx: ctx, readOnly: opts.ReadOnly}
+	return c.tx, nil
+}
+
+func (c *d1Conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	queryArgs := convertNamedValuesToArgs(args)
+
+	if c.tx != nil {
+		return c.tx.queue(query, queryArgs)
+	}
+
+	result, err := c.database.executeQuery(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return D1Result{
+		lastInsertID: int64(result.Meta.LastRowID),
+		rowsAffected: int64(result.Meta.Changes),
+	}, nil
+}
+
+func (c *d1Conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryArgs := convertNamedValuesToArgs(args)
+
+	result, err := c.database.executeQuery(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newD1Rows(result.Columns, result.Rows), nil
+}
+
+func convertNamedValuesToArgs(args []driver.NamedValue) []any {
+	queryArgs := make([]any, len(args))
+	for i, arg := range args {
+		queryArgs[i] = arg.Value
+	}
+	return queryArgs
+}
+
+type d1Stmt struct {
+	conn  *d1Conn
+	query string
+}
+
+func (s *d1Stmt) Close() error {
+	return nil
+}
+
+func (s *d1Stmt) NumInput() int {
+	return len(placeholderOffsets(s.query))
+}
+
+func (s *d1Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), convertValuesToNamedValues(args))
+}
+
+func (s *d1Stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), convertValuesToNamedValues(args))
+}
+
+func (s *d1Stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *d1Stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func parseDateTime(strVal string) interface{} {
+	cleanedStr := stripMonotonicClock(strVal)
+
+	timeFormats := []string{
+		time.RFC3339,
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05 -0700 MST",
+		"2006-01-02 15:04:05.999999999 -0700 MST",
+		"2006-01-02 15:04:05.999999999 -0700 -07",
+		sqliteDateTimeFormat,
+		sqliteDateFormat,
+	}
+
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, cleanedStr); err == nil {
+			if format == sqliteDateTimeFormat || format == sqliteDateFormat {
+				return cleanedStr
+			}
+			return t.Format(sqliteDateTimeFormat)
+		}
+	}
+
+	return strVal
+}
+
+func stripMonotonicClock(s string) string {
+	if idx := strings.Index(s, " m="); idx > 0 {
+		return s[:idx]
+	}
+	return s
+}
+
+func convertValuesToNamedValues(values []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(values))
+	for i, v := range values {
+		named[i] = driver.NamedValue{
+			Ordinal: i + 1,
+			Value:   v,
+		}
+	}
+	return named
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxSafeInteger is the largest integer a JSON number can carry without
+// losing precision once D1 parses it as a JavaScript double.
+const maxSafeInteger = 1<<53 - 1
+
+// d1Query is a single statement with its bound parameters, as sent to the
+// D1 /raw endpoint either on its own or as one element of a batch.
+type d1Query struct {
+	Sql    string `json:"sql"`
+	Params []any  `json:"params,omitempty"`
+}
+
+// newD1Query binds args to the placeholders in query as typed JSON values:
+//
+//   - nil becomes NULL
+//   - integers become JSON numbers, or decimal strings outside ±2^53 so
+//     that INTEGER column affinity restores them exactly
+//   - floats become JSON numbers (NaN and ±Inf are rejected)
+//   - bools become 1 or 0
+//   - time.Time becomes a UTC "YYYY-MM-DD HH:MM:SS" string, matching
+//     CURRENT_TIMESTAMP
+//   - []byte is sent hex-encoded and its placeholder rewritten to unhex(?),
+//     since JSON has no blob type
+//   - strings must be valid UTF-8
+func newD1Query(query string, args []any) (d1Query, error) {
+	if len(args) == 0 {
+		return d1Query{Sql: query}, nil
+	}
+
+	offsets := placeholderOffsets(query)
+	if len(offsets) != len(args) {
+		return d1Query{}, fmt.Errorf("query has %d placeholders but %d arguments were given", len(offsets), len(args))
+	}
+
+	params := make([]any, len(args))
+	var rewritten strings.Builder
+	last := 0
+
+	for i, arg := range args {
+		value, isBlob, err := d1ParamValue(arg)
+		if err != nil {
+			return d1Query{}, fmt.Errorf("argument %d: %w", i+1, err)
+		}
+		params[i] = value
+
+		if isBlob {
+			rewritten.WriteString(query[last:offsets[i]])
+			rewritten.WriteString("unhex(?)")
+			last = offsets[i] + 1
+		}
+	}
+
+	if last == 0 {
+		return d1Query{Sql: query, Params: params}, nil
+	}
+
+	rewritten.WriteString(query[last:])
+	return d1Query{Sql: rewritten.String(), Params: params}, nil
+}
+
+func d1ParamValue(arg any) (value any, isBlob bool, err error) {
+	switch v := arg.(type) {
+	case nil:
+		return nil, false, nil
+	case string:
+		if !utf8.ValidString(v) {
+			return nil, false, fmt.Errorf("string is not valid UTF-8")
+		}
+		return v, false, nil
+	case int64:
+		if v > maxSafeInteger || v < -maxSafeInteger {
+			return fmt.Sprintf("%d", v), false, nil
+		}
+		return v, false, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, false, fmt.Errorf("cannot bind %v", v)
+		}
+		return v, false, nil
+	case bool:
+		if v {
+			return int64(1), false, nil
+		}
+		return int64(0), false, nil
+	case []byte:
+		return hex.EncodeToString(v), true, nil
+	case time.Time:
+		return v.UTC().Format(sqliteDateTimeFormat), false, nil
+	default:
+		return nil, false, fmt.Errorf("unsupported type %T", arg)
+	}
+}
+
+// placeholderOffsets returns the byte offset of every "?" placeholder in
+// query, skipping string literals, quoted identifiers and comments.
+func placeholderOffsets(query string) []int {
+	var offsets []int
+
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '?':
+			offsets = append(offsets, i)
+		case '\'', '"', '`':
+			i = skipQuoted(query, i, query[i])
+		case '[':
+			i = skipQuoted(query, i, ']')
+		case '-':
+			if i+1 < len(query) && query[i+1] == '-' {
+				if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+					i += end
+				} else {
+					i = len(query)
+				}
+			}
+		case '/':
+			if i+1 < len(query) && query[i+1] == '*' {
+				if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+					i += end + 3
+				} else {
+					i = len(query)
+				}
+			}
+		}
+	}
+
+	return offsets
+}
+
+// skipQuoted returns the offset of the character closing the quoted section
+// that opens at start. A doubled closing character is an escape.
+func skipQuoted(query string, start int, closing byte) int {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != closing {
+			continue
+		}
+		if closing != ']' && i+1 < len(query) && query[i+1] == closing {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(query)
+}
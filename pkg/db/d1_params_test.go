@@ -0,0 +1,210 @@
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestPlaceholderOffsets(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"SELECT 1", nil},
+		{"SELECT ?, ?", []int{7, 10}},
+		{"SELECT '?' , ?", []int{13}},
+		{"SELECT 'it''s ?', ?", []int{18}},
+		{`SELECT "a?" , ?`, []int{14}},
+		{"SELECT `a?` , ?", []int{14}},
+		{"SELECT [a?] , ?", []int{14}},
+		{"SELECT ? -- ?\n, ?", []int{7, 16}},
+		{"SELECT /* ? */ ?", []int{15}},
+		{"SELECT 'unterminated ?", nil},
+		{"SELECT ? /* unterminated ?", []int{7}},
+	}
+
+	for _, tt := range tests {
+		if got := placeholderOffsets(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("placeholderOffsets(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestNewD1Query(t *testing.T) {
+	published := time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("ICT", 7*60*60))
+
+	q, err := newD1Query(
+		"INSERT INTO t (a, b, c, d, e, f, g, h, i) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) -- '?'",
+		[]any{nil, "it's ?", int64(42), int64(math.MaxInt64), 0.1, true, false, []byte{0xde, 0xad}, published},
+	)
+	if err != nil {
+		t.Fatalf("newD1Query: %v", err)
+	}
+
+	wantSQL := "INSERT INTO t (a, b, c, d, e, f, g, h, i) VALUES (?, ?, ?, ?, ?, ?, ?, unhex(?), ?) -- '?'"
+	if q.Sql != wantSQL {
+		t.Errorf("sql = %q, want %q", q.Sql, wantSQL)
+	}
+
+	wantParams := []any{nil, "it's ?", int64(42), "9223372036854775807", 0.1, int64(1), int64(0), "dead", "2024-05-06 00:08:09"}
+	if !reflect.DeepEqual(q.Params, wantParams) {
+		t.Errorf("params = %#v, want %#v", q.Params, wantParams)
+	}
+
+	body, err := json.Marshal(q)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(body), `"params":[null,"it's ?",42,"9223372036854775807",0.1,1,0,"dead","2024-05-06 00:08:09"]`) {
+		t.Errorf("unexpected body %s", body)
+	}
+}
+
+func TestNewD1QueryErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		args  []any
+	}{
+		{"too few placeholders", "SELECT ?", []any{int64(1), int64(2)}},
+		{"too many placeholders", "SELECT ?, ?", []any{int64(1)}},
+		{"placeholder inside literal", "SELECT '?'", []any{int64(1)}},
+		{"NaN", "SELECT ?", []any{math.NaN()}},
+		{"infinity", "SELECT ?", []any{math.Inf(1)}},
+		{"invalid UTF-8", "SELECT ?", []any{"\xff"}},
+		{"unsupported type", "SELECT ?", []any{struct{}{}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := newD1Query(tt.query, tt.args); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func FuzzPlaceholderOffsets(f *testing.F) {
+	f.Add("SELECT ?, '?', \"?\", [?], `?` -- ?\n /* ? */ ?")
+	f.Add("SELECT 'it''s' || ?")
+	f.Add("?'?")
+
+	f.Fuzz(func(t *testing.T, query string) {
+		offsets := placeholderOffsets(query)
+
+		if len(offsets) > strings.Count(query, "?") {
+			t.Fatalf("found %d placeholders in a query with %d question marks", len(offsets), strings.Count(query, "?"))
+		}
+
+		prev := -1
+		for _, off := range offsets {
+			if off <= prev || query[off] != '?' {
+				t.Fatalf("bad offset %d in %v for %q", off, offsets, query)
+			}
+			prev = off
+		}
+
+		if !strings.ContainsAny(query, "'\"`[-/") && len(offsets) != strings.Count(query, "?") {
+			t.Fatalf("plain query %q: got %d placeholders, want %d", query, len(offsets), strings.Count(query, "?"))
+		}
+	})
+}
+
+// FuzzD1ParamsRoundTrip binds arguments the way the D1 driver does, passes
+// them through JSON, and executes the statement on SQLite the way D1 would,
+// checking every value comes back unchanged.
+func FuzzD1ParamsRoundTrip(f *testing.F) {
+	f.Add("hello", int64(1), 1.5, true, []byte{0, 1, 2})
+	f.Add("it's a '?'", int64(math.MinInt64), -0.0, false, []byte{})
+	f.Add("ภาษาไทย", int64(1<<53), math.MaxFloat64, true, []byte("blob"))
+	f.Add("", int64(-(1 << 53)), math.SmallestNonzeroFloat64, false, []byte(nil))
+
+	conn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		f.Fatalf("open sqlite: %v", err)
+	}
+	conn.SetMaxOpenConns(1)
+	f.Cleanup(func() { conn.Close() })
+
+	f.Fuzz(func(t *testing.T, s string, i int64, fl float64, b bool, blob []byte) {
+		args := []any{s, i, fl, b, blob, nil}
+		q, err := newD1Query("SELECT ?, ?, ?, ?, ?, ? -- '?'", args)
+
+		if !utf8.ValidString(s) || math.IsNaN(fl) || math.IsInf(fl, 0) {
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("newD1Query: %v", err)
+		}
+
+		params, err := decodeParamsLikeD1(q.Params)
+		if err != nil {
+			t.Fatalf("decode params: %v", err)
+		}
+
+		var (
+			gotS    string
+			gotI    int64
+			gotF    float64
+			gotB    bool
+			gotBlob []byte
+			gotNil  any
+		)
+		row := conn.QueryRow(q.Sql, params...)
+		if err := row.Scan(&gotS, &gotI, &gotF, &gotB, &gotBlob, &gotNil); err != nil {
+			t.Fatalf("scan %q %#v: %v", q.Sql, params, err)
+		}
+
+		if gotS != s {
+			t.Errorf("string = %q, want %q", gotS, s)
+		}
+		if gotI != i {
+			t.Errorf("int = %d, want %d", gotI, i)
+		}
+		if gotF != fl {
+			t.Errorf("float = %v, want %v", gotF, fl)
+		}
+		if gotB != b {
+			t.Errorf("bool = %v, want %v", gotB, b)
+		}
+		if !bytes.Equal(gotBlob, blob) {
+			t.Errorf("blob = %x, want %x", gotBlob, blob)
+		}
+		if gotNil != nil {
+			t.Errorf("nil = %#v, want nil", gotNil)
+		}
+	})
+}
+
+// decodeParamsLikeD1 mimics D1's handling of JSON params: numbers are
+// doubles, and integral ones are bound as INTEGER.
+func decodeParamsLikeD1(params []any) ([]any, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded []any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+
+	values := make([]any, len(decoded))
+	for i, v := range decoded {
+		if f, ok := v.(float64); ok && f == math.Trunc(f) && math.Abs(f) <= maxSafeInteger {
+			values[i] = driver.Value(int64(f))
+			continue
+		}
+		values[i] = v
+	}
+	return values, nil
+}
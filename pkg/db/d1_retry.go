@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how D1 requests are retried.
+//
+// A request is retried when D1 rate limits it, since the request was never
+// run, and when a read fails with ErrTransient. Writes that fail with
+// ErrTransient are NOT retried: the write may already have been applied and
+// repeating it is not safe in general.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first. Values
+	// below 1 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt; it doubles on each
+	// later attempt up to MaxDelay. The actual wait is a random duration
+	// between zero and that bound ("full jitter"), so clients that failed
+	// together do not retry together.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy is used by NewD1Database.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// SetRetryPolicy replaces the retry policy used for subsequent requests.
+func (d *D1Database) SetRetryPolicy(p RetryPolicy) {
+	d.retry = p
+}
+
+func (p RetryPolicy) shouldRetry(err error, idempotent bool) bool {
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	return idempotent && errors.Is(err, ErrTransient)
+}
+
+// delay returns how long to wait after the given (1-based) failed attempt.
+// A Retry-After hint from the server takes precedence when it is longer.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	bound := p.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if d := p.BaseDelay << shift; d > 0 && d < bound {
+			bound = d
+		}
+	}
+
+	var wait time.Duration
+	if bound > 0 {
+		wait = rand.N(bound + 1)
+	}
+
+	var dbErr *Error
+	if errors.As(err, &dbErr) && dbErr.RetryAfter > wait {
+		wait = min(dbErr.RetryAfter, p.MaxDelay)
+	}
+
+	return wait
+}
+
+// withRetry calls fn until it succeeds, fails with an error the policy does
+// not retry, runs out of attempts, or ctx is done.
+func (d *D1Database) withRetry(ctx context.Context, idempotent bool, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= d.retry.MaxAttempts || !d.retry.shouldRetry(err, idempotent) {
+			return err
+		}
+
+		timer := time.NewTimer(d.retry.delay(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"database/sql/driver"
+	"io"
+	"math"
+)
+
+// SQLite storage classes reported by d1Rows.ColumnTypeDatabaseTypeName. D1
+// does not return declared column types, so they are inferred from the
+// values in the result set.
+const (
+	d1TypeInteger = "INTEGER"
+	d1TypeReal    = "REAL"
+	d1TypeText    = "TEXT"
+	d1TypeBlob    = "BLOB"
+)
+
+// d1Rows implements driver.Rows over a D1 /raw result, where each row is an
+// array ordered like columns.
+type d1Rows struct {
+	columns  []string
+	rows     [][]interface{}
+	index    int
+	types    []string
+	nullable []bool
+}
+
+func newD1Rows(columns []string, rows [][]interface{}) *d1Rows {
+	r := &d1Rows{
+		columns: columns,
+		rows:    rows,
+		index:   -1,
+	}
+	r.inferColumnTypes()
+	return r
+}
+
+func (r *d1Rows) Columns() []string {
+	return r.columns
+}
+
+func (r *d1Rows) Close() error {
+	return nil
+}
+
+func (r *d1Rows) Next(dest []driver.Value) error {
+	r.index++
+	if r.index >= len(r.rows) {
+		return io.EOF
+	}
+
+	row := r.rows[r.index]
+	for i := range dest {
+		if i >= len(row) {
+			dest[i] = nil
+			continue
+		}
+		dest[i] = convertColumnValue(row[i], r.types[i])
+	}
+	return nil
+}
+
+// ColumnTypeDatabaseTypeName returns the storage class of the column, or an
+// empty string when every value in it is NULL.
+func (r *d1Rows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.types[index]
+}
+
+// ColumnTypeNullable reports whether the column held a NULL. A column
+// without NULLs may still be nullable, so ok is false in that case.
+func (r *d1Rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if r.nullable[index] {
+		return true, true
+	}
+	return false, false
+}
+
+func (r *d1Rows) inferColumnTypes() {
+	r.types = make([]string, len(r.columns))
+	r.nullable = make([]bool, len(r.columns))
+
+	for _, row := range r.rows {
+		for i := range r.columns {
+			var val interface{}
+			if i < len(row) {
+				val = row[i]
+			}
+			if val == nil {
+				r.nullable[i] = true
+				continue
+			}
+			r.types[i] = widenColumnType(r.types[i], valueColumnType(val))
+		}
+	}
+}
+
+func valueColumnType(val interface{}) string {
+	switch v := val.(type) {
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return d1TypeInteger
+		}
+		return d1TypeReal
+	case bool:
+		return d1TypeInteger
+	case []interface{}:
+		return d1TypeBlob
+	default:
+		return d1TypeText
+	}
+}
+
+// widenColumnType combines the types seen so far in a column. Integers and
+// reals mix into REAL; anything else mixed falls back to TEXT.
+func widenColumnType(current, next string) string {
+	switch {
+	case current == "" || current == next:
+		return next
+	case (current == d1TypeInteger && next == d1TypeReal) || (current == d1TypeReal && next == d1TypeInteger):
+		return d1TypeReal
+	default:
+		return d1TypeText
+	}
+}
+
+// convertColumnValue turns a JSON-decoded D1 value into a driver.Value.
+func convertColumnValue(val interface{}, columnType string) driver.Value {
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case string:
+		return parseDateTime(v)
+	case float64:
+		if columnType == d1TypeInteger {
+			return int64(v)
+		}
+		return v
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	case []interface{}:
+		return blobFromJSON(v)
+	default:
+		return v
+	}
+}
+
+// blobFromJSON converts a BLOB, which D1 serializes as an array of byte
+// values, back to []byte.
+func blobFromJSON(values []interface{}) []byte {
+	b := make([]byte, len(values))
+	for i, v := range values {
+		if f, ok := v.(float64); ok {
+			b[i] = byte(f)
+		}
+	}
+	return b
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/cloudflare/cloudflare-go/v6/option"
+)
+
+// newRawD1Server answers every /raw request with the given columns and rows.
+func newRawD1Server(t *testing.T, columns []string, rows [][]interface{}) *D1Database {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":  true,
+			"errors":   []interface{}{},
+			"messages": []interface{}{},
+			"result": []interface{}{
+				map[string]interface{}{
+					"success": true,
+					"meta":    map[string]interface{}{},
+					"results": map[string]interface{}{"columns": columns, "rows": rows},
+				},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	d, err := NewD1Database("account", "database", "token", option.WithBaseURL(srv.URL), option.WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("NewD1Database: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+
+	return d
+}
+
+func TestD1RowsColumnOrderIsStable(t *testing.T) {
+	columns := []string{"z", "y", "x", "w", "v", "u", "t", "s", "r", "q", "p", "o"}
+	row := make([]interface{}, len(columns))
+	for i := range row {
+		row[i] = float64(i)
+	}
+
+	d := newRawD1Server(t, columns, [][]interface{}{row})
+
+	for i := 0; i < 50; i++ {
+		rows, err := d.Conn().QueryContext(context.Background(), "SELECT 1")
+		if err != nil {
+			t.Fatalf("query: %v", err)
+		}
+
+		got, err := rows.Columns()
+		if err != nil {
+			t.Fatalf("columns: %v", err)
+		}
+		if !reflect.DeepEqual(got, columns) {
+			t.Fatalf("columns = %v, want %v", got, columns)
+		}
+
+		values := make([]int64, len(columns))
+		dest := make([]interface{}, len(columns))
+		for j := range values {
+			dest[j] = &values[j]
+		}
+		if !rows.Next() {
+			t.Fatal("expected a row")
+		}
+		if err := rows.Scan(dest...); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		for j, v := range values {
+			if v != int64(j) {
+				t.Fatalf("column %s = %d, want %d", columns[j], v, j)
+			}
+		}
+		rows.Close()
+	}
+}
+
+func TestD1RowsColumnTypes(t *testing.T) {
+	columns := []string{"id", "score", "name", "data", "flag", "empty", "mixed"}
+	rows := [][]interface{}{
+		{float64(1), 1.5, "a", []interface{}{float64(1), float64(2)}, true, nil, float64(2)},
+		{float64(2), nil, "b", nil, false, nil, 2.5},
+	}
+
+	d := newRawD1Server(t, columns, rows)
+
+	result, err := d.Conn().QueryContext(context.Background(), "SELECT 1")
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer result.Close()
+
+	types, err := result.ColumnTypes()
+	if err != nil {
+		t.Fatalf("column types: %v", err)
+	}
+
+	want := []struct {
+		typeName string
+		nullable bool
+		ok       bool
+	}{
+		{d1TypeInteger, false, false},
+		{d1TypeReal, true, true},
+		{d1TypeText, false, false},
+		{d1TypeBlob, true, true},
+		{d1TypeInteger, false, false},
+		{"", true, true},
+		{d1TypeReal, false, false},
+	}
+
+	for i, ct := range types {
+		if got := ct.DatabaseTypeName(); got != want[i].typeName {
+			t.Errorf("%s: type = %q, want %q", ct.Name(), got, want[i].typeName)
+		}
+		nullable, ok := ct.Nullable()
+		if nullable != want[i].nullable || ok != want[i].ok {
+			t.Errorf("%s: nullable = %v, %v, want %v, %v", ct.Name(), nullable, ok, want[i].nullable, want[i].ok)
+		}
+	}
+}
+
+func TestD1RowsNextConvertsValues(t *testing.T) {
+	columns := []string{"id", "score", "name", "data", "flag", "published_at", "missing"}
+	rows := newD1Rows(columns, [][]interface{}{
+		{float64(42), 0.25, "it's", []interface{}{float64(104), float64(105)}, true, "2024-01-02T03:04:05Z"},
+	})
+
+	dest := make([]driver.Value, len(columns))
+	if err := rows.Next(dest); err != nil {
+		t.Fatalf("next: %v", err)
+	}
+
+	want := []driver.Value{int64(42), 0.25, "it's", []byte("hi"), int64(1), "2024-01-02 03:04:05", nil}
+	if !reflect.DeepEqual(dest, want) {
+		t.Errorf("row = %#v, want %#v", dest, want)
+	}
+
+	if err := rows.Next(dest); err != io.EOF {
+		t.Errorf("second next = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// QueryStats describes one statement sent to D1, taken from the meta block
+// D1 returns with each result. Rows read and written are what D1 bills for.
+type QueryStats struct {
+	Query       string
+	Fingerprint string
+	Write       bool
+
+	// Duration is the time D1 spent executing the statement, excluding the
+	// network round trip.
+	Duration    time.Duration
+	RowsRead    int64
+	RowsWritten int64
+
+	// Err is set when the request carrying the statement failed; the
+	// counters above are then zero.
+	Err error
+}
+
+// QueryObserver is notified after every statement a D1Database executes.
+// Statements committed in one transaction are reported individually.
+type QueryObserver interface {
+	ObserveQuery(ctx context.Context, stats QueryStats)
+}
+
+// AddQueryObserver registers o for subsequent statements. It must not be
+// called concurrently with queries.
+func (d *D1Database) AddQueryObserver(o QueryObserver) {
+	d.observers = append(d.observers, o)
+}
+
+// Usage accumulates the D1 cost of the statements run with a context; see
+// WithUsage.
+type Usage struct {
+	Queries     atomic.Int64
+	RowsRead    atomic.Int64
+	RowsWritten atomic.Int64
+	// Duration is the total D1 execution time in nanoseconds.
+	Duration atomic.Int64
+}
+
+type usageKey struct{}
+
+// UsageKey is the context key under which a *Usage is looked up. Request
+// contexts that can't be wrapped, such as fasthttp's, can store a *Usage
+// under it directly.
+var UsageKey any = usageKey{}
+
+// WithUsage returns a context whose D1 statements are tallied in the
+// returned Usage.
+func WithUsage(ctx context.Context) (context.Context, *Usage) {
+	u := &Usage{}
+	return context.WithValue(ctx, UsageKey, u), u
+}
+
+func (u *Usage) add(s QueryStats) {
+	u.Queries.Add(1)
+	u.RowsRead.Add(s.RowsRead)
+	u.RowsWritten.Add(s.RowsWritten)
+	u.Duration.Add(int64(s.Duration))
+}
+
+// record reports a statement to the context's Usage and the observers.
+func (d *D1Database) record(ctx context.Context, query string, meta QueryResultMeta, err error) {
+	u, _ := ctx.Value(UsageKey).(*Usage)
+	if u == nil && len(d.observers) == 0 {
+		return
+	}
+
+	stats := QueryStats{
+		Query:       query,
+		Fingerprint: Fingerprint(query),
+		Write:       isWriteOperation(query),
+		Duration:    time.Duration(meta.Duration * float64(time.Millisecond)),
+		RowsRead:    int64(meta.RowsRead),
+		RowsWritten: int64(meta.RowsWritten),
+		Err:         err,
+	}
+
+	if u != nil {
+		u.add(stats)
+	}
+	for _, o := range d.observers {
+		o.ObserveQuery(ctx, stats)
+	}
+}
+
+// SlowQueryLog is a QueryObserver that logs statements whose D1 execution
+// time reaches Threshold. Only the fingerprint is logged, so bound values
+// never end up in the log.
+type SlowQueryLog struct {
+	Threshold time.Duration
+	// Logger defaults to the standard logger.
+	Logger *log.Logger
+}
+
+func (l SlowQueryLog) ObserveQuery(ctx context.Context, s QueryStats) {
+	if s.Err != nil || s.Duration < l.Threshold {
+		return
+	}
+
+	logger := l.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	logger.Printf("slow D1 query: %v, rows read %d, rows written %d: %s",
+		s.Duration.Round(time.Microsecond), s.RowsRead, s.RowsWritten, s.Fingerprint)
+}
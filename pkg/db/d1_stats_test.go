@@ -0,0 +1,180 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v6/option"
+)
+
+var testMeta = map[string]interface{}{
+	"changed_db":   true,
+	"changes":      2,
+	"duration":     12.5,
+	"last_row_id":  42,
+	"rows_read":    7,
+	"rows_written": 3,
+	"size_after":   8192,
+}
+
+// newMetaD1Server answers every request with one successful result per
+// statement, each carrying testMeta.
+func newMetaD1Server(t *testing.T) *D1Database {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Batch []json.RawMessage `json:"batch"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		result := []interface{}{}
+		for range max(len(body.Batch), 1) {
+			result = append(result, map[string]interface{}{
+				"success": true,
+				"meta":    testMeta,
+				"results": map[string]interface{}{"columns": []string{"n"}, "rows": [][]interface{}{{1}}},
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":  true,
+			"errors":   []interface{}{},
+			"messages": []interface{}{},
+			"result":   result,
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	d, err := NewD1Database("account", "database", "token", option.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewD1Database: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+
+	return d
+}
+
+type recordingObserver struct {
+	mu    sync.Mutex
+	stats []QueryStats
+}
+
+func (o *recordingObserver) ObserveQuery(ctx context.Context, s QueryStats) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.stats = append(o.stats, s)
+}
+
+func TestD1MetaIsDecoded(t *testing.T) {
+	d := newMetaD1Server(t)
+
+	result, err := d.executeQuery(context.Background(), "SELECT 1")
+	if err != nil {
+		t.Fatalf("executeQuery: %v", err)
+	}
+
+	want := QueryResultMeta{
+		ChangedDB:   true,
+		Changes:     2,
+		Duration:    12.5,
+		LastRowID:   42,
+		RowsRead:    7,
+		RowsWritten: 3,
+		SizeAfter:   8192,
+	}
+	if result.Meta != want {
+		t.Fatalf("meta = %+v, want %+v", result.Meta, want)
+	}
+
+	res, err := d.Conn().ExecContext(context.Background(), "UPDATE t SET a = ?", 1)
+	if err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if id, _ := res.LastInsertId(); id != 42 {
+		t.Fatalf("LastInsertId = %d, want 42", id)
+	}
+	if n, _ := res.RowsAffected(); n != 2 {
+		t.Fatalf("RowsAffected = %d, want 2", n)
+	}
+}
+
+func TestD1QueryObserverAndUsage(t *testing.T) {
+	d := newMetaD1Server(t)
+	observer := &recordingObserver{}
+	d.AddQueryObserver(observer)
+
+	ctx, usage := WithUsage(context.Background())
+
+	rows, err := d.Conn().QueryContext(ctx, "SELECT n FROM t WHERE id IN (?, ?)", 1, 2)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	rows.Close()
+
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	tx.ExecContext(ctx, "INSERT INTO t (a) VALUES (?)", 1)
+	tx.ExecContext(ctx, "DELETE FROM t WHERE a = 'x'")
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if len(observer.stats) != 3 {
+		t.Fatalf("observed %d statements, want 3", len(observer.stats))
+	}
+
+	first := observer.stats[0]
+	if first.Fingerprint != "SELECT n FROM t WHERE id IN (?, ...)" || first.Write {
+		t.Fatalf("first = %+v", first)
+	}
+	if first.Duration != 12500*time.Microsecond || first.RowsRead != 7 || first.RowsWritten != 3 {
+		t.Fatalf("first = %+v", first)
+	}
+	if last := observer.stats[2]; last.Fingerprint != "DELETE FROM t WHERE a = ?" || !last.Write {
+		t.Fatalf("last = %+v", last)
+	}
+
+	if usage.Queries.Load() != 3 || usage.RowsRead.Load() != 21 || usage.RowsWritten.Load() != 9 {
+		t.Fatalf("usage = %d queries, %d read, %d written",
+			usage.Queries.Load(), usage.RowsRead.Load(), usage.RowsWritten.Load())
+	}
+	if time.Duration(usage.Duration.Load()) != 3*12500*time.Microsecond {
+		t.Fatalf("usage duration = %v", time.Duration(usage.Duration.Load()))
+	}
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	var buf bytes.Buffer
+	slow := SlowQueryLog{Threshold: 10 * time.Millisecond, Logger: log.New(&buf, "", 0)}
+
+	slow.ObserveQuery(context.Background(), QueryStats{Fingerprint: "SELECT ?", Duration: 5 * time.Millisecond})
+	if buf.Len() != 0 {
+		t.Fatalf("logged a fast query: %q", buf.String())
+	}
+
+	slow.ObserveQuery(context.Background(), QueryStats{
+		Query:       "SELECT 'secret'",
+		Fingerprint: "SELECT ?",
+		Duration:    25 * time.Millisecond,
+		RowsRead:    1000,
+	})
+	line := buf.String()
+	if !strings.Contains(line, "25ms") || !strings.Contains(line, "rows read 1000") || !strings.Contains(line, "SELECT ?") {
+		t.Fatalf("log = %q", line)
+	}
+	if strings.Contains(line, "secret") {
+		t.Fatalf("log leaks literal values: %q", line)
+	}
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrResultNotReady is returned by results of statements executed inside a
+// D1 transaction, since they only run on Commit.
+var ErrResultNotReady = errors.New("result not available until the transaction commits")
+
+var (
+	errTxDone          = errors.New("transaction has already been committed or rolled back")
+	errTxReadOnly      = errors.New("cannot write in a read-only transaction")
+	errShortBatchReply = errors.New("D1 batch returned fewer results than statements")
+	errNoResults       = errors.New("no results returned from D1")
+)
+
+// d1Tx is a transaction on top of the D1 REST API, which has no interactive
+// transactions. Statements executed through the transaction are buffered and
+// sent as a single batch request on Commit; D1 runs a batch atomically, so
+// either every statement lands or none do. Rollback simply discards the
+// buffer.
+//
+// Because nothing is sent before Commit:
+//   - queries inside the transaction read committed data only and do NOT see
+//     the transaction's own pending writes (no read-your-writes);
+//   - Exec results are placeholders whose LastInsertId and RowsAffected return
+//     ErrResultNotReady, so generate keys client-side rather than relying on
+//     AUTOINCREMENT ids mid-transaction.
+type d1Tx struct {
+	conn       *d1Conn
+	ctx        context.Context
+	readOnly   bool
+	statements []d1Statement
+	done       bool
+}
+
+// d1Statement is a single statement waiting to be sent in a batch.
+type d1Statement struct {
+	query string
+	args  []any
+}
+
+type d1PendingResult struct{}
+
+func (d1PendingResult) LastInsertId() (int64, error) {
+	return 0, ErrResultNotReady
+}
+
+func (d1PendingResult) RowsAffected() (int64, error) {
+	return 0, ErrResultNotReady
+}
+
+func (tx *d1Tx) queue(query string, args []any) (driver.Result, error) {
+	if tx.done {
+		return nil, errTxDone
+	}
+	if tx.readOnly {
+		return nil, errTxReadOnly
+	}
+
+	tx.statements = append(tx.statements, d1Statement{query: query, args: args})
+	return d1PendingResult{}, nil
+}
+
+func (tx *d1Tx) Commit() error {
+	if tx.done {
+		return errTxDone
+	}
+	tx.finish()
+
+	if len(tx.statements) == 0 {
+		return nil
+	}
+
+	_, err := tx.conn.database.executeBatch(tx.ctx, tx.statements)
+	return err
+}
+
+func (tx *d1Tx) Rollback() error {
+	if tx.done {
+		return errTxDone
+	}
+	tx.finish()
+	tx.statements = nil
+	return nil
+}
+
+func (tx *d1Tx) finish() {
+	tx.done = true
+	tx.conn.tx = nil
+}
+
+type d1BatchRequest struct {
+	Batch []d1Query `json:"batch"`
+}
+
+// executeBatch sends statements to D1 as one atomic batch and returns one
+// result per statement.
+func (d *D1Database) executeBatch(ctx context.Context, statements []d1Statement) ([]QueryResult, error) {
+	results, err := d.sendBatch(ctx, statements)
+	if err != nil {
+		for _, s := range statements {
+			d.record(ctx, s.query, QueryResultMeta{}, err)
+		}
+		return nil, err
+	}
+
+	for i, s := range statements {
+		d.record(ctx, s.query, results[i].Meta, nil)
+	}
+
+	return results, nil
+}
+
+func (d *D1Database) sendBatch(ctx context.Context, statements []d1Statement) ([]QueryResult, error) {
+	// Foreign keys are checked when the batch commits, so statements may be
+	// queued in any order.
+	batch := make([]d1Query, 0, len(statements)+1)
+	batch = append(batch, d1Query{Sql: deferForeignKeysPragma})
+	for i, s := range statements {
+		q, err := newD1Query(s.query, s.args)
+		if err != nil {
+			return nil, fmt.Errorf("batch statement %d: %w", i+1, err)
+		}
+		batch = append(batch, q)
+	}
+
+	body, err := json.Marshal(d1BatchRequest{Batch: batch})
+	if err != nil {
+		return nil, fmt.Errorf("encode D1 batch: %w", err)
+	}
+
+	raw, err := d.raw(ctx, body, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) < len(batch) {
+		return nil, errShortBatchReply
+	}
+
+	results := make([]QueryResult, 0, len(statements))
+	for i, r := range raw[1:] {
+		if !r.Success {
+			return nil, &Error{Message: fmt.Sprintf("batch statement %d failed", i+1)}
+		}
+		results = append(results, *convertD1RawResult(r))
+	}
+
+	return results, nil
+}
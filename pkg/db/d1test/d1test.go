@@ -0,0 +1,452 @@
+// Package d1test provides an in-process stand-in for the Cloudflare D1 REST
+// API, backed by an in-memory SQLite database, for use in tests.
+//
+// Only the parts of the API used by db.D1Database are implemented: the
+// /query and /raw endpoints, with either a single {"sql", "params"} body or
+// a {"batch": [...]} body. A batch runs in one transaction, as on D1, and
+// a statement may bind at most 100 parameters, as on D1.
+// Server.Fail injects API errors to exercise retries and error handling.
+package d1test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v6/option"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/K0ng2/zeedzad/db"
+	"github.com/K0ng2/zeedzad/migrations"
+)
+
+const (
+	AccountID  = "d1test-account"
+	DatabaseID = "d1test-database"
+	APIToken   = "d1test-token"
+
+	// errorCodeQuery is the code D1 uses for SQL errors.
+	errorCodeQuery = 7500
+
+	// maxParams is the most parameters D1 binds to a statement.
+	maxParams = 100
+)
+
+// Server is a fake D1 API. Its SQLite database starts with every migration
+// applied.
+type Server struct {
+	*httptest.Server
+
+	// SQLite is the backing database. Tests may use it directly to seed
+	// data or inspect results.
+	SQLite *db.SQLiteDatabase
+
+	mu sync.Mutex
+
+	faultMu  sync.Mutex
+	requests int
+	faults   []fault
+}
+
+type fault struct {
+	status  int
+	code    int
+	message string
+}
+
+// NewServer starts a fake D1 API that is shut down when the test ends.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+
+	sqlite, err := db.NewSQLiteDatabase(":memory:")
+	if err != nil {
+		tb.Fatalf("d1test: open sqlite: %v", err)
+	}
+
+	migrator, err := migrations.New(sqlite)
+	if err != nil {
+		tb.Fatalf("d1test: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		tb.Fatalf("d1test: %v", err)
+	}
+
+	s := &Server{SQLite: sqlite}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	tb.Cleanup(func() {
+		s.Close()
+		sqlite.Close()
+	})
+
+	return s
+}
+
+// ClientOptions returns options that point a Cloudflare client at the
+// server.
+func (s *Server) ClientOptions() []option.RequestOption {
+	return []option.RequestOption{
+		option.WithBaseURL(s.URL),
+		option.WithMaxRetries(0),
+	}
+}
+
+// NewDatabase returns a db.D1Database talking to the server.
+func (s *Server) NewDatabase(tb testing.TB) *db.D1Database {
+	tb.Helper()
+
+	database, err := db.NewD1Database(AccountID, DatabaseID, APIToken, s.ClientOptions()...)
+	if err != nil {
+		tb.Fatalf("d1test: %v", err)
+	}
+	tb.Cleanup(func() { database.Close() })
+
+	return database
+}
+
+type query struct {
+	Sql    string            `json:"sql"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type request struct {
+	query
+	Batch []query `json:"batch"`
+}
+
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type envelope struct {
+	Success  bool       `json:"success"`
+	Errors   []apiError `json:"errors"`
+	Messages []string   `json:"messages"`
+	Result   any        `json:"result"`
+}
+
+type meta struct {
+	ChangedDB       bool    `json:"changed_db"`
+	Changes         int64   `json:"changes"`
+	Duration        float64 `json:"duration"`
+	LastRowID       int64   `json:"last_row_id"`
+	RowsRead        int64   `json:"rows_read"`
+	RowsWritten     int64   `json:"rows_written"`
+	ServedByPrimary bool    `json:"served_by_primary"`
+	SizeAfter       int64   `json:"size_after"`
+}
+
+type statementResult struct {
+	columns []string
+	rows    [][]any
+	meta    meta
+}
+
+// Fail makes the next n requests fail with the given HTTP status, D1 error
+// code and message without touching the database. A 429 response carries
+// "Retry-After: 0".
+func (s *Server) Fail(n, status, code int, message string) {
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+
+	for range n {
+		s.faults = append(s.faults, fault{status: status, code: code, message: message})
+	}
+}
+
+// Requests returns the number of API requests the server has received.
+func (s *Server) Requests() int {
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+
+	return s.requests
+}
+
+// nextFault counts a request and returns the fault queued for it, if any.
+func (s *Server) nextFault() (fault, bool) {
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+
+	s.requests++
+	if len(s.faults) == 0 {
+		return fault{}, false
+	}
+	f := s.faults[0]
+	s.faults = s.faults[1:]
+	return f, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if f, ok := s.nextFault(); ok {
+		if f.status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		writeError(w, f.status, f.code, f.message)
+		return
+	}
+
+	prefix := fmt.Sprintf("/accounts/%s/d1/database/%s/", AccountID, DatabaseID)
+	endpoint, found := strings.CutPrefix(r.URL.Path, prefix)
+	if !found || r.Method != http.MethodPost || (endpoint != "query" && endpoint != "raw") {
+		writeError(w, http.StatusNotFound, 7404, "not found")
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+APIToken {
+		writeError(w, http.StatusUnauthorized, 10000, "Authentication error")
+		return
+	}
+
+	var req request
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, 7400, "invalid request body: "+err.Error())
+		return
+	}
+
+	queries := req.Batch
+	if queries == nil {
+		queries = []query{req.query}
+	}
+
+	results, err := s.execute(r.Context(), queries)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorCodeQuery, err.Error())
+		return
+	}
+
+	formatted := make([]any, len(results))
+	for i, res := range results {
+		formatted[i] = formatResult(endpoint, res)
+	}
+
+	writeJSON(w, http.StatusOK, envelope{
+		Success:  true,
+		Errors:   []apiError{},
+		Messages: []string{},
+		Result:   formatted,
+	})
+}
+
+// execute runs queries in a single transaction, rolling everything back if
+// any of them fails.
+func (s *Server) execute(ctx context.Context, queries []query) ([]statementResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conn, err := s.SQLite.Conn().Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]statementResult, 0, len(queries))
+	for _, q := range queries {
+		res, err := executeStatement(ctx, tx, q)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %s", err, resultCodeName(err))
+		}
+		results = append(results, res)
+	}
+
+	if err := tx.Commit(); err != nil {
+		// A COMMIT that fails a deferred foreign key check leaves the
+		// transaction open, which database/sql no longer knows about.
+		conn.ExecContext(ctx, "ROLLBACK")
+		return nil, fmt.Errorf("%v: %s", err, resultCodeName(err))
+	}
+
+	return results, nil
+}
+
+func executeStatement(ctx context.Context, tx *sql.Tx, q query) (statementResult, error) {
+	if len(q.Params) > maxParams {
+		return statementResult{}, fmt.Errorf("too many SQL variables: %d bound, at most %d allowed", len(q.Params), maxParams)
+	}
+
+	args, err := decodeParams(q.Params)
+	if err != nil {
+		return statementResult{}, err
+	}
+
+	start := time.Now()
+
+	rows, err := tx.QueryContext(ctx, q.Sql, args...)
+	if err != nil {
+		return statementResult{}, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return statementResult{}, err
+	}
+
+	res := statementResult{columns: columns, rows: [][]any{}}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return statementResult{}, err
+		}
+		for i, v := range values {
+			values[i] = encodeValue(v)
+		}
+		res.rows = append(res.rows, values)
+	}
+	if err := rows.Close(); err != nil {
+		return statementResult{}, err
+	}
+	if err := rows.Err(); err != nil {
+		return statementResult{}, err
+	}
+
+	res.meta.Duration = float64(time.Since(start).Microseconds()) / 1000
+	res.meta.RowsRead = int64(len(res.rows))
+	res.meta.ServedByPrimary = true
+
+	if err := tx.QueryRowContext(ctx, "SELECT changes(), last_insert_rowid()").Scan(&res.meta.Changes, &res.meta.LastRowID); err != nil {
+		return statementResult{}, err
+	}
+	if isWrite(q.Sql) {
+		res.meta.ChangedDB = res.meta.Changes > 0
+		res.meta.RowsWritten = res.meta.Changes
+	} else {
+		res.meta.Changes = 0
+	}
+
+	return res, nil
+}
+
+// resultCodeName returns the SQLite result code D1 appends to error
+// messages, e.g. "SQLITE_CONSTRAINT".
+func resultCodeName(err error) string {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_CONSTRAINT {
+		return "SQLITE_CONSTRAINT"
+	}
+	return "SQLITE_ERROR"
+}
+
+func isWrite(sql string) bool {
+	upper := strings.ToUpper(strings.TrimSpace(sql))
+	return strings.HasPrefix(upper, "INSERT") ||
+		strings.HasPrefix(upper, "UPDATE") ||
+		strings.HasPrefix(upper, "DELETE") ||
+		strings.HasPrefix(upper, "REPLACE")
+}
+
+// decodeParams converts JSON params the way D1 binds them: integral numbers
+// as INTEGER, other numbers as REAL, and strings, booleans and null as-is.
+func decodeParams(raw []json.RawMessage) ([]any, error) {
+	args := make([]any, len(raw))
+	for i, p := range raw {
+		decoder := json.NewDecoder(strings.NewReader(string(p)))
+		decoder.UseNumber()
+
+		var v any
+		if err := decoder.Decode(&v); err != nil {
+			return nil, fmt.Errorf("param %d: %w", i+1, err)
+		}
+
+		switch val := v.(type) {
+		case json.Number:
+			if n, err := val.Int64(); err == nil {
+				args[i] = n
+			} else if f, err := val.Float64(); err == nil {
+				args[i] = f
+			} else {
+				return nil, fmt.Errorf("param %d: %w", i+1, err)
+			}
+		case bool:
+			if val {
+				args[i] = int64(1)
+			} else {
+				args[i] = int64(0)
+			}
+		case nil, string:
+			args[i] = val
+		default:
+			return nil, fmt.Errorf("param %d: unsupported type %T", i+1, v)
+		}
+	}
+	return args, nil
+}
+
+// encodeValue converts a SQLite value to its D1 JSON form. D1 returns the
+// stored text of DATETIME columns, and blobs as arrays of byte values.
+func encodeValue(v any) any {
+	switch val := v.(type) {
+	case time.Time:
+		return val.UTC().Format("2006-01-02 15:04:05.999999999")
+	case []byte:
+		bytes := make([]int, len(val))
+		for i, b := range val {
+			bytes[i] = int(b)
+		}
+		return bytes
+	default:
+		return val
+	}
+}
+
+func formatResult(endpoint string, res statementResult) map[string]any {
+	out := map[string]any{
+		"success": true,
+		"meta":    res.meta,
+	}
+
+	if endpoint == "raw" {
+		out["results"] = map[string]any{
+			"columns": res.columns,
+			"rows":    res.rows,
+		}
+		return out
+	}
+
+	objects := make([]map[string]any, len(res.rows))
+	for i, row := range res.rows {
+		obj := make(map[string]any, len(res.columns))
+		for j, col := range res.columns {
+			obj[col] = row[j]
+		}
+		objects[i] = obj
+	}
+	out["results"] = objects
+	return out
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, envelope{
+		Success:  false,
+		Errors:   []apiError{{Code: code, Message: message}},
+		Messages: []string{},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,87 @@
+package d1test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func post(t *testing.T, srv *Server, endpoint, token, body string) (*http.Response, envelope) {
+	t.Helper()
+
+	url := srv.URL + "/accounts/" + AccountID + "/d1/database/" + DatabaseID + "/" + endpoint
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return resp, env
+}
+
+func TestQueryReturnsObjects(t *testing.T) {
+	srv := NewServer(t)
+
+	_, env := post(t, srv, "query", APIToken, `{"sql": "SELECT ? AS n, ? AS s, ? AS f, ? AS z", "params": [1, "a", 1.5, null]}`)
+	if !env.Success {
+		t.Fatalf("errors = %+v", env.Errors)
+	}
+
+	results := env.Result.([]any)
+	rows := results[0].(map[string]any)["results"].([]any)
+	row := rows[0].(map[string]any)
+	if row["n"] != float64(1) || row["s"] != "a" || row["f"] != 1.5 || row["z"] != nil {
+		t.Fatalf("row = %+v", row)
+	}
+}
+
+func TestRejectsBadToken(t *testing.T) {
+	srv := NewServer(t)
+
+	resp, env := post(t, srv, "raw", "wrong", `{"sql": "SELECT 1"}`)
+	if resp.StatusCode != http.StatusUnauthorized || env.Success {
+		t.Fatalf("status = %d, success = %v", resp.StatusCode, env.Success)
+	}
+}
+
+func TestBatchIsAtomic(t *testing.T) {
+	srv := NewServer(t)
+
+	resp, env := post(t, srv, "raw", APIToken, `{"batch": [
+		{"sql": "INSERT INTO games (id, name, url) VALUES (?, ?, ?)", "params": [1, "a", "u"]},
+		{"sql": "INSERT INTO games (id, name, url) VALUES (?, ?, ?)", "params": [1, "a", "u"]}
+	]}`)
+	if resp.StatusCode != http.StatusBadRequest || env.Success || env.Errors[0].Code != errorCodeQuery {
+		t.Fatalf("status = %d, envelope = %+v", resp.StatusCode, env)
+	}
+
+	var count int
+	if err := srv.SQLite.Conn().QueryRow("SELECT COUNT(*) FROM games").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatalf("count = %d, want 0", count)
+	}
+}
+
+func TestRejectsTooManyParams(t *testing.T) {
+	srv := NewServer(t)
+
+	params := strings.Repeat("1, ", maxParams) + "1"
+	placeholders := strings.Repeat("?, ", maxParams) + "?"
+	resp, env := post(t, srv, "raw", APIToken, `{"sql": "SELECT id FROM games WHERE id IN (`+placeholders+`)", "params": [`+params+`]}`)
+	if resp.StatusCode != http.StatusBadRequest || env.Success || env.Errors[0].Code != errorCodeQuery {
+		t.Fatalf("status = %d, envelope = %+v", resp.StatusCode, env)
+	}
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v6"
+)
+
+// Error kinds. Backend failures are reported as *Error values whose Kind is
+// one of these, so callers can match them with errors.Is.
+var (
+	// ErrNotFound means the requested row or database does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConstraint means a UNIQUE, FOREIGN KEY, NOT NULL or CHECK
+	// constraint rejected a write.
+	ErrConstraint = errors.New("constraint violation")
+	// ErrRateLimited means the backend refused the request because too many
+	// were sent. The request was not executed.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrTransient means the request failed for a reason that may go away on
+	// its own, such as a dropped connection or an overloaded server. A write
+	// that fails this way may or may not have been applied.
+	ErrTransient = errors.New("transient failure")
+	// ErrAuth means the backend rejected the configured credentials.
+	ErrAuth = errors.New("authentication failed")
+)
+
+// D1 API error codes.
+const (
+	d1CodeQuery    = 7500
+	d1CodeNotFound = 7404
+	d1CodeAuth     = 10000
+)
+
+// Error is a failure reported by a database backend.
+type Error struct {
+	// Kind is one of the sentinel errors above, or nil if the failure does
+	// not fit any of them (e.g. a SQL syntax error).
+	Kind error
+	// StatusCode and Code are the HTTP status and D1 error code of the
+	// response, if there was one.
+	StatusCode int
+	Code       int
+	Message    string
+	// RetryAfter is how long the backend asked us to wait, if it said.
+	RetryAfter time.Duration
+
+	err error
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" && e.err != nil {
+		msg = e.err.Error()
+	}
+	if e.Kind != nil {
+		return fmt.Sprintf("D1 %v: %s", e.Kind, msg)
+	}
+	return "D1: " + msg
+}
+
+func (e *Error) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.err != nil {
+		errs = append(errs, e.err)
+	}
+	return errs
+}
+
+// Messages D1 returns for failures that Cloudflare documents as safe to
+// retry.
+var d1TransientMessages = []string{
+	"network connection lost",
+	"overloaded",
+	"storage caused object to be reset",
+	"reset because its code was updated",
+	"internal error",
+}
+
+// classifyD1Error converts an error from the Cloudflare client into an
+// *Error. Context cancellation and errors that did not come from a request
+// are returned unchanged.
+func classifyD1Error(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	var apiErr *cloudflare.Error
+	if errors.As(err, &apiErr) {
+		return classifyD1APIError(apiErr)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return &Error{Kind: ErrTransient, err: err}
+	}
+
+	return err
+}
+
+func classifyD1APIError(apiErr *cloudflare.Error) *Error {
+	e := &Error{StatusCode: apiErr.StatusCode, err: apiErr}
+	if len(apiErr.Errors) > 0 {
+		e.Code = int(apiErr.Errors[0].Code)
+		e.Message = apiErr.Errors[0].Message
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(apiErr.StatusCode)
+	}
+	if apiErr.Response != nil {
+		e.RetryAfter = parseRetryAfter(apiErr.Response.Header.Get("Retry-After"))
+	}
+
+	lower := strings.ToLower(e.Message)
+
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests:
+		e.Kind = ErrRateLimited
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden || e.Code == d1CodeAuth:
+		e.Kind = ErrAuth
+	case e.StatusCode == http.StatusNotFound || e.Code == d1CodeNotFound:
+		e.Kind = ErrNotFound
+	case strings.Contains(lower, "constraint failed") || strings.Contains(e.Message, "SQLITE_CONSTRAINT"):
+		e.Kind = ErrConstraint
+	case e.StatusCode == http.StatusRequestTimeout || e.StatusCode >= http.StatusInternalServerError:
+		e.Kind = ErrTransient
+	case e.Code == d1CodeQuery && containsAny(lower, d1TransientMessages):
+		e.Kind = ErrTransient
+	}
+
+	return e
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
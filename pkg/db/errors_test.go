@@ -0,0 +1,234 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v6/option"
+)
+
+type d1Reply struct {
+	status  int
+	code    int
+	message string
+}
+
+// newScriptedD1Server answers requests with the given replies in order, then
+// with successful empty results. It returns the database and a request
+// counter.
+func newScriptedD1Server(t *testing.T, replies ...d1Reply) (*D1Database, *atomic.Int32) {
+	t.Helper()
+
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(count.Add(1))
+		w.Header().Set("Content-Type", "application/json")
+
+		if n <= len(replies) {
+			reply := replies[n-1]
+			if reply.status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			w.WriteHeader(reply.status)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success":  false,
+				"errors":   []interface{}{map[string]interface{}{"code": reply.code, "message": reply.message}},
+				"messages": []interface{}{},
+			})
+			return
+		}
+
+		var body struct {
+			Batch []json.RawMessage `json:"batch"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		result := []interface{}{}
+		for range max(len(body.Batch), 1) {
+			result = append(result, map[string]interface{}{
+				"success": true,
+				"meta":    map[string]interface{}{},
+				"results": map[string]interface{}{"columns": []string{}, "rows": [][]interface{}{}},
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":  true,
+			"errors":   []interface{}{},
+			"messages": []interface{}{},
+			"result":   result,
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	d, err := NewD1Database("account", "database", "token", option.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("NewD1Database: %v", err)
+	}
+	d.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+	t.Cleanup(func() { d.Close() })
+
+	return d, &count
+}
+
+func TestD1ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply d1Reply
+		kind  error
+	}{
+		{"unique", d1Reply{400, 7500, "UNIQUE constraint failed: games.id: SQLITE_CONSTRAINT"}, ErrConstraint},
+		{"foreign key", d1Reply{400, 7500, "FOREIGN KEY constraint failed: SQLITE_CONSTRAINT"}, ErrConstraint},
+		{"database missing", d1Reply{404, 7404, "Couldn't find a D1 DB with the name or binding 'x'"}, ErrNotFound},
+		{"bad token", d1Reply{401, 10000, "Authentication error"}, ErrAuth},
+		{"forbidden", d1Reply{403, 10000, "Authentication error"}, ErrAuth},
+		{"rate limited", d1Reply{429, 971, "Please wait and consider throttling your request speed"}, ErrRateLimited},
+		{"server error", d1Reply{502, 0, "Bad Gateway"}, ErrTransient},
+		{"connection lost", d1Reply{400, 7500, "D1_ERROR: Network connection lost."}, ErrTransient},
+		{"syntax", d1Reply{400, 7500, "near \"SELEC\": syntax error: SQLITE_ERROR"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Writes are only retried when rate limited, so queue enough
+			// failures to exhaust the policy either way.
+			d, _ := newScriptedD1Server(t, tt.reply, tt.reply, tt.reply)
+
+			_, err := d.Conn().ExecContext(context.Background(), "INSERT INTO t VALUES (1)")
+
+			var dbErr *Error
+			if !errors.As(err, &dbErr) {
+				t.Fatalf("err = %v (%T), want *Error", err, err)
+			}
+			if dbErr.Kind != tt.kind {
+				t.Fatalf("kind = %v, want %v", dbErr.Kind, tt.kind)
+			}
+			if tt.kind != nil && !errors.Is(err, tt.kind) {
+				t.Fatalf("errors.Is(%v, %v) = false", err, tt.kind)
+			}
+			if dbErr.Code != tt.reply.code || dbErr.StatusCode != tt.reply.status {
+				t.Fatalf("code = %d, status = %d", dbErr.Code, dbErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestD1RetriesTransientReads(t *testing.T) {
+	d, count := newScriptedD1Server(t, d1Reply{503, 0, "unavailable"}, d1Reply{400, 7500, "D1 DB is overloaded. Too many requests queued."})
+
+	rows, err := d.Conn().QueryContext(context.Background(), "SELECT 1")
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	rows.Close()
+
+	if got := count.Load(); got != 3 {
+		t.Fatalf("requests = %d, want 3", got)
+	}
+}
+
+func TestD1DoesNotRetryTransientWrites(t *testing.T) {
+	d, count := newScriptedD1Server(t, d1Reply{503, 0, "unavailable"})
+
+	_, err := d.Conn().ExecContext(context.Background(), "INSERT INTO t VALUES (1)")
+	if !errors.Is(err, ErrTransient) {
+		t.Fatalf("err = %v, want ErrTransient", err)
+	}
+
+	if got := count.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+}
+
+func TestIsWriteOperation(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT 1", false},
+		{"  insert INTO t VALUES (1)", true},
+		{"REPLACE INTO t VALUES (1)", true},
+		{"WITH page (id) AS (VALUES (?), (?)) INSERT INTO t SELECT id FROM page ON CONFLICT DO NOTHING RETURNING id", true},
+		{"WITH RECURSIVE n AS (SELECT 1 UNION ALL SELECT x + 1 FROM n) DELETE FROM t WHERE id IN (SELECT * FROM n)", true},
+		{"WITH 'update' AS (SELECT 'delete') SELECT * FROM \"update\"", false},
+		{"-- INSERT\nWITH x AS (SELECT 1) /* UPDATE */ SELECT * FROM x", false},
+	}
+
+	for _, tt := range tests {
+		if got := isWriteOperation(tt.query); got != tt.want {
+			t.Errorf("isWriteOperation(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestD1RetriesRateLimitedWrites(t *testing.T) {
+	d, count := newScriptedD1Server(t, d1Reply{429, 971, "slow down"})
+
+	if _, err := d.Conn().ExecContext(context.Background(), "INSERT INTO t VALUES (1)"); err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+
+	if got := count.Load(); got != 2 {
+		t.Fatalf("requests = %d, want 2", got)
+	}
+}
+
+func TestD1RetryGivesUp(t *testing.T) {
+	reply := d1Reply{503, 0, "unavailable"}
+	d, count := newScriptedD1Server(t, reply, reply, reply, reply)
+
+	_, err := d.Conn().QueryContext(context.Background(), "SELECT 1")
+	if !errors.Is(err, ErrTransient) {
+		t.Fatalf("err = %v, want ErrTransient", err)
+	}
+
+	if got := count.Load(); got != 3 {
+		t.Fatalf("requests = %d, want 3", got)
+	}
+}
+
+func TestD1RetryStopsWhenContextDone(t *testing.T) {
+	reply := d1Reply{503, 0, "unavailable"}
+	d, count := newScriptedD1Server(t, reply, reply, reply)
+	d.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := d.Conn().QueryContext(ctx, "SELECT 1")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if got := count.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		bound := min(p.BaseDelay<<(attempt-1), p.MaxDelay)
+		for range 100 {
+			if d := p.delay(attempt, errors.New("x")); d < 0 || d > bound {
+				t.Fatalf("attempt %d: delay %v outside [0, %v]", attempt, d, bound)
+			}
+		}
+	}
+
+	hinted := &Error{Kind: ErrRateLimited, RetryAfter: 30 * time.Millisecond}
+	if d := p.delay(1, hinted); d < 30*time.Millisecond {
+		t.Fatalf("delay %v ignores Retry-After", d)
+	}
+
+	hinted.RetryAfter = time.Minute
+	if d := p.delay(1, hinted); d != p.MaxDelay {
+		t.Fatalf("delay %v not capped at MaxDelay", d)
+	}
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	placeholderList = regexp.MustCompile(`\?(?:\s*,\s*\?)+`)
+	valuesList      = regexp.MustCompile(`(\(\?(?:, \.\.\.)?\))(?:\s*,\s*\(\?(?:, \.\.\.)?\))+`)
+)
+
+// Fingerprint normalizes query so that statements differing only in literal
+// values, whitespace, comments or the length of a placeholder list share one
+// fingerprint:
+//
+//	SELECT * FROM t WHERE id IN (1, 2, 3) AND name = 'x'
+//	SELECT * FROM t WHERE id IN (?, ...) AND name = ?
+//
+// Quoted identifiers are kept as they are.
+func Fingerprint(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	space := false
+	emit := func(s string) {
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteString(s)
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			i = skipQuoted(query, i, '\'')
+			emit("?")
+		case c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end := min(skipQuoted(query, i, closing), len(query)-1)
+			emit(query[i : end+1])
+			i = end
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(query)
+			}
+			space = true
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(query)
+			}
+			space = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+		case c >= '0' && c <= '9' && (i == 0 || !isIdentByte(query[i-1])):
+			for i+1 < len(query) && (isIdentByte(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			emit("?")
+		default:
+			emit(query[i : i+1])
+		}
+	}
+
+	fp := placeholderList.ReplaceAllString(b.String(), "?, ...")
+	return valuesList.ReplaceAllString(fp, "$1, ...")
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
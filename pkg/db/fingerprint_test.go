@@ -0,0 +1,50 @@
+package db
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			query: "SELECT * FROM t WHERE id IN (1, 2, 3) AND name = 'it''s'",
+			want:  "SELECT * FROM t WHERE id IN (?, ...) AND name = ?",
+		},
+		{
+			query: "SELECT  *\n\tFROM t -- trailing comment\nWHERE /* inline */ x = 1.5e3",
+			want:  "SELECT * FROM t WHERE x = ?",
+		},
+		{
+			query: "SELECT col1, \"weird 1\" FROM t2 WHERE a = ?",
+			want:  "SELECT col1, \"weird 1\" FROM t2 WHERE a = ?",
+		},
+		{
+			query: "INSERT INTO t (a, b) VALUES (?, ?), (?, ?), (?, ?)",
+			want:  "INSERT INTO t (a, b) VALUES (?, ...), ...",
+		},
+		{
+			query: "UPDATE t SET n = ? WHERE id = ?",
+			want:  "UPDATE t SET n = ? WHERE id = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Fingerprint(tt.query); got != tt.want {
+			t.Errorf("Fingerprint(%q)\n got %q\nwant %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFingerprintGroupsListLengths(t *testing.T) {
+	a := Fingerprint("SELECT * FROM t WHERE id IN (?)")
+	b := Fingerprint("SELECT * FROM t WHERE id IN (?, ?, ?, ?)")
+	c := Fingerprint("SELECT * FROM t WHERE id IN (?, ?)")
+
+	if b != c {
+		t.Fatalf("%q != %q", b, c)
+	}
+	if a == "" {
+		t.Fatal("empty fingerprint")
+	}
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteMemoryPath = ":memory:"
+
+// SQLiteDatabase stores data in a local SQLite file using the pure Go
+// modernc.org/sqlite driver, so no Cloudflare account is needed.
+type SQLiteDatabase struct {
+	db *sql.DB
+}
+
+// NewSQLiteDatabase opens (creating if necessary) the SQLite database at path.
+// Pass ":memory:" for a throwaway database. The schema is managed by the
+// migrations package.
+func NewSQLiteDatabase(path string) (*SQLiteDatabase, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite path is required")
+	}
+
+	conn, err := sql.Open("sqlite", sqliteDSN(path))
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	// Every connection to :memory: gets its own empty database, so pin the
+	// pool to a single connection.
+	if path == sqliteMemoryPath {
+		conn.SetMaxOpenConns(1)
+	}
+
+	return &SQLiteDatabase{db: conn}, nil
+}
+
+func sqliteDSN(path string) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	if path != sqliteMemoryPath {
+		params.Add("_pragma", "journal_mode(WAL)")
+	}
+	params.Set("_time_format", "sqlite")
+
+	return "file:" + path + "?" + params.Encode()
+}
+
+func (d *SQLiteDatabase) Close() error {
+	return d.db.Close()
+}
+
+func (d *SQLiteDatabase) Conn() *sql.DB {
+	return d.db
+}
+
+func (d *SQLiteDatabase) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return d.db.BeginTx(ctx, opts)
+}
+
+func (d *SQLiteDatabase) PingContext(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
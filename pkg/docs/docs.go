@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/databasez": {
+        "/admin/quota": {
             "get": {
-                "description": "Check the Database health status",
+                "description": "Get the YouTube Data API units spent today, by endpoint, against the daily budget. Days start at midnight Pacific time, when YouTube resets the quota. With backfill_channel_id, also project what a backfill of that channel would cost, from the size of its uploads playlist when it was last synced; the projection spends no quota.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,22 +25,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin"
+                ],
+                "summary": "Get today's YouTube quota usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel to project a backfill for",
+                        "name": "backfill_channel_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max_results of the backfill to project; no limit if omitted",
+                        "name": "max_results",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Database Health check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.DatabaseHealth"
+                            "$ref": "#/definitions/model.APIResponse-model_QuotaUsage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
                         }
                     }
                 }
             }
         },
-        "/games": {
+        "/channels": {
             "get": {
-                "description": "Get all games with optional search and pagination",
+                "description": "Get every registered YouTube channel",
                 "consumes": [
                     "application/json"
                 ],
@@ -48,46 +80,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "games"
-                ],
-                "summary": "Get all games",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search by game name",
-                        "name": "search",
-                        "in": "query"
-                    }
+                    "channels"
                 ],
+                "summary": "Get all channels",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse-array_model_GameResponse"
+                            "$ref": "#/definitions/model.APIResponse-array_model_ChannelResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
@@ -95,7 +105,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new game from Steam data",
+                "description": "Register a YouTube channel to sync, given its channel ID, @handle or URL. If the channel is already registered, it is returned unchanged.",
                 "consumes": [
                     "application/json"
                 ],
@@ -103,17 +113,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "games"
+                    "channels"
                 ],
-                "summary": "Create a new game",
+                "summary": "Register a channel",
                 "parameters": [
                     {
-                        "description": "Game data",
-                        "name": "game",
+                        "description": "Channel and sync settings",
+                        "name": "channel",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CreateGameRequest"
+                            "$ref": "#/definitions/model.CreateChannelRequest"
                         }
                     }
                 ],
@@ -121,7 +131,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse-model_GameResponse"
+                            "$ref": "#/definitions/model.APIResponse-model_ChannelResponse"
                         }
                     },
                     "400": {
@@ -130,18 +140,42 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
                     }
                 }
             }
         },
-        "/games/igdb/search": {
+        "/channels/{id}": {
             "get": {
-                "description": "Search for games on IGDB by name",
+                "description": "Get a single registered channel by its YouTube channel ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -149,15 +183,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "games"
+                    "channels"
                 ],
-                "summary": "Search IGDB games",
+                "summary": "Get channel by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "q",
-                        "in": "query",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -165,11 +199,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse-array_model_IGDBGameSearchResult"
+                            "$ref": "#/definitions/model.APIResponse-model_ChannelResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
@@ -179,13 +213,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
                     }
                 }
-            }
-        },
-        "/games/{id}": {
-            "get": {
-                "description": "Get a single game by its ID",
+            },
+            "put": {
+                "description": "Change a channel's sync schedule or turn its scheduled sync on or off",
                 "consumes": [
                     "application/json"
                 ],
@@ -193,23 +231,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "games"
+                    "channels"
                 ],
-                "summary": "Get game by ID",
+                "summary": "Update a channel's sync settings",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Game ID",
+                        "type": "string",
+                        "description": "Channel ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Sync settings",
+                        "name": "channel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateChannelRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse-model_GameResponse"
+                            "$ref": "#/definitions/model.APIResponse-model_ChannelResponse"
                         }
                     },
                     "400": {
@@ -218,18 +265,28 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
                     }
                 }
-            }
-        },
-        "/videos": {
-            "get": {
-                "description": "Get all videos with optional search and pagination",
+            },
+            "delete": {
+                "description": "Stop tracking a channel. Its videos are kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -237,40 +294,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "channels"
                 ],
-                "summary": "Get all videos",
+                "summary": "Remove a channel",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 24,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Search by video title or game name",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.APIResponse-array_model_VideoResponse"
-                        }
+                        "description": "OK"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
@@ -280,13 +321,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
                     }
                 }
             }
         },
-        "/videos/sync": {
+        "/channels/{id}/sync": {
             "post": {
-                "description": "Fetch and sync videos from OPZTV YouTube channel",
+                "description": "Start a background job syncing videos from one registered channel, whether or not its scheduled sync is enabled, and return it",
                 "consumes": [
                     "application/json"
                 ],
@@ -294,30 +341,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "channels"
                 ],
-                "summary": "Sync videos from YouTube channel",
+                "summary": "Sync videos from a channel",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "YouTube API Key",
-                        "name": "api_key",
-                        "in": "query",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
+                    {
+                        "enum": [
+                            "incremental",
+                            "backfill"
+                        ],
+                        "type": "string",
+                        "default": "incremental",
+                        "description": "Sync mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 50,
-                        "description": "Maximum results to fetch",
+                        "description": "Maximum results to fetch; backfills stop at the end of a page and default to no limit",
                         "name": "max_results",
                         "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse-model_SyncResult"
+                            "$ref": "#/definitions/model.APIResponse-model_SyncJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "The job's URL"
+                            }
                         }
                     },
                     "400": {
@@ -326,6 +390,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -335,9 +417,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/videos/{id}": {
+        "/databasez": {
             "get": {
-                "description": "Get a single video by its ID",
+                "description": "Check the Database health status",
                 "consumes": [
                     "application/json"
                 ],
@@ -345,23 +427,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "health"
                 ],
-                "summary": "Get video by ID",
+                "summary": "Database Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DatabaseHealth"
+                        }
+                    }
+                }
+            }
+        },
+        "/games": {
+            "get": {
+                "description": "Get all games with optional search and pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get all games",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Video ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Search by game name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ranked"
+                        ],
+                        "type": "string",
+                        "description": "Search mode",
+                        "name": "mode",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse-model_VideoResponse"
+                            "$ref": "#/definitions/model.APIResponse-array_model_GameResponse"
                         }
                     },
                     "400": {
@@ -375,13 +502,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
                     }
                 }
-            }
-        },
-        "/videos/{id}/game": {
-            "put": {
-                "description": "Match a video with a game",
+            },
+            "post": {
+                "description": "Create a new game, and fetch its metadata from IGDB",
                 "consumes": [
                     "application/json"
                 ],
@@ -389,30 +520,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "games"
                 ],
-                "summary": "Update video's game match",
+                "summary": "Create a new game",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Video ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Game ID to match",
+                        "description": "Game data",
                         "name": "game",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UpdateVideoGameRequest"
+                            "$ref": "#/definitions/model.CreateGameRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_GameResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -420,16 +547,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Error"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/model.Error"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove the game association from a video",
+            }
+        },
+        "/games/enrich": {
+            "post": {
+                "description": "Fetch the metadata of every game that has none yet from IGDB. Games added by syncs, reviews and POST /games are enriched as they are added; this catches up on the others.",
                 "consumes": [
                     "application/json"
                 ],
@@ -437,21 +578,59 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "games"
+                ],
+                "summary": "Fetch missing game metadata",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_GameEnrichResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/igdb/search": {
+            "get": {
+                "description": "Search for games on IGDB by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
                 ],
-                "summary": "Remove game match from video",
+                "summary": "Search IGDB games",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Video ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-array_model_IGDBGameSearchResult"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -467,255 +646,2939 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.APIResponse-array_model_GameResponse": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.GameResponse"
+        },
+        "/games/{id}": {
+            "get": {
+                "description": "Get a single game by its ID, with its metadata from IGDB",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get game by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
-                },
-                "meta": {
-                    "description": "omitted if nil",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Meta"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_GameResponse"
                         }
-                    ]
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
                 }
             }
         },
-        "model.APIResponse-array_model_IGDBGameSearchResult": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.IGDBGameSearchResult"
+        "/games/{id}/metadata": {
+            "post": {
+                "description": "Fetch the cover, summary, genres, themes, platforms, release date, companies, rating and alternative names of a game from IGDB again, and return the game with them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Refresh a game's metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
-                },
-                "meta": {
-                    "description": "omitted if nil",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Meta"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_GameResponse"
                         }
-                    ]
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
                 }
             }
         },
-        "model.APIResponse-array_model_VideoResponse": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.VideoResponse"
+        "/match-suggestions": {
+            "get": {
+                "description": "Get the games the matcher suggested for videos it wasn't sure about, best first. Only pending suggestions are listed unless another status is asked for.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Get the match review queue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only suggestions for this video",
+                        "name": "video_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "title",
+                            "igdb",
+                            "series"
+                        ],
+                        "type": "string",
+                        "description": "Only suggestions found this way",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "accepted",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Only suggestions in this state",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only suggestions scoring at least this, from 0 to 1",
+                        "name": "min_score",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-array_model_MatchSuggestion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/match-suggestions/review": {
+            "post": {
+                "description": "Accept and reject several match suggestions at once, in one transaction. Suggestions that aren't pending are left as they are, and left out of the result; one listed to be both accepted and rejected is accepted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Review match suggestions in bulk",
+                "parameters": [
+                    {
+                        "description": "Suggestions to accept and reject",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReviewMatchSuggestionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_MatchReviewResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/match-suggestions/{id}/accept": {
+            "post": {
+                "description": "Add the suggested game to the end of the video's games, adding it to the catalogue with its metadata first if it came from IGDB. It becomes the primary game if the video had none, and the unmatched episodes of the video's series may then be given it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Accept a match suggestion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match suggestion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_MatchSuggestion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/match-suggestions/{id}/reject": {
+            "post": {
+                "description": "Reject the suggested game for the video. The rejection is kept, so the matcher never proposes that game for the video again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "matches"
+                ],
+                "summary": "Reject a match suggestion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match suggestion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_MatchSuggestion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/series": {
+            "get": {
+                "description": "Get the series of videos found in titles numbering episodes, such as \"Elden Ring EP.3\", the most recently continued first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Get all series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only series of this channel",
+                        "name": "channel_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-array_github_com_K0ng2_zeedzad_model_Series"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/infer": {
+            "post": {
+                "description": "Put the stored videos that aren't in a series yet in the series their titles number them in, then give the unmatched episodes of every series the game most of its matched episodes have. It is assigned if nearly all of them have it, and suggested otherwise. Syncs do this for the videos they add.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Infer series from stored videos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_SeriesInferResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}": {
+            "get": {
+                "description": "Get a series with its episodes, in episode order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Get series by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-github_com_K0ng2_zeedzad_model_Series"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/sync/jobs": {
+            "get": {
+                "description": "Get the running sync jobs, and those that finished within the last hour, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Get the sync jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-array_model_SyncJob"
+                        }
+                    }
+                }
+            }
+        },
+        "/sync/jobs/{id}": {
+            "get": {
+                "description": "Get a sync job and its progress. Jobs are kept for an hour after they finish; the runs they recorded stay in the sync history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Get a sync job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sync job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_SyncJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancel a running sync job, and return it once it has stopped. The page of videos being stored is stored whole; a backfill resumes after it, and an incremental sync catches up next time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Cancel a sync job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sync job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_SyncJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/sync/jobs/{id}/events": {
+            "get": {
+                "description": "Stream a sync job as server-sent events: a progress event with the job each time it changes, and a done event with the job when it has finished, after which the stream ends.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Stream a sync job's progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sync job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SyncJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/sync/runs": {
+            "get": {
+                "description": "Get the recorded syncs of every channel, newest first. Every sync is recorded, whether it was asked for, run on a channel's schedule or started by a WebSub notification, along with its counts and the YouTube quota it spent. The videos a run failed on are listed by GET /sync/runs/{id}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Get the sync history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only runs of this channel",
+                        "name": "channel_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "manual",
+                            "schedule",
+                            "push"
+                        ],
+                        "type": "string",
+                        "description": "Only runs started this way",
+                        "name": "trigger",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "running",
+                            "succeeded",
+                            "failed",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Only runs in this state",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-array_model_SyncRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/sync/runs/{id}": {
+            "get": {
+                "description": "Get a recorded sync, with the videos it failed to store or update",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Get a sync run",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sync run ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_SyncRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos": {
+            "get": {
+                "description": "Get all videos with optional search, filters and pagination. The meta counts the videos found by each genre, platform, developer and release year of their games, and by whether they have a game; each count leaves out its own filter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get all videos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 24,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by video title, description or game name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ranked"
+                        ],
+                        "type": "string",
+                        "description": "Search mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only videos from this channel",
+                        "name": "channel_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum duration in seconds",
+                        "name": "min_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum duration in seconds",
+                        "name": "max_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only live streams (true) or only regular uploads (false)",
+                        "name": "is_live",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include videos that were made private or removed",
+                        "name": "include_unavailable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only videos with a game of this IGDB genre ID",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only videos with a game on this IGDB platform ID",
+                        "name": "platform",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only videos with a game by this IGDB company ID",
+                        "name": "developer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only videos with a game first released in or after this year",
+                        "name": "min_release_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only videos with a game first released in or before this year",
+                        "name": "max_release_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only videos with a game (true) or without one (false)",
+                        "name": "matched",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-array_model_VideoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/stats/refresh": {
+            "post": {
+                "description": "Read the counts of the videos that are due for it now, as the scheduled stats refresh does. Videos are read hourly for their first two days, every 6 hours for their first week, daily for their first month and weekly until they are 90 days old.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Refresh video stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_StatsRefreshResult"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/sync": {
+            "post": {
+                "description": "Start a background job syncing videos from one registered channel, or from every channel with sync enabled, and return it. Poll the job, or stream its progress, until it is done. An incremental sync (the default) stops at the newest video of the previous sync. A backfill pages through the whole uploads playlist; if it is interrupted or stopped by max_results, the next backfill resumes where it left off. A sync is refused if one of its channels is already being synced, or if the first can't be synced within what is left of today's YouTube quota: a backfill projected to spend more than that is refused. A sync that runs out of quota part-way stops and reports deferred.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Sync videos from YouTube channels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "YouTube API Key",
+                        "name": "api_key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel to sync; every enabled channel if omitted",
+                        "name": "channel_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "incremental",
+                            "backfill"
+                        ],
+                        "type": "string",
+                        "default": "incremental",
+                        "description": "Sync mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum results to fetch per channel; backfills stop at the end of a page and default to no limit",
+                        "name": "max_results",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_SyncJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "The job's URL"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}": {
+            "get": {
+                "description": "Get a single video by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get video by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_VideoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}/edits": {
+            "get": {
+                "description": "Get the changes the sync made to a video's title, thumbnail and status, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get a video's edit history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-array_model_VideoEdit"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}/game": {
+            "put": {
+                "description": "Make a game the primary game of a video, adding it to the video's games if needed. The unmatched episodes of the video's series may then be given the game.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Set video's primary game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Game ID to match",
+                        "name": "game",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateVideoGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the primary game from a video; its next game, if any, becomes primary",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Remove video's primary game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}/games": {
+            "post": {
+                "description": "Add a game to the end of a video's games, optionally with the segment of the video it covers. If the game is already on the video, its segment is updated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Add a game to a video",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Game and segment",
+                        "name": "game",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AddVideoGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_VideoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}/games/order": {
+            "put": {
+                "description": "Put a video's games in the given order; the first becomes the primary game. Every game on the video must be listed exactly once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Reorder a video's games",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Game IDs in the new order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReorderVideoGamesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_VideoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}/games/{gameId}": {
+            "delete": {
+                "description": "Remove one of a video's games; if it was primary, the next game becomes primary",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Remove a game from a video",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Game ID",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-model_VideoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}/stats": {
+            "get": {
+                "description": "Get the view, like and comment counts of a video each time they were read, oldest first, with the views and likes gained since the previous snapshot",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get a video's stats history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse-array_model_VideoStatsPoint"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/websub/callback/{id}": {
+            "get": {
+                "description": "Called by the hub to confirm a subscribe or unsubscribe request by echoing hub.challenge, or to report that it denied a subscription",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "websub"
+                ],
+                "summary": "Verify a WebSub subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "subscribe",
+                            "unsubscribe",
+                            "denied"
+                        ],
+                        "type": "string",
+                        "description": "Request being verified",
+                        "name": "hub.mode",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel feed URL",
+                        "name": "hub.topic",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Challenge to echo",
+                        "name": "hub.challenge",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lease granted by the hub",
+                        "name": "hub.lease_seconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The challenge",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Called by the hub when a channel publishes or updates a video, with the Atom entry of the video, or deletes one. The videos are looked up and stored right away, unless the channel is being synced: then the notification is refused with a Retry-After, for the hub to deliver again. Notifications whose X-Hub-Signature doesn't match the secret are acknowledged and ignored.",
+                "consumes": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "websub"
+                ],
+                "summary": "Receive a WebSub notification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC of the body, e.g. sha1=...",
+                        "name": "X-Hub-Signature",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_K0ng2_zeedzad_model.Series": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "episode_count": {
+                    "description": "EpisodeCount counts its videos, and MatchedCount those with a game.",
+                    "type": "integer"
+                },
+                "episodes": {
+                    "description": "Episodes are set on a single series, in episode order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VideoResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "matched_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.APIResponse-array_github_com_K0ng2_zeedzad_model_Series": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_K0ng2_zeedzad_model.Series"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-array_model_ChannelResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChannelResponse"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-array_model_GameResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GameResponse"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-array_model_IGDBGameSearchResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.IGDBGameSearchResult"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-array_model_MatchSuggestion": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MatchSuggestion"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-array_model_SyncJob": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SyncJob"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-array_model_SyncRun": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SyncRun"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-array_model_VideoEdit": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VideoEdit"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-array_model_VideoResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VideoResponse"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-array_model_VideoStatsPoint": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VideoStatsPoint"
+                    }
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-github_com_K0ng2_zeedzad_model_Series": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_K0ng2_zeedzad_model.Series"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_ChannelResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.ChannelResponse"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_GameEnrichResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.GameEnrichResult"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_GameResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.GameResponse"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_MatchReviewResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.MatchReviewResult"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_MatchSuggestion": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.MatchSuggestion"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_QuotaUsage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.QuotaUsage"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_SeriesInferResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.SeriesInferResult"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_StatsRefreshResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.StatsRefreshResult"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_SyncJob": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.SyncJob"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_SyncRun": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.SyncRun"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.APIResponse-model_VideoResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.VideoResponse"
+                },
+                "meta": {
+                    "description": "omitted if nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.Meta"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.AddVideoGameRequest": {
+            "type": "object",
+            "properties": {
+                "end_seconds": {
+                    "type": "integer"
+                },
+                "game_id": {
+                    "type": "integer"
+                },
+                "start_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BackfillProjection": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "type": "string"
+                },
+                "fits_today": {
+                    "description": "FitsToday is set if the backfill fits in what is left of today's\nbudget.",
+                    "type": "boolean"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "remaining": {
+                    "description": "Remaining is the number of items left to read; a stopped backfill\nresumes after the ones it has seen.",
+                    "type": "integer"
+                },
+                "units": {
+                    "type": "integer"
+                },
+                "videos": {
+                    "description": "Videos is the number of items in the channel's uploads playlist when\na sync last read it, or of its stored videos if none has.",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ChannelResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "handle": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "type": "string"
+                },
+                "next_sync_at": {
+                    "description": "NextSyncAt is when the scheduled sync runs next, if it is scheduled.",
+                    "type": "string"
+                },
+                "push_expires_at": {
+                    "type": "string"
+                },
+                "push_status": {
+                    "description": "PushStatus is the state of the channel's WebSub subscription, nil if\nit isn't subscribed.",
+                    "type": "string"
+                },
+                "sync_enabled": {
+                    "type": "boolean"
+                },
+                "sync_schedule": {
+                    "description": "SyncSchedule is a cron expression; channels without one are synced on\nthe default schedule.",
+                    "type": "string"
+                },
+                "thumbnail": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uploads_playlist_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateChannelRequest": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "sync_enabled": {
+                    "type": "boolean"
+                },
+                "sync_schedule": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateGameRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DatabaseHealth": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Error": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Facet": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GameAlternativeName": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GameEnrichResult": {
+            "type": "object",
+            "properties": {
+                "enriched": {
+                    "type": "integer"
+                },
+                "not_found": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "model.GameInfo": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GamePlatform": {
+            "type": "object",
+            "properties": {
+                "abbreviation": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GameResponse": {
+            "type": "object",
+            "properties": {
+                "alternative_names": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GameAlternativeName"
+                    }
+                },
+                "cover_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "developers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GameTerm"
+                    }
+                },
+                "first_release_date": {
+                    "type": "string"
+                },
+                "genres": {
+                    "description": "The related metadata is set on a single game.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GameTerm"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "match": {
+                    "description": "Match is set on the results of a ranked search.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SearchMatch"
+                        }
+                    ]
+                },
+                "metadata_updated_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "platforms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GamePlatform"
+                    }
+                },
+                "publishers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GameTerm"
+                    }
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "rating_count": {
+                    "type": "integer"
+                },
+                "summary": {
+                    "description": "Metadata from IGDB; nil until it has been fetched, at\nMetadataUpdatedAt. Rating is IGDB's total rating, from 0 to 100, of\nRatingCount critic and user ratings.",
+                    "type": "string"
+                },
+                "themes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GameTerm"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GameTerm": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.IGDBGameSearchResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MatchReviewResult": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "rejected": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "model.MatchSuggestion": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "game": {
+                    "$ref": "#/definitions/model.GameInfo"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "video_id": {
+                    "type": "string"
+                },
+                "video_title": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MatchedFacet": {
+            "type": "object",
+            "properties": {
+                "matched": {
+                    "type": "integer"
+                },
+                "unmatched": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Meta": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "description": "Facets is only set on lists of videos.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.VideoFacets"
+                        }
+                    ]
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.QuotaEndpointUsage": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.QuotaUsage": {
+            "type": "object",
+            "properties": {
+                "backfill": {
+                    "description": "Backfill is the projected cost of a backfill, if one was asked for.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BackfillProjection"
+                        }
+                    ]
+                },
+                "budget": {
+                    "type": "integer"
+                },
+                "day": {
+                    "type": "string"
+                },
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.QuotaEndpointUsage"
+                    }
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "resets_at": {
+                    "type": "string"
+                },
+                "used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ReleaseYearFacet": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ReorderVideoGamesRequest": {
+            "type": "object",
+            "properties": {
+                "game_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
-                },
-                "meta": {
-                    "description": "omitted if nil",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Meta"
-                        }
-                    ]
                 }
             }
         },
-        "model.APIResponse-model_GameResponse": {
+        "model.ReviewMatchSuggestionsRequest": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.GameResponse"
+                "accept": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "meta": {
-                    "description": "omitted if nil",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Meta"
-                        }
-                    ]
+                "reject": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "model.APIResponse-model_SyncResult": {
+        "model.SearchMatch": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.SyncResult"
+                "highlights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "meta": {
-                    "description": "omitted if nil",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Meta"
-                        }
-                    ]
+                "rank": {
+                    "type": "number"
                 }
             }
         },
-        "model.APIResponse-model_VideoResponse": {
+        "model.SeriesInferResult": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.VideoResponse"
+                "assigned": {
+                    "type": "integer"
                 },
-                "meta": {
-                    "description": "omitted if nil",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.Meta"
-                        }
-                    ]
+                "grouped": {
+                    "type": "integer"
+                },
+                "suggested": {
+                    "type": "integer"
                 }
             }
         },
-        "model.CreateGameRequest": {
+        "model.StatsRefreshResult": {
             "type": "object",
             "properties": {
-                "id": {
+                "checked": {
+                    "description": "Checked is the number of videos that were due for a refresh.",
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "missing": {
+                    "description": "Missing is the number of videos YouTube returned no stats for. They\nare marked removed.",
+                    "type": "integer"
                 },
-                "url": {
-                    "type": "string"
+                "updated": {
+                    "type": "integer"
                 }
             }
         },
-        "model.DatabaseHealth": {
+        "model.SyncJob": {
             "type": "object",
             "properties": {
-                "database": {
+                "channel_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "error": {
                     "type": "string"
                 },
-                "status": {
+                "finished_at": {
                     "type": "string"
                 },
-                "timestamp": {
+                "id": {
                     "type": "string"
                 },
-                "uptime": {
+                "progress": {
+                    "$ref": "#/definitions/model.SyncResult"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "model.Error": {
+        "model.SyncResult": {
             "type": "object",
             "properties": {
-                "error": {
+                "added": {
+                    "type": "integer"
+                },
+                "complete": {
+                    "description": "Complete is set when an incremental sync caught up with the previous\none, or a backfill reached the end of the playlist.",
+                    "type": "boolean"
+                },
+                "deferred": {
+                    "description": "Deferred is set when the sync stopped because the day's YouTube\nquota budget ran out. The next sync after the reset continues.",
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "mode": {
                     "type": "string"
+                },
+                "run_ids": {
+                    "description": "RunIDs are the sync runs the sync was recorded as, one per channel.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "Updated counts stored videos that were renamed, given a new\nthumbnail, made private or removed, or became available again.",
+                    "type": "integer"
                 }
             }
         },
-        "model.GameInfo": {
+        "model.SyncRun": {
             "type": "object",
             "properties": {
-                "id": {
+                "added": {
                     "type": "integer"
                 },
-                "name": {
+                "channel_id": {
                     "type": "string"
                 },
-                "url": {
+                "complete": {
+                    "type": "boolean"
+                },
+                "deferred": {
+                    "type": "boolean"
+                },
+                "error": {
                     "type": "string"
-                }
-            }
-        },
-        "model.GameResponse": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "finished_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "mode": {
                     "type": "string"
                 },
-                "updated_at": {
+                "quota_units": {
+                    "description": "QuotaUnits is the YouTube quota the run spent.",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "started_at": {
                     "type": "string"
                 },
-                "url": {
+                "status": {
                     "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "trigger": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "integer"
+                },
+                "video_errors": {
+                    "description": "VideoErrors are the videos the run failed to store or update. They\nare only listed for a single run.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SyncRunError"
+                    }
                 }
             }
         },
-        "model.IGDBGameSearchResult": {
+        "model.SyncRunError": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "message": {
+                    "type": "string"
                 },
-                "name": {
+                "video_id": {
                     "type": "string"
+                }
+            }
+        },
+        "model.UpdateChannelRequest": {
+            "type": "object",
+            "properties": {
+                "sync_enabled": {
+                    "type": "boolean"
                 },
-                "url": {
+                "sync_schedule": {
                     "type": "string"
                 }
             }
         },
-        "model.Meta": {
+        "model.UpdateVideoGameRequest": {
             "type": "object",
             "properties": {
-                "limit": {
+                "game_id": {
                     "type": "integer"
+                }
+            }
+        },
+        "model.VideoEdit": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
                 },
-                "offset": {
-                    "type": "integer"
+                "field": {
+                    "description": "Field is \"title\", \"thumbnail\" or \"status\".",
+                    "type": "string"
                 },
-                "total": {
-                    "type": "integer"
+                "new_value": {
+                    "type": "string"
+                },
+                "old_value": {
+                    "type": "string"
                 }
             }
         },
-        "model.SyncResult": {
+        "model.VideoFacets": {
             "type": "object",
             "properties": {
-                "added": {
-                    "type": "integer"
+                "developers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Facet"
+                    }
                 },
-                "errors": {
-                    "type": "integer"
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Facet"
+                    }
                 },
-                "skipped": {
-                    "type": "integer"
+                "matched": {
+                    "$ref": "#/definitions/model.MatchedFacet"
                 },
-                "total": {
-                    "type": "integer"
+                "platforms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Facet"
+                    }
+                },
+                "release_years": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ReleaseYearFacet"
+                    }
                 }
             }
         },
-        "model.UpdateVideoGameRequest": {
+        "model.VideoGame": {
             "type": "object",
             "properties": {
-                "game_id": {
+                "end_seconds": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
                     "type": "integer"
+                },
+                "start_seconds": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
         "model.VideoResponse": {
             "type": "object",
             "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "channel_id": {
+                    "type": "string"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "default_language": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "description": "Details from videos.list; nil until the sync has looked the video up.",
+                    "type": "integer"
+                },
+                "episode": {
+                    "type": "integer"
+                },
                 "game": {
-                    "$ref": "#/definitions/model.GameInfo"
+                    "description": "Game is the primary game, the first entry of Games.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.GameInfo"
+                        }
+                    ]
+                },
+                "games": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VideoGame"
+                    }
                 },
                 "id": {
                     "type": "string"
                 },
+                "like_count": {
+                    "type": "integer"
+                },
+                "live_status": {
+                    "description": "LiveStatus is \"none\" for regular uploads, or \"upcoming\", \"live\" or\n\"completed\" for live streams.",
+                    "type": "string"
+                },
+                "match": {
+                    "description": "Match is set on the results of a ranked search.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SearchMatch"
+                        }
+                    ]
+                },
                 "published_at": {
                     "type": "string"
                 },
+                "series_id": {
+                    "description": "SeriesID and Episode place the video in a series; nil if its title\nnumbers no episode.",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "thumbnail": {
                     "type": "string"
                 },
@@ -724,6 +3587,32 @@ const docTemplate = `{
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "view_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.VideoStatsPoint": {
+            "type": "object",
+            "properties": {
+                "captured_at": {
+                    "type": "string"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
+                "like_count": {
+                    "type": "integer"
+                },
+                "likes_gained": {
+                    "type": "integer"
+                },
+                "view_count": {
+                    "type": "integer"
+                },
+                "views_gained": {
+                    "type": "integer"
                 }
             }
         }
@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/robfig/cron/v3"
+	"google.golang.org/api/youtube/v3"
+
+	"github.com/K0ng2/zeedzad/db"
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+// GetChannels godoc
+// @Summary Get all channels
+// @Description Get every registered YouTube channel
+// @Tags channels
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.APIResponse[[]model.ChannelResponse]
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /channels [get]
+func (h *Handler) GetChannels(c fiber.Ctx) error {
+	channels, err := h.repo.GetChannels(c.RequestCtx())
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	for i := range channels {
+		channels[i].NextSyncAt = h.nextScheduledSync(channels[i].ID)
+	}
+
+	return c.JSON(Response(channels, nil))
+}
+
+// GetChannelByID godoc
+// @Summary Get channel by ID
+// @Description Get a single registered channel by its YouTube channel ID
+// @Tags channels
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Channel ID"
+// @Success 200 {object} model.APIResponse[model.ChannelResponse]
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /channels/{id} [get]
+func (h *Handler) GetChannelByID(c fiber.Ctx) error {
+	return h.sendChannel(c, c.Params("id"))
+}
+
+// CreateChannel godoc
+// @Summary Register a channel
+// @Description Register a YouTube channel to sync, given its channel ID, @handle or URL. If the channel is already registered, it is returned unchanged.
+// @Tags channels
+// @Accept  json
+// @Produce  json
+// @Param channel body model.CreateChannelRequest true "Channel and sync settings"
+// @Success 201 {object} model.APIResponse[model.ChannelResponse]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 429 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 502 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /channels [post]
+func (h *Handler) CreateChannel(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	var requestBody model.CreateChannelRequest
+	if err := c.Bind().JSON(&requestBody); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidRequestBody)
+	}
+
+	if err := validateSchedule(requestBody.SyncSchedule); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
+
+	ref, err := parseChannelRef(requestBody.Channel)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
+
+	found, err := h.lookupChannel(ctx, ref)
+	var syncErr *syncError
+	if errors.As(err, &syncErr) {
+		return sendSyncError(c, syncErr)
+	}
+	if err != nil {
+		return c.Status(http.StatusBadGateway).JSON(model.Error{Error: "failed to look up channel: " + err.Error()})
+	}
+	if found == nil {
+		return c.Status(http.StatusNotFound).JSON(model.Error{Error: "channel not found on YouTube"})
+	}
+
+	// Check if the channel is already registered
+	existingChannel, err := h.repo.GetChannelByID(ctx, found.Id)
+	if err == nil && existingChannel != nil {
+		return c.JSON(Response(existingChannel, nil))
+	}
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return sendError(c, err)
+	}
+
+	channel := buildChannelModel(found)
+	if requestBody.SyncSchedule != nil && *requestBody.SyncSchedule != "" {
+		channel.SyncSchedule = requestBody.SyncSchedule
+	}
+	if requestBody.SyncEnabled != nil {
+		channel.SyncEnabled = *requestBody.SyncEnabled
+	}
+
+	if err := h.repo.CreateChannel(ctx, channel); err != nil {
+		return sendError(c, err)
+	}
+
+	h.reloadSchedulesOrLog(ctx)
+	h.updateSubscriptionsOrLog(ctx)
+
+	return h.sendChannelStatus(c, found.Id, http.StatusCreated)
+}
+
+// UpdateChannel godoc
+// @Summary Update a channel's sync settings
+// @Description Change a channel's sync schedule or turn its scheduled sync on or off
+// @Tags channels
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Channel ID"
+// @Param channel body model.UpdateChannelRequest true "Sync settings"
+// @Success 200 {object} model.APIResponse[model.ChannelResponse]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /channels/{id} [put]
+func (h *Handler) UpdateChannel(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	channelID := c.Params("id")
+
+	var requestBody model.UpdateChannelRequest
+	if err := c.Bind().JSON(&requestBody); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidRequestBody)
+	}
+
+	if err := validateSchedule(requestBody.SyncSchedule); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
+
+	if _, err := h.repo.GetChannelByID(ctx, channelID); err != nil {
+		return sendError(c, err)
+	}
+
+	if err := h.repo.UpdateChannel(ctx, channelID, requestBody); err != nil {
+		return sendError(c, err)
+	}
+
+	h.reloadSchedulesOrLog(ctx)
+	h.updateSubscriptionsOrLog(ctx)
+
+	return h.sendChannel(c, channelID)
+}
+
+// DeleteChannel godoc
+// @Summary Remove a channel
+// @Description Stop tracking a channel. Its videos are kept.
+// @Tags channels
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Channel ID"
+// @Success 200
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /channels/{id} [delete]
+func (h *Handler) DeleteChannel(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	channelID := c.Params("id")
+
+	if _, err := h.repo.GetChannelByID(ctx, channelID); err != nil {
+		return sendError(c, err)
+	}
+
+	// The subscription would go with the channel, so the hub is told first.
+	h.unsubscribeOrLog(ctx, channelID)
+
+	if err := h.repo.DeleteChannel(ctx, channelID); err != nil {
+		return sendError(c, err)
+	}
+
+	h.reloadSchedulesOrLog(ctx)
+
+	return c.SendStatus(http.StatusOK)
+}
+
+func (h *Handler) sendChannel(c fiber.Ctx, channelID string) error {
+	return h.sendChannelStatus(c, channelID, http.StatusOK)
+}
+
+func (h *Handler) sendChannelStatus(c fiber.Ctx, channelID string, status int) error {
+	channel, err := h.repo.GetChannelByID(c.RequestCtx(), channelID)
+	if err != nil {
+		return sendError(c, err)
+	}
+	channel.NextSyncAt = h.nextScheduledSync(channelID)
+
+	return c.Status(status).JSON(Response(channel, nil))
+}
+
+func validateSchedule(schedule *string) error {
+	if schedule == nil || *schedule == "" {
+		return nil
+	}
+	if _, err := cron.ParseStandard(*schedule); err != nil {
+		return fmt.Errorf("invalid sync_schedule: %w", err)
+	}
+	return nil
+}
+
+// channelRef identifies a channel in one of the ways channels.list can look
+// it up.
+type channelRef struct {
+	id       string
+	handle   string
+	username string
+}
+
+var channelIDPattern = regexp.MustCompile(`^UC[0-9A-Za-z_-]{22}$`)
+
+// parseChannelRef accepts a channel ID, an @handle, or a channel URL such as
+// https://www.youtube.com/@handle or https://www.youtube.com/channel/UC...
+func parseChannelRef(s string) (channelRef, error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case s == "":
+		return channelRef{}, errors.New("channel is required")
+	case channelIDPattern.MatchString(s):
+		return channelRef{id: s}, nil
+	case strings.HasPrefix(s, "@") && len(s) > 1 && !strings.ContainsAny(s, "/ "):
+		return channelRef{handle: s}, nil
+	}
+
+	raw := s
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !isYouTubeHost(u.Hostname()) {
+		return channelRef{}, fmt.Errorf("%q is not a channel ID, @handle or YouTube channel URL", s)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case strings.HasPrefix(segments[0], "@") && len(segments[0]) > 1:
+		return channelRef{handle: segments[0]}, nil
+	case len(segments) >= 2 && segments[0] == "channel" && channelIDPattern.MatchString(segments[1]):
+		return channelRef{id: segments[1]}, nil
+	case len(segments) >= 2 && segments[0] == "user":
+		return channelRef{username: segments[1]}, nil
+	case len(segments) >= 2 && segments[0] == "c":
+		// Legacy custom URLs became handles.
+		return channelRef{handle: "@" + segments[1]}, nil
+	}
+
+	return channelRef{}, fmt.Errorf("%q is not a YouTube channel URL", s)
+}
+
+func isYouTubeHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "youtube.com" || strings.HasSuffix(host, ".youtube.com")
+}
+
+// lookupChannel fetches a channel from YouTube, returning nil if it doesn't
+// exist.
+func (h *Handler) lookupChannel(ctx context.Context, ref channelRef) (*youtube.Channel, error) {
+	service, err := h.createYouTubeService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if syncErr := h.spendQuota(ctx, "channels.list"); syncErr != nil {
+		return nil, syncErr
+	}
+
+	call := service.Channels.List([]string{"snippet", "contentDetails"}).Context(ctx)
+	switch {
+	case ref.id != "":
+		call = call.Id(ref.id)
+	case ref.handle != "":
+		call = call.ForHandle(ref.handle)
+	default:
+		call = call.ForUsername(ref.username)
+	}
+
+	response, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Items) == 0 {
+		return nil, nil
+	}
+
+	return response.Items[0], nil
+}
+
+func buildChannelModel(channel *youtube.Channel) repoModel.Channels {
+	result := repoModel.Channels{
+		ID:                &channel.Id,
+		UploadsPlaylistID: channel.ContentDetails.RelatedPlaylists.Uploads,
+		SyncEnabled:       true,
+	}
+
+	if channel.Snippet != nil {
+		result.Title = channel.Snippet.Title
+		result.Handle = toNullableString(channel.Snippet.CustomUrl)
+		if thumbnail := channelThumbnailURL(channel.Snippet.Thumbnails); thumbnail != "" {
+			result.Thumbnail = &thumbnail
+		}
+	}
+
+	return result
+}
+
+func channelThumbnailURL(thumbnails *youtube.ThumbnailDetails) string {
+	switch {
+	case thumbnails == nil:
+		return ""
+	case thumbnails.High != nil:
+		return thumbnails.High.Url
+	case thumbnails.Default != nil:
+		return thumbnails.Default.Url
+	}
+	return ""
+}
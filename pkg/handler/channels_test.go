@@ -0,0 +1,190 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/youtubetest"
+)
+
+var otherChannel = youtubetest.Channel{
+	ID:                "UC0aBcDeFgHiJkLmNoPqRsTu",
+	Handle:            "@OtherGamer",
+	Username:          "othergamer",
+	Title:             "Other Gamer",
+	UploadsPlaylistID: "UU0aBcDeFgHiJkLmNoPqRsTu",
+}
+
+func TestCreateChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+	}{
+		{"channel id", otherChannel.ID},
+		{"handle", "@othergamer"},
+		{"handle url", "https://www.youtube.com/@OtherGamer/videos"},
+		{"channel url", "youtube.com/channel/" + otherChannel.ID},
+		{"user url", "https://m.youtube.com/user/othergamer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			app.youtube.AddChannel(otherChannel)
+
+			var body model.APIResponse[model.ChannelResponse]
+			resp := app.do(t, http.MethodPost, "/api/channels", fmt.Sprintf(`{"channel":%q}`, tt.channel), &body)
+			if resp.StatusCode != http.StatusCreated {
+				t.Fatalf("status = %d, want 201", resp.StatusCode)
+			}
+
+			got := body.Data
+			if got.ID != otherChannel.ID || got.Title != otherChannel.Title || got.UploadsPlaylistID != otherChannel.UploadsPlaylistID {
+				t.Fatalf("channel = %+v", got)
+			}
+			if got.Handle == nil || *got.Handle != "@othergamer" {
+				t.Fatalf("handle = %v, want @othergamer", got.Handle)
+			}
+			if !got.SyncEnabled || got.SyncSchedule != nil {
+				t.Fatalf("sync = %v %v, want enabled on the default schedule", got.SyncEnabled, got.SyncSchedule)
+			}
+
+			// Registering it again returns the existing channel.
+			resp = app.do(t, http.MethodPost, "/api/channels", fmt.Sprintf(`{"channel":%q}`, otherChannel.ID), nil)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("second create: status = %d, want 200", resp.StatusCode)
+			}
+
+			var list model.APIResponse[[]model.ChannelResponse]
+			app.do(t, http.MethodGet, "/api/channels", "", &list)
+			if len(list.Data) != 2 {
+				t.Fatalf("got %d channels, want 2", len(list.Data))
+			}
+		})
+	}
+}
+
+func TestCreateChannelRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"empty", `{"channel":""}`, http.StatusBadRequest},
+		{"other site", `{"channel":"https://example.com/@othergamer"}`, http.StatusBadRequest},
+		{"video url", `{"channel":"https://www.youtube.com/watch?v=abc"}`, http.StatusBadRequest},
+		{"bad schedule", `{"channel":"@othergamer","sync_schedule":"every day"}`, http.StatusBadRequest},
+		{"unknown", `{"channel":"@nobody"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			app.youtube.AddChannel(otherChannel)
+
+			resp := app.do(t, http.MethodPost, "/api/channels", tt.body, nil)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestUpdateAndDeleteChannel(t *testing.T) {
+	app := newTestApp(t)
+
+	var body model.APIResponse[model.ChannelResponse]
+	resp := app.do(t, http.MethodPut, "/api/channels/"+testChannelID, `{"sync_schedule":"0 */6 * * *","sync_enabled":false}`, &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update: status = %d, want 200", resp.StatusCode)
+	}
+	if body.Data.SyncSchedule == nil || *body.Data.SyncSchedule != "0 */6 * * *" || body.Data.SyncEnabled {
+		t.Fatalf("channel = %+v", body.Data)
+	}
+
+	// An empty schedule goes back to the default one.
+	app.do(t, http.MethodPut, "/api/channels/"+testChannelID, `{"sync_schedule":""}`, &body)
+	if body.Data.SyncSchedule != nil || body.Data.SyncEnabled {
+		t.Fatalf("channel = %+v, want default schedule, still disabled", body.Data)
+	}
+
+	if resp := app.do(t, http.MethodPut, "/api/channels/"+testChannelID, `{"sync_schedule":"61 * * * *"}`, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("bad schedule: status = %d, want 400", resp.StatusCode)
+	}
+	if resp := app.do(t, http.MethodPut, "/api/channels/UCnope", `{}`, nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown channel: status = %d, want 404", resp.StatusCode)
+	}
+
+	if resp := app.do(t, http.MethodDelete, "/api/channels/"+testChannelID, "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete: status = %d, want 200", resp.StatusCode)
+	}
+	if resp := app.do(t, http.MethodGet, "/api/channels/"+testChannelID, "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("get deleted: status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestSyncChannels(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.AddChannel(otherChannel)
+	app.upload(1, 2)
+	app.youtube.Upload(otherChannel.ID, youtubetest.Video{ID: "other1", Title: "Other 1", PublishedAt: time.Now()})
+
+	app.do(t, http.MethodPost, "/api/channels", fmt.Sprintf(`{"channel":%q}`, otherChannel.Handle), nil)
+
+	job := app.runSyncJob(t, "/api/channels/"+otherChannel.ID+"/sync")
+	if job.Status != model.SyncRunSucceeded || job.Progress.Added != 1 {
+		t.Fatalf("channel sync = %+v, want 1 added", job)
+	}
+
+	// Without channel_id, every enabled channel is synced.
+	got := app.sync(t, "")
+	if got.Added != 2 || got.Total != 2 || !got.Complete {
+		t.Fatalf("sync = %+v, want only the 2 new OPZTV videos", got)
+	}
+
+	var videos model.APIResponse[[]model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos?channel_id="+otherChannel.ID, "", &videos)
+	if videos.Meta.Total != 1 || videos.Data[0].ID != "other1" {
+		t.Fatalf("videos = %+v, want only other1", videos.Data)
+	}
+	if c := videos.Data[0].ChannelID; c == nil || *c != otherChannel.ID {
+		t.Fatalf("channel_id = %v, want %s", c, otherChannel.ID)
+	}
+
+	if resp := app.do(t, http.MethodPost, "/api/videos/sync?channel_id=UCnope", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown channel: status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestScheduledSyncNextRun(t *testing.T) {
+	app := newTestApp(t)
+	if err := app.handler.StartScheduledSync(context.Background(), "0 3 * * *"); err != nil {
+		t.Fatalf("StartScheduledSync: %v", err)
+	}
+	t.Cleanup(app.handler.StopScheduledSync)
+
+	var body model.APIResponse[model.ChannelResponse]
+	app.do(t, http.MethodGet, "/api/channels/"+testChannelID, "", &body)
+	if next := body.Data.NextSyncAt; next == nil || next.Hour() != 3 || next.Minute() != 0 {
+		t.Fatalf("next_sync_at = %v, want 03:00 on the default schedule", next)
+	}
+
+	app.do(t, http.MethodPut, "/api/channels/"+testChannelID, `{"sync_schedule":"30 * * * *"}`, &body)
+	if next := body.Data.NextSyncAt; next == nil || next.Minute() != 30 {
+		t.Fatalf("next_sync_at = %v, want half past on the channel's schedule", next)
+	}
+
+	app.do(t, http.MethodPut, "/api/channels/"+testChannelID, `{"sync_enabled":false}`, &body)
+	if body.Data.NextSyncAt != nil {
+		t.Fatalf("next_sync_at = %v, want nil when disabled", body.Data.NextSyncAt)
+	}
+
+	if err := app.handler.StartScheduledSync(context.Background(), "not a schedule"); err == nil {
+		t.Fatal("StartScheduledSync accepted an invalid default schedule")
+	}
+}
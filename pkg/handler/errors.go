@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/db"
+	"github.com/K0ng2/zeedzad/model"
+)
+
+// errorStatus maps a repository error to an HTTP status.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrConstraint):
+		return http.StatusConflict
+	case errors.Is(err, db.ErrRateLimited), errors.Is(err, db.ErrTransient):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, db.ErrAuth):
+		// The database rejected our credentials, not the client's.
+		return http.StatusBadGateway
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// sendError writes err as a model.Error with the status from errorStatus.
+// Rate-limited responses pass the backend's Retry-After hint on.
+func sendError(c fiber.Ctx, err error) error {
+	var dbErr *db.Error
+	if errors.Is(err, db.ErrRateLimited) && errors.As(err, &dbErr) && dbErr.RetryAfter > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(dbErr.RetryAfter.Seconds()))))
+	}
+
+	return c.Status(errorStatus(err)).JSON(model.Error{Error: err.Error()})
+}
@@ -0,0 +1,92 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+func TestErrorStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		fail   func(app *testApp)
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{
+			name:   "missing game",
+			method: http.MethodGet,
+			target: "/api/games/404",
+			want:   http.StatusNotFound,
+		},
+		{
+			name:   "missing video",
+			method: http.MethodGet,
+			target: "/api/videos/nope",
+			want:   http.StatusNotFound,
+		},
+		{
+			name:   "unknown game for video",
+			method: http.MethodPut,
+			target: "/api/videos/vid1/game",
+			body:   `{"game_id": 404}`,
+			want:   http.StatusConflict,
+		},
+		{
+			name:   "rate limited",
+			fail:   func(app *testApp) { app.d1.Fail(10, http.StatusTooManyRequests, 971, "slow down") },
+			method: http.MethodGet,
+			target: "/api/games",
+			want:   http.StatusServiceUnavailable,
+		},
+		{
+			name:   "bad credentials",
+			fail:   func(app *testApp) { app.d1.Fail(1, http.StatusUnauthorized, 10000, "Authentication error") },
+			method: http.MethodGet,
+			target: "/api/videos",
+			want:   http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			app.seed(t, `INSERT INTO videos (id, title, published_at) VALUES ('vid1', 'Video', '2024-01-01 00:00:00')`)
+			if tt.fail != nil {
+				tt.fail(app)
+			}
+
+			var body model.Error
+			resp := app.do(t, tt.method, tt.target, tt.body, &body)
+
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", resp.StatusCode, tt.want, body.Error)
+			}
+			if body.Error == "" {
+				t.Fatal("expected an error message")
+			}
+		})
+	}
+}
+
+func TestTransientReadIsRetried(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES (1, 'Portal', 'https://www.igdb.com/games/portal')`)
+	app.d1.Fail(1, http.StatusServiceUnavailable, 0, "unavailable")
+
+	var body model.APIResponse[model.GameResponse]
+	resp := app.do(t, http.MethodGet, "/api/games/1", "", &body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if body.Data.Name != "Portal" {
+		t.Fatalf("game = %+v", body.Data)
+	}
+	if got := app.d1.Requests(); got != 2 {
+		t.Fatalf("D1 requests = %d, want 2", got)
+	}
+}
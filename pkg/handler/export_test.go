@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// Exported for the tests in package handler_test.
+var (
+	QuotaDay     = quotaDay
+	QuotaResetAt = quotaResetAt
+)
+
+// MatchStoredVideos runs the matcher on stored videos, as the sync does on
+// the videos it adds.
+func (h *Handler) MatchStoredVideos(ctx context.Context, ids ...string) error {
+	stored, err := h.repo.GetStoredVideos(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	h.matchVideos(ctx, slices.Collect(maps.Values(stored)))
+	return nil
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/igdb"
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/repository"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	"github.com/K0ng2/zeedzad/utils"
+)
+
+// enrichGames fetches the metadata of stored games from IGDB and stores
+// it. It returns the IDs IGDB doesn't know.
+func (h *Handler) enrichGames(ctx context.Context, ids []int64) (notFound []int64, err error) {
+	for batch := range slices.Chunk(ids, igdb.MaxGamesPerRequest) {
+		games, err := h.igdb.GetGames(batch)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata := make([]repository.GameMetadata, len(games))
+		for i, game := range games {
+			metadata[i] = convertToGameMetadata(game)
+		}
+		if err := h.repo.SaveGameMetadata(ctx, metadata); err != nil {
+			return nil, err
+		}
+
+		for _, id := range batch {
+			if !slices.ContainsFunc(games, func(g igdb.Game) bool { return g.ID == id }) {
+				notFound = append(notFound, id)
+			}
+		}
+	}
+
+	return notFound, nil
+}
+
+// enrichNewGames fetches the metadata of those of the games that have none
+// yet. Failures are only logged: the games are stored, and can be
+// enriched later.
+func (h *Handler) enrichNewGames(ctx context.Context, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+
+	missing, err := h.repo.GetGameIDsWithoutMetadata(ctx, ids)
+	if err != nil {
+		fmt.Printf("failed to find the games to fetch metadata for: %v\n", err)
+		return
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	if _, err := h.enrichGames(ctx, missing); err != nil {
+		fmt.Printf("failed to fetch the metadata of games %v: %v\n", missing, err)
+	}
+}
+
+// RefreshGameMetadata godoc
+// @Summary Refresh a game's metadata
+// @Description Fetch the cover, summary, genres, themes, platforms, release date, companies, rating and alternative names of a game from IGDB again, and return the game with them
+// @Tags games
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Game ID"
+// @Success 200 {object} model.APIResponse[model.GameResponse]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /games/{id}/metadata [post]
+func (h *Handler) RefreshGameMetadata(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	id, err := fiber.Convert(c.Params("id"), utils.Atoi64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidPathParams)
+	}
+
+	if _, err := h.repo.GetGameByID(ctx, id); err != nil {
+		return sendError(c, err)
+	}
+
+	notFound, err := h.enrichGames(ctx, []int64{id})
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: "failed to fetch game metadata: " + err.Error()})
+	}
+	if len(notFound) > 0 {
+		return c.Status(http.StatusNotFound).JSON(model.Error{Error: "game not found on igdb"})
+	}
+
+	game, err := h.repo.GetGameByID(ctx, id)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.JSON(Response(game, nil))
+}
+
+// EnrichGames godoc
+// @Summary Fetch missing game metadata
+// @Description Fetch the metadata of every game that has none yet from IGDB. Games added by syncs, reviews and POST /games are enriched as they are added; this catches up on the others.
+// @Tags games
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.APIResponse[model.GameEnrichResult]
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /games/enrich [post]
+func (h *Handler) EnrichGames(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	ids, err := h.repo.GetGameIDsWithoutMetadata(ctx, nil)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	notFound, err := h.enrichGames(ctx, ids)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: "failed to fetch game metadata: " + err.Error()})
+	}
+
+	result := model.GameEnrichResult{Enriched: len(ids) - len(notFound), NotFound: notFound}
+	if result.NotFound == nil {
+		result.NotFound = []int64{}
+	}
+
+	return c.JSON(Response(result, nil))
+}
+
+// convertToGameMetadata maps a game from IGDB to the rows it is stored as.
+func convertToGameMetadata(game igdb.Game) repository.GameMetadata {
+	id := int32(game.ID)
+	m := repository.GameMetadata{
+		Game: repoModel.Games{
+			ID:      &id,
+			Summary: toNullableString(game.Summary),
+		},
+	}
+
+	if game.Cover != nil && game.Cover.ImageID != "" {
+		url := game.Cover.URL()
+		m.Game.CoverURL = &url
+	}
+	if game.FirstReleaseDate != 0 {
+		released := time.Unix(game.FirstReleaseDate, 0).UTC()
+		m.Game.FirstReleaseDate = &released
+	}
+	if game.TotalRatingCount > 0 {
+		m.Game.Rating = &game.TotalRating
+		m.Game.RatingCount = &game.TotalRatingCount
+	}
+
+	for _, genre := range game.Genres {
+		m.Genres = append(m.Genres, repoModel.Genres{ID: int32Ptr(genre.ID), Name: genre.Name})
+	}
+	for _, theme := range game.Themes {
+		m.Themes = append(m.Themes, repoModel.Themes{ID: int32Ptr(theme.ID), Name: theme.Name})
+	}
+	for _, platform := range game.Platforms {
+		m.Platforms = append(m.Platforms, repoModel.Platforms{
+			ID:           int32Ptr(platform.ID),
+			Name:         platform.Name,
+			Abbreviation: toNullableString(platform.Abbreviation),
+		})
+	}
+	for _, involved := range game.InvolvedCompanies {
+		if !involved.Developer && !involved.Publisher {
+			continue
+		}
+
+		// A company can be listed once as the developer and again as the
+		// publisher.
+		companyID := int32(involved.Company.ID)
+		if i := slices.IndexFunc(m.GameCompanies, func(c repoModel.GameCompanies) bool { return c.CompanyID == companyID }); i >= 0 {
+			m.GameCompanies[i].Developer = m.GameCompanies[i].Developer || involved.Developer
+			m.GameCompanies[i].Publisher = m.GameCompanies[i].Publisher || involved.Publisher
+			continue
+		}
+
+		m.Companies = append(m.Companies, repoModel.Companies{ID: &companyID, Name: involved.Company.Name})
+		m.GameCompanies = append(m.GameCompanies, repoModel.GameCompanies{
+			GameID:    id,
+			CompanyID: companyID,
+			Developer: involved.Developer,
+			Publisher: involved.Publisher,
+		})
+	}
+	for _, name := range game.AlternativeNames {
+		m.AlternativeNames = append(m.AlternativeNames, repoModel.GameAlternativeNames{
+			GameID:  id,
+			Name:    name.Name,
+			Comment: toNullableString(name.Comment),
+		})
+	}
+
+	return m
+}
+
+func int32Ptr(v int64) *int32 {
+	i := int32(v)
+	return &i
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gofiber/fiber/v3"
 
+	"github.com/K0ng2/zeedzad/db"
 	"github.com/K0ng2/zeedzad/model"
 	"github.com/K0ng2/zeedzad/utils"
 )
@@ -18,9 +20,11 @@ import (
 // @Param offset query int false "Offset" default(0)
 // @Param limit query int false "Limit" default(20)
 // @Param search query string false "Search by game name"
+// @Param mode query string false "Search mode" Enums(ranked)
 // @Success 200 {object} model.APIResponse[[]model.GameResponse]
 // @Failure 400 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
 // @Router /games [get]
 func (h *Handler) GetGames(c fiber.Ctx) error {
 	ctx := c.RequestCtx()
@@ -30,16 +34,19 @@ func (h *Handler) GetGames(c fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
 	}
 
-	search := c.Query("search", "")
+	search, err := GetSearch(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
 
 	games, err := h.repo.GetGames(ctx, *q, search)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
 	}
 
 	total, err := h.repo.GetGameTotalItems(ctx, search)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
 	}
 
 	meta := &model.Meta{
@@ -53,14 +60,16 @@ func (h *Handler) GetGames(c fiber.Ctx) error {
 
 // GetGameByID godoc
 // @Summary Get game by ID
-// @Description Get a single game by its ID
+// @Description Get a single game by its ID, with its metadata from IGDB
 // @Tags games
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Game ID"
 // @Success 200 {object} model.APIResponse[model.GameResponse]
 // @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
 // @Router /games/{id} [get]
 func (h *Handler) GetGameByID(c fiber.Ctx) error {
 	ctx := c.RequestCtx()
@@ -72,7 +81,7 @@ func (h *Handler) GetGameByID(c fiber.Ctx) error {
 
 	game, err := h.repo.GetGameByID(ctx, id)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
 	}
 
 	return c.JSON(Response(game, nil))
@@ -105,14 +114,16 @@ func (h *Handler) SearchIGDBGames(c fiber.Ctx) error {
 
 // CreateGame godoc
 // @Summary Create a new game
-// @Description Create a new game from Steam data
+// @Description Create a new game, and fetch its metadata from IGDB
 // @Tags games
 // @Accept  json
 // @Produce  json
 // @Param game body model.CreateGameRequest true "Game data"
 // @Success 201 {object} model.APIResponse[model.GameResponse]
 // @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
 // @Router /games [post]
 func (h *Handler) CreateGame(c fiber.Ctx) error {
 	ctx := c.RequestCtx()
@@ -127,16 +138,21 @@ func (h *Handler) CreateGame(c fiber.Ctx) error {
 	if err == nil && existingGame != nil {
 		return c.JSON(Response(existingGame, nil))
 	}
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return sendError(c, err)
+	}
 
 	// Create new game
 	id, err := h.repo.CreateGame(ctx, requestBody)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
 	}
 
+	h.enrichNewGames(ctx, []int64{id})
+
 	game, err := h.repo.GetGameByID(ctx, id)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
 	}
 
 	return c.Status(http.StatusCreated).JSON(Response(game, nil))
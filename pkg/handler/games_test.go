@@ -0,0 +1,187 @@
+package handler_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/igdb"
+	"github.com/K0ng2/zeedzad/igdbtest"
+	"github.com/K0ng2/zeedzad/model"
+)
+
+func TestCreateGame(t *testing.T) {
+	app := newTestApp(t)
+
+	var created model.APIResponse[model.GameResponse]
+	resp := app.do(t, http.MethodPost, "/api/games", `{"id": 1877, "name": "Cyberpunk 2077", "url": "https://www.igdb.com/games/cyberpunk-2077"}`, &created)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", resp.StatusCode)
+	}
+	if created.Data.ID != 1877 || created.Data.Name != "Cyberpunk 2077" {
+		t.Fatalf("game = %+v", created.Data)
+	}
+
+	// Creating the same game again returns the existing row.
+	resp = app.do(t, http.MethodPost, "/api/games", `{"id": 1877, "name": "Renamed", "url": "https://example.com"}`, &created)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if created.Data.Name != "Cyberpunk 2077" {
+		t.Fatalf("name = %q, want the original", created.Data.Name)
+	}
+}
+
+func TestGetGames(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES
+		(1, 'Portal', 'https://www.igdb.com/games/portal'),
+		(2, 'Portal 2', 'https://www.igdb.com/games/portal-2'),
+		(3, 'Half-Life', 'https://www.igdb.com/games/half-life')`)
+
+	var body model.APIResponse[[]model.GameResponse]
+	resp := app.do(t, http.MethodGet, "/api/games?search=portal", "", &body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if len(body.Data) != 2 || body.Data[0].Name != "Portal" || body.Data[1].Name != "Portal 2" {
+		t.Fatalf("data = %+v", body.Data)
+	}
+	if body.Meta == nil || body.Meta.Total != 2 {
+		t.Fatalf("meta = %+v, want total 2", body.Meta)
+	}
+}
+
+func TestGetGameByIDRejectsInvalidID(t *testing.T) {
+	app := newTestApp(t)
+
+	resp := app.do(t, http.MethodGet, "/api/games/abc", "", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+// cyberpunk is a game on the fake IGDB with all the metadata the app
+// fetches. CD Projekt RED is listed once as the developer and again as the
+// publisher.
+var cyberpunk = igdbtest.Game{
+	ID:               1877,
+	Name:             "Cyberpunk 2077",
+	URL:              "https://www.igdb.com/games/cyberpunk-2077",
+	Summary:          "An open-world action RPG set in Night City.",
+	FirstReleaseDate: time.Date(2020, 12, 10, 0, 0, 0, 0, time.UTC).Unix(),
+	TotalRating:      82.5,
+	TotalRatingCount: 1200,
+	Cover:            &igdb.Cover{ImageID: "coaih8"},
+	Genres:           []igdb.Entity{{ID: 12, Name: "Role-playing (RPG)"}, {ID: 5, Name: "Shooter"}},
+	Themes:           []igdb.Entity{{ID: 18, Name: "Science fiction"}},
+	Platforms:        []igdb.Platform{{ID: 6, Name: "PC (Microsoft Windows)", Abbreviation: "PC"}, {ID: 167, Name: "PlayStation 5", Abbreviation: "PS5"}},
+	InvolvedCompanies: []igdb.InvolvedCompany{
+		{Company: igdb.Entity{ID: 908, Name: "CD Projekt RED"}, Developer: true},
+		{Company: igdb.Entity{ID: 908, Name: "CD Projekt RED"}, Publisher: true},
+		{Company: igdb.Entity{ID: 2, Name: "Bandai Namco"}, Publisher: true},
+		{Company: igdb.Entity{ID: 3, Name: "Porting House"}},
+	},
+	AlternativeNames: []igdb.AlternativeName{{Name: "CP2077", Comment: "Acronym"}},
+}
+
+func TestCreateGameFetchesMetadata(t *testing.T) {
+	app := newTestApp(t)
+	app.igdb.AddGames(cyberpunk)
+
+	app.do(t, http.MethodPost, "/api/games", `{"id": 1877, "name": "Cyberpunk 2077", "url": "https://www.igdb.com/games/cyberpunk-2077"}`, nil)
+
+	var body model.APIResponse[model.GameResponse]
+	if resp := app.do(t, http.MethodGet, "/api/games/1877", "", &body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET game status = %d, want 200", resp.StatusCode)
+	}
+	game := body.Data
+
+	if game.Summary == nil || *game.Summary != cyberpunk.Summary {
+		t.Errorf("summary = %v, want %q", game.Summary, cyberpunk.Summary)
+	}
+	if game.CoverURL == nil || *game.CoverURL != "https://images.igdb.com/igdb/image/upload/t_cover_big/coaih8.jpg" {
+		t.Errorf("cover_url = %v", game.CoverURL)
+	}
+	if game.FirstReleaseDate == nil || !game.FirstReleaseDate.Equal(time.Date(2020, 12, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first_release_date = %v, want 2020-12-10", game.FirstReleaseDate)
+	}
+	if game.Rating == nil || *game.Rating != 82.5 || game.RatingCount == nil || *game.RatingCount != 1200 {
+		t.Errorf("rating = %v of %v, want 82.5 of 1200", game.Rating, game.RatingCount)
+	}
+	if game.MetadataUpdatedAt == nil {
+		t.Errorf("metadata_updated_at is nil, want it set")
+	}
+
+	wantGenres := []model.GameTerm{{ID: 12, Name: "Role-playing (RPG)"}, {ID: 5, Name: "Shooter"}}
+	if !slices.Equal(game.Genres, wantGenres) {
+		t.Errorf("genres = %+v, want %+v", game.Genres, wantGenres)
+	}
+	if !slices.Equal(game.Themes, []model.GameTerm{{ID: 18, Name: "Science fiction"}}) {
+		t.Errorf("themes = %+v", game.Themes)
+	}
+	if len(game.Platforms) != 2 || game.Platforms[0].ID != 6 || game.Platforms[1].Abbreviation == nil || *game.Platforms[1].Abbreviation != "PS5" {
+		t.Errorf("platforms = %+v", game.Platforms)
+	}
+	if !slices.Equal(game.Developers, []model.GameTerm{{ID: 908, Name: "CD Projekt RED"}}) {
+		t.Errorf("developers = %+v, want CD Projekt RED", game.Developers)
+	}
+	if wantPublishers := []model.GameTerm{{ID: 2, Name: "Bandai Namco"}, {ID: 908, Name: "CD Projekt RED"}}; !slices.Equal(game.Publishers, wantPublishers) {
+		t.Errorf("publishers = %+v, want %+v", game.Publishers, wantPublishers)
+	}
+	if len(game.AlternativeNames) != 1 || game.AlternativeNames[0].Name != "CP2077" || game.AlternativeNames[0].Comment == nil || *game.AlternativeNames[0].Comment != "Acronym" {
+		t.Errorf("alternative_names = %+v", game.AlternativeNames)
+	}
+
+	// Listed games carry the metadata stored on the game itself.
+	var list model.APIResponse[[]model.GameResponse]
+	app.do(t, http.MethodGet, "/api/games", "", &list)
+	if len(list.Data) != 1 || list.Data[0].Summary == nil || list.Data[0].Genres != nil {
+		t.Fatalf("games = %+v, want the summary without the related metadata", list.Data)
+	}
+}
+
+func TestEnrichGames(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES
+		(1877, 'Cyberpunk 2077', 'https://www.igdb.com/games/cyberpunk-2077'),
+		(99, 'Homebrew', 'https://example.com/homebrew')`)
+	app.igdb.AddGames(cyberpunk)
+
+	var body model.APIResponse[model.GameEnrichResult]
+	if resp := app.do(t, http.MethodPost, "/api/games/enrich", "", &body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("enrich status = %d, want 200", resp.StatusCode)
+	}
+	if body.Data.Enriched != 1 || !slices.Equal(body.Data.NotFound, []int64{99}) {
+		t.Fatalf("enrich = %+v, want Cyberpunk enriched and Homebrew not found", body.Data)
+	}
+
+	// Refreshing a game replaces its metadata.
+	app.igdb.SetGame(1877, func(g *igdbtest.Game) {
+		g.Genres = []igdb.Entity{{ID: 12, Name: "RPG"}}
+		g.AlternativeNames = nil
+	})
+
+	var game model.APIResponse[model.GameResponse]
+	if resp := app.do(t, http.MethodPost, "/api/games/1877/metadata", "", &game); resp.StatusCode != http.StatusOK {
+		t.Fatalf("refresh status = %d, want 200", resp.StatusCode)
+	}
+	if !slices.Equal(game.Data.Genres, []model.GameTerm{{ID: 12, Name: "RPG"}}) || game.Data.AlternativeNames != nil {
+		t.Fatalf("refreshed game = %+v, want the new genres and no alternative names", game.Data)
+	}
+
+	if resp := app.do(t, http.MethodPost, "/api/games/99/metadata", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("refreshing a game igdb doesn't know status = %d, want 404", resp.StatusCode)
+	}
+	if resp := app.do(t, http.MethodPost, "/api/games/5/metadata", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("refreshing a missing game status = %d, want 404", resp.StatusCode)
+	}
+
+	app.igdb.Fail(1, http.StatusInternalServerError)
+	if resp := app.do(t, http.MethodPost, "/api/games/1877/metadata", "", nil); resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("refresh with igdb down status = %d, want 500", resp.StatusCode)
+	}
+}
@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/robfig/cron/v3"
+	"google.golang.org/api/option"
 
 	"github.com/K0ng2/zeedzad/db"
 	"github.com/K0ng2/zeedzad/igdb"
@@ -15,15 +20,30 @@ import (
 type Handler struct {
 	repo *repository.Repository
 	igdb *igdb.Client
+
+	youtubeOptions []option.ClientOption
+	scheduler      *syncScheduler
+	statsCron      *cron.Cron
+	websub         *webSubscriber
+	quota          quotaLedger
+	jobs           syncJobs
 }
 
-func NewHandler(db *db.Database, igdbClient *igdb.Client) *Handler {
+func NewHandler(db db.Database, igdbClient *igdb.Client) *Handler {
 	return &Handler{
-		repo: repository.NewRepository(db),
-		igdb: igdbClient,
+		repo:  repository.NewRepository(db),
+		igdb:  igdbClient,
+		quota: quotaLedger{budget: DefaultQuotaBudget},
+		jobs:  newSyncJobs(),
 	}
 }
 
+// SetYouTubeOptions sets extra options for the YouTube API client, such as
+// a different endpoint.
+func (h *Handler) SetYouTubeOptions(opts ...option.ClientOption) {
+	h.youtubeOptions = opts
+}
+
 // GetOffset extracts limit and offset query parameters from the request context.
 func GetOffset(c fiber.Ctx) (*model.Offset, error) {
 	var query model.Offset
@@ -35,6 +55,118 @@ func GetOffset(c fiber.Ctx) (*model.Offset, error) {
 	return &query, nil
 }
 
+// GetSearch extracts the search and mode query parameters from the request
+// context. The only mode is "ranked"; without it results keep their usual
+// order.
+func GetSearch(c fiber.Ctx) (model.Search, error) {
+	search := model.Search{Query: c.Query("search")}
+
+	switch mode := c.Query("mode"); mode {
+	case "":
+	case "ranked":
+		search.Ranked = true
+	default:
+		return search, fmt.Errorf("unknown search mode %q", mode)
+	}
+
+	return search, nil
+}
+
+// GetVideoFilter extracts the video search and filter query parameters from
+// the request context.
+func GetVideoFilter(c fiber.Ctx) (model.VideoFilter, error) {
+	search, err := GetSearch(c)
+	if err != nil {
+		return model.VideoFilter{}, err
+	}
+
+	filter := model.VideoFilter{Search: search, ChannelID: c.Query("channel_id")}
+
+	bounds := []struct {
+		key   string
+		value *int
+	}{
+		{"min_duration", &filter.MinDuration},
+		{"max_duration", &filter.MaxDuration},
+	}
+	for _, bound := range bounds {
+		value := c.Query(bound.key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("%s must be a number of seconds", bound.key)
+		}
+		*bound.value = n
+	}
+
+	if value := c.Query("include_unavailable"); value != "" {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("include_unavailable must be true or false")
+		}
+		filter.IncludeUnavailable = include
+	}
+
+	if value := c.Query("is_live"); value != "" {
+		isLive, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("is_live must be true or false")
+		}
+		filter.IsLive = &isLive
+	}
+
+	ids := []struct {
+		key   string
+		value *int64
+	}{
+		{"genre", &filter.GenreID},
+		{"platform", &filter.PlatformID},
+		{"developer", &filter.DeveloperID},
+	}
+	for _, id := range ids {
+		value := c.Query(id.key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			return filter, fmt.Errorf("%s must be an igdb id", id.key)
+		}
+		*id.value = n
+	}
+
+	years := []struct {
+		key   string
+		value *int
+	}{
+		{"min_release_year", &filter.MinReleaseYear},
+		{"max_release_year", &filter.MaxReleaseYear},
+	}
+	for _, year := range years {
+		value := c.Query(year.key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 9999 {
+			return filter, fmt.Errorf("%s must be a year", year.key)
+		}
+		*year.value = n
+	}
+
+	if value := c.Query("matched"); value != "" {
+		matched, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("matched must be true or false")
+		}
+		filter.Matched = &matched
+	}
+
+	return filter, nil
+}
+
 var startTime = time.Now()
 
 func DatabaseHealth(ping error, c fiber.Ctx) error {
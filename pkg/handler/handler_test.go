@@ -0,0 +1,102 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/db/d1test"
+	"github.com/K0ng2/zeedzad/handler"
+	"github.com/K0ng2/zeedzad/igdbtest"
+	"github.com/K0ng2/zeedzad/metrics"
+	"github.com/K0ng2/zeedzad/server"
+	"github.com/K0ng2/zeedzad/youtubetest"
+)
+
+// testApp is the full router backed by fake D1, YouTube and IGDB servers.
+type testApp struct {
+	*fiber.App
+	handler *handler.Handler
+	d1      *d1test.Server
+	youtube *youtubetest.Server
+	igdb    *igdbtest.Server
+	metrics *metrics.Metrics
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+
+	srv := d1test.NewServer(t)
+	database := srv.NewDatabase(t)
+	igdb := igdbtest.NewServer(t)
+	h := handler.NewHandler(database, igdb.NewClient())
+
+	yt := youtubetest.NewServer(t)
+	h.SetYouTubeOptions(yt.ClientOptions()...)
+
+	m := metrics.New()
+	database.AddQueryObserver(m)
+
+	return &testApp{App: server.NewRouter(h, m), handler: h, d1: srv, youtube: yt, igdb: igdb, metrics: m}
+}
+
+// seed runs SQL directly against the fake D1 database.
+func (a *testApp) seed(t *testing.T, query string, args ...any) {
+	t.Helper()
+
+	if _, err := a.d1.SQLite.Conn().Exec(query, args...); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+}
+
+// do sends a request and decodes the JSON response body into out, if given.
+func (a *testApp) do(t *testing.T, method, target, body string, out any) *http.Response {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.Test(req, fiber.TestConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, target, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decode response: %v", method, target, err)
+		}
+	}
+
+	return resp
+}
+
+func TestDatabaseHealth(t *testing.T) {
+	app := newTestApp(t)
+
+	var health struct {
+		Status   string `json:"status"`
+		Database string `json:"database"`
+	}
+	resp := app.do(t, http.MethodGet, "/api/databasez", "", &health)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if health.Status != "healthy" || health.Database != "healthy" {
+		t.Fatalf("health = %+v", health)
+	}
+}
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/utils"
+)
+
+// GetMatchSuggestions godoc
+// @Summary Get the match review queue
+// @Description Get the games the matcher suggested for videos it wasn't sure about, best first. Only pending suggestions are listed unless another status is asked for.
+// @Tags matches
+// @Accept  json
+// @Produce  json
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Param video_id query string false "Only suggestions for this video"
+// @Param source query string false "Only suggestions found this way" Enums(title, igdb, series)
+// @Param status query string false "Only suggestions in this state" Enums(pending, accepted, rejected) default(pending)
+// @Param min_score query number false "Only suggestions scoring at least this, from 0 to 1"
+// @Success 200 {object} model.APIResponse[[]model.MatchSuggestion]
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /match-suggestions [get]
+func (h *Handler) GetMatchSuggestions(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	q, err := GetOffset(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
+
+	filter := model.MatchSuggestionFilter{
+		VideoID: c.Query("video_id"),
+		Source:  c.Query("source"),
+		Status:  c.Query("status", model.MatchSuggestionPending),
+	}
+	sources := []string{model.MatchSourceTitle, model.MatchSourceIGDB, model.MatchSourceSeries}
+	if filter.Source != "" && !slices.Contains(sources, filter.Source) {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: fmt.Sprintf("unknown source %q", filter.Source)})
+	}
+	statuses := []string{model.MatchSuggestionPending, model.MatchSuggestionAccepted, model.MatchSuggestionRejected}
+	if !slices.Contains(statuses, filter.Status) {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: fmt.Sprintf("unknown status %q", filter.Status)})
+	}
+	if value := c.Query("min_score"); value != "" {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil || score < 0 || score > 1 {
+			return c.Status(http.StatusBadRequest).JSON(model.Error{Error: "min_score must be a number from 0 to 1"})
+		}
+		filter.MinScore = score
+	}
+
+	suggestions, err := h.repo.GetMatchSuggestions(ctx, *q, filter)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	total, err := h.repo.GetMatchSuggestionTotalItems(ctx, filter)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	meta := &model.Meta{
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+
+	return c.JSON(Response(suggestions, meta))
+}
+
+// AcceptMatchSuggestion godoc
+// @Summary Accept a match suggestion
+// @Description Add the suggested game to the end of the video's games, adding it to the catalogue with its metadata first if it came from IGDB. It becomes the primary game if the video had none, and the unmatched episodes of the video's series may then be given it.
+// @Tags matches
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Match suggestion ID"
+// @Success 200 {object} model.APIResponse[model.MatchSuggestion]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /match-suggestions/{id}/accept [post]
+func (h *Handler) AcceptMatchSuggestion(c fiber.Ctx) error {
+	return h.reviewMatchSuggestion(c, model.MatchSuggestionAccepted)
+}
+
+// RejectMatchSuggestion godoc
+// @Summary Reject a match suggestion
+// @Description Reject the suggested game for the video. The rejection is kept, so the matcher never proposes that game for the video again.
+// @Tags matches
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Match suggestion ID"
+// @Success 200 {object} model.APIResponse[model.MatchSuggestion]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /match-suggestions/{id}/reject [post]
+func (h *Handler) RejectMatchSuggestion(c fiber.Ctx) error {
+	return h.reviewMatchSuggestion(c, model.MatchSuggestionRejected)
+}
+
+// reviewMatchSuggestion accepts or rejects the pending suggestion of the
+// id path parameter, and returns it reviewed.
+func (h *Handler) reviewMatchSuggestion(c fiber.Ctx, status string) error {
+	ctx := c.RequestCtx()
+
+	id, err := fiber.Convert(c.Params("id"), utils.Atoi64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidPathParams)
+	}
+
+	suggestion, err := h.repo.GetMatchSuggestionByID(ctx, id)
+	if err != nil {
+		return sendError(c, err)
+	}
+	if suggestion.Status != model.MatchSuggestionPending {
+		return c.Status(http.StatusConflict).JSON(model.Error{Error: "match suggestion was already " + suggestion.Status})
+	}
+
+	var accept, reject []int64
+	if status == model.MatchSuggestionAccepted {
+		accept = []int64{id}
+	} else {
+		reject = []int64{id}
+	}
+
+	result, err := h.repo.ReviewMatchSuggestions(ctx, accept, reject)
+	if err != nil {
+		return sendError(c, err)
+	}
+	if len(result.Accepted) == 0 && len(result.Rejected) == 0 {
+		return c.Status(http.StatusConflict).JSON(model.Error{Error: "match suggestion was reviewed meanwhile"})
+	}
+	if len(result.Accepted) > 0 {
+		h.enrichNewGames(ctx, []int64{int64(suggestion.Game.ID)})
+		h.inferVideoSeriesGames(ctx, suggestion.VideoID)
+	}
+
+	suggestion, err = h.repo.GetMatchSuggestionByID(ctx, id)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.JSON(Response(suggestion, nil))
+}
+
+// ReviewMatchSuggestions godoc
+// @Summary Review match suggestions in bulk
+// @Description Accept and reject several match suggestions at once, in one transaction. Suggestions that aren't pending are left as they are, and left out of the result; one listed to be both accepted and rejected is accepted.
+// @Tags matches
+// @Accept  json
+// @Produce  json
+// @Param review body model.ReviewMatchSuggestionsRequest true "Suggestions to accept and reject"
+// @Success 200 {object} model.APIResponse[model.MatchReviewResult]
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /match-suggestions/review [post]
+func (h *Handler) ReviewMatchSuggestions(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	var requestBody model.ReviewMatchSuggestionsRequest
+	if err := c.Bind().JSON(&requestBody); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidRequestBody)
+	}
+	if len(requestBody.Accept) == 0 && len(requestBody.Reject) == 0 {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: "accept or reject must list at least one suggestion"})
+	}
+
+	result, err := h.repo.ReviewMatchSuggestions(ctx, requestBody.Accept, requestBody.Reject)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	if len(result.Accepted) > 0 {
+		accepted, err := h.repo.GetMatchSuggestionsByIDs(ctx, result.Accepted)
+		if err != nil {
+			return sendError(c, err)
+		}
+
+		var videoIDs []string
+		var gameIDs []int64
+		for _, suggestion := range accepted {
+			videoIDs = append(videoIDs, suggestion.VideoID)
+			gameIDs = append(gameIDs, int64(suggestion.GameID))
+		}
+		h.enrichNewGames(ctx, gameIDs)
+		h.inferVideoSeriesGames(ctx, videoIDs...)
+	}
+
+	return c.JSON(Response(result, nil))
+}
@@ -0,0 +1,171 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+// seedSuggestions stores two unmatched videos with pending suggestions:
+// vid01 is suggested a stored game and one from IGDB, vid02 a stored game.
+func (a *testApp) seedSuggestions(t *testing.T) {
+	t.Helper()
+
+	a.seed(t, `INSERT INTO games (id, name, url) VALUES (119133, 'Elden Ring', 'https://www.igdb.com/games/elden-ring')`)
+	a.seed(t, `INSERT INTO videos (id, title, thumbnail, published_at) VALUES
+		('vid01', 'Elden Ring Shadow EP.1', 'https://i.ytimg.com/vi/vid01/hqdefault.jpg', '2024-01-02 00:00:00'),
+		('vid02', 'Elden Ringo EP.1', 'https://i.ytimg.com/vi/vid02/hqdefault.jpg', '2024-01-03 00:00:00')`)
+	a.seed(t, `INSERT INTO match_suggestions (id, video_id, game_id, game_name, game_url, score, source) VALUES
+		(1, 'vid01', 119133, 'Elden Ring', 'https://www.igdb.com/games/elden-ring', 0.7, 'title'),
+		(2, 'vid01', 207025, 'Elden Ring: Shadow of the Erdtree', 'https://www.igdb.com/games/elden-ring-shadow-of-the-erdtree', 0.75, 'igdb'),
+		(3, 'vid02', 119133, 'Elden Ring', 'https://www.igdb.com/games/elden-ring', 0.85, 'title')`)
+}
+
+// matchSuggestions lists match suggestions, and returns their IDs in order.
+func (a *testApp) matchSuggestions(t *testing.T, query string) []int64 {
+	t.Helper()
+
+	var body model.APIResponse[[]model.MatchSuggestion]
+	if resp := a.do(t, http.MethodGet, "/api/match-suggestions"+query, "", &body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/match-suggestions%s status = %d", query, resp.StatusCode)
+	}
+
+	ids := make([]int64, len(body.Data))
+	for i, s := range body.Data {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+func TestGetMatchSuggestions(t *testing.T) {
+	app := newTestApp(t)
+	app.seedSuggestions(t)
+
+	var body model.APIResponse[[]model.MatchSuggestion]
+	app.do(t, http.MethodGet, "/api/match-suggestions", "", &body)
+	if body.Meta == nil || body.Meta.Total != 3 || len(body.Data) != 3 {
+		t.Fatalf("queue = %+v, want 3 suggestions", body)
+	}
+	first := body.Data[0]
+	if first.ID != 3 || first.VideoTitle != "Elden Ringo EP.1" || first.Game.ID != 119133 || first.Status != model.MatchSuggestionPending {
+		t.Fatalf("first suggestion = %+v, want the best one, for vid02", first)
+	}
+
+	tests := []struct {
+		query string
+		want  []int64
+	}{
+		{"?video_id=vid01", []int64{2, 1}},
+		{"?source=igdb", []int64{2}},
+		{"?min_score=0.75", []int64{3, 2}},
+		{"?status=rejected", []int64{}},
+	}
+	for _, tt := range tests {
+		if got := app.matchSuggestions(t, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("GET /api/match-suggestions%s = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	for _, query := range []string{"?source=manual", "?status=done", "?min_score=2"} {
+		if resp := app.do(t, http.MethodGet, "/api/match-suggestions"+query, "", nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("GET /api/match-suggestions%s status = %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestAcceptMatchSuggestion(t *testing.T) {
+	app := newTestApp(t)
+	app.seedSuggestions(t)
+
+	var body model.APIResponse[model.MatchSuggestion]
+	if resp := app.do(t, http.MethodPost, "/api/match-suggestions/2/accept", "", &body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("accept status = %d, want 200", resp.StatusCode)
+	}
+	if body.Data.Status != model.MatchSuggestionAccepted || body.Data.ReviewedAt == nil {
+		t.Fatalf("accepted suggestion = %+v, want it accepted", body.Data)
+	}
+
+	// The game from IGDB is added to the catalogue, and is the video's
+	// primary game.
+	if game := app.videoGame(t, "vid01"); game == nil || game.ID != 207025 {
+		t.Fatalf("vid01 game = %+v, want the accepted game", game)
+	}
+	if resp := app.do(t, http.MethodGet, "/api/games/207025", "", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET accepted game status = %d, want 200", resp.StatusCode)
+	}
+
+	// Accepting another game for the video adds it after the first.
+	app.do(t, http.MethodPost, "/api/match-suggestions/1/accept", "", nil)
+
+	var video model.APIResponse[model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos/vid01", "", &video)
+	if len(video.Data.Games) != 2 || video.Data.Games[0].ID != 207025 || video.Data.Games[1].ID != 119133 {
+		t.Fatalf("vid01 games = %+v, want both accepted games in order", video.Data.Games)
+	}
+
+	if resp := app.do(t, http.MethodPost, "/api/match-suggestions/2/reject", "", nil); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("rejecting an accepted suggestion status = %d, want 409", resp.StatusCode)
+	}
+	if resp := app.do(t, http.MethodPost, "/api/match-suggestions/99/accept", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("accepting a missing suggestion status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestReviewMatchSuggestions(t *testing.T) {
+	app := newTestApp(t)
+	app.seedSuggestions(t)
+	app.do(t, http.MethodPost, "/api/match-suggestions/1/reject", "", nil)
+
+	var body model.APIResponse[model.MatchReviewResult]
+	resp := app.do(t, http.MethodPost, "/api/match-suggestions/review", `{"accept": [3, 1], "reject": [2, 3, 99]}`, &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("review status = %d, want 200", resp.StatusCode)
+	}
+	if !slices.Equal(body.Data.Accepted, []int64{3}) || !slices.Equal(body.Data.Rejected, []int64{2}) {
+		t.Fatalf("review = %+v, want 3 accepted and 2 rejected", body.Data)
+	}
+
+	if got := app.matchSuggestions(t, ""); len(got) != 0 {
+		t.Fatalf("queue = %v, want it empty", got)
+	}
+	if got := app.matchSuggestions(t, "?status=rejected"); !slices.Equal(got, []int64{2, 1}) {
+		t.Fatalf("rejected = %v, want 2 and 1", got)
+	}
+	if game := app.videoGame(t, "vid02"); game == nil || game.ID != 119133 {
+		t.Fatalf("vid02 game = %+v, want the accepted game", game)
+	}
+
+	if resp := app.do(t, http.MethodPost, "/api/match-suggestions/review", `{}`, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("empty review status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestRejectedMatchIsNotProposedAgain(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES
+		(1, 'Hades', 'https://www.igdb.com/games/hades--1'),
+		(2, 'Hades II', 'https://www.igdb.com/games/hades-ii')`)
+	app.seed(t, `INSERT INTO videos (id, title, thumbnail, published_at)
+		VALUES ('vid01', 'Hades EP.1', 'https://i.ytimg.com/vi/vid01/hqdefault.jpg', '2024-01-02 00:00:00')`)
+	app.seed(t, `INSERT INTO match_suggestions (video_id, game_id, game_name, game_url, score, source, status)
+		VALUES ('vid01', 1, 'Hades', 'https://www.igdb.com/games/hades--1', 1, 'title', 'rejected')`)
+
+	if err := app.handler.MatchStoredVideos(context.Background(), "vid01"); err != nil {
+		t.Fatalf("match: %v", err)
+	}
+
+	// Hades would have been assigned; without it, Hades II is only close
+	// enough to suggest.
+	if game := app.videoGame(t, "vid01"); game != nil {
+		t.Fatalf("vid01 game = %+v, want the rejected game left off", game)
+	}
+	if got := app.suggestions(t); len(got) != 2 || got[1].gameID != 2 {
+		t.Fatalf("suggestions = %+v, want Hades II suggested", got)
+	}
+	if got := app.matchSuggestions(t, "?status=rejected"); len(got) != 1 {
+		t.Fatalf("rejected = %v, want Hades still rejected", got)
+	}
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/K0ng2/zeedzad/igdb"
+	"github.com/K0ng2/zeedzad/matcher"
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+// Auto-matching thresholds. The best game found for a video is assigned if
+// it scores at least autoMatchScore and leads the next best by at least
+// autoMatchMargin. Otherwise the best maxSuggestions games scoring at least
+// suggestionScore are kept as suggestions for review.
+const (
+	autoMatchScore  = 0.9
+	autoMatchMargin = 0.1
+	suggestionScore = 0.5
+	maxSuggestions  = 3
+	// maxTitleCandidates is how many of the names read from a title are
+	// looked for.
+	maxTitleCandidates = 3
+)
+
+// gameMatch is a game the matcher found for a video, and how well it
+// matches.
+type gameMatch struct {
+	game   repoModel.Games
+	score  float64
+	source string
+}
+
+// gameMatcher matches the videos of a page with games. It compares their
+// titles with every stored game, and searches IGDB for the names it can't
+// find, each name once.
+type gameMatcher struct {
+	igdb   *igdb.Client
+	games  []repoModel.Games
+	stored map[int32]bool
+
+	searches map[string][]igdb.GameSearchResult
+	// igdbFailed is set once an IGDB search fails, so that the rest of the
+	// page isn't searched for.
+	igdbFailed bool
+}
+
+// matchVideos looks for the games of newly stored videos by their titles.
+// A game the matcher is confident of is assigned to the video, and its
+// metadata fetched if it is new; a video it isn't sure about gets
+// suggestions for someone to review. Games rejected for a video are never
+// proposed for it again. Failures are only logged: the videos are stored,
+// and can be matched by hand.
+func (h *Handler) matchVideos(ctx context.Context, videos []repoModel.Videos) {
+	titles := make(map[string]matcher.Title, len(videos))
+	for _, video := range videos {
+		if video.GameID != nil {
+			continue
+		}
+		if title := matcher.ParseTitle(video.Title); len(title.Candidates) > 0 {
+			titles[*video.ID] = title
+		}
+	}
+	if len(titles) == 0 {
+		return
+	}
+
+	games, err := h.repo.GetAllGames(ctx)
+	if err != nil {
+		fmt.Printf("failed to load games to match videos with: %v\n", err)
+		return
+	}
+
+	rejected, err := h.repo.GetRejectedMatches(ctx, slices.Collect(maps.Keys(titles)))
+	if err != nil {
+		fmt.Printf("failed to load rejected matches: %v\n", err)
+		return
+	}
+
+	m := &gameMatcher{
+		igdb:     h.igdb,
+		games:    games,
+		stored:   make(map[int32]bool, len(games)),
+		searches: make(map[string][]igdb.GameSearchResult),
+	}
+	for _, game := range games {
+		m.stored[*game.ID] = true
+	}
+
+	var newGames []repoModel.Games
+	var assigned []repoModel.VideoGames
+	var suggestions []repoModel.MatchSuggestions
+
+	for _, video := range videos {
+		title, ok := titles[*video.ID]
+		if !ok {
+			continue
+		}
+
+		matches := m.match(title, rejected[*video.ID])
+		if len(matches) == 0 {
+			continue
+		}
+
+		if best := matches[0]; best.score >= autoMatchScore && (len(matches) == 1 || best.score-matches[1].score >= autoMatchMargin) {
+			if best.source == model.MatchSourceIGDB && !slices.ContainsFunc(newGames, func(g repoModel.Games) bool { return *g.ID == *best.game.ID }) {
+				newGames = append(newGames, best.game)
+			}
+			assigned = append(assigned, repoModel.VideoGames{VideoID: *video.ID, GameID: *best.game.ID})
+			continue
+		}
+
+		for _, match := range matches[:min(len(matches), maxSuggestions)] {
+			suggestions = append(suggestions, repoModel.MatchSuggestions{
+				VideoID:  *video.ID,
+				GameID:   *match.game.ID,
+				GameName: match.game.Name,
+				GameURL:  match.game.URL,
+				Score:    match.score,
+				Source:   match.source,
+			})
+		}
+	}
+
+	if err := h.repo.SaveMatches(ctx, newGames, assigned, suggestions); err != nil {
+		fmt.Printf("failed to save the games matched with a page of videos: %v\n", err)
+		return
+	}
+
+	ids := make([]int64, len(newGames))
+	for i, game := range newGames {
+		ids[i] = int64(*game.ID)
+	}
+	h.enrichNewGames(ctx, ids)
+}
+
+// match returns the games matching the names read from a title, best
+// first, leaving out the rejected ones. A game's score is how alike its
+// name is to the name it matched, weighed by how likely that part of the
+// title is to name the game. IGDB is only searched if no stored game is a
+// confident match.
+func (m *gameMatcher) match(title matcher.Title, rejected []int32) []gameMatch {
+	candidates := title.Candidates[:min(len(title.Candidates), maxTitleCandidates)]
+	found := make(map[int32]gameMatch)
+
+	add := func(game repoModel.Games, score float64, source string) {
+		if score < suggestionScore || slices.Contains(rejected, *game.ID) {
+			return
+		}
+		if prev, ok := found[*game.ID]; ok && prev.score >= score {
+			return
+		}
+		found[*game.ID] = gameMatch{game: game, score: score, source: source}
+	}
+
+	for _, candidate := range candidates {
+		for _, game := range m.games {
+			add(game, matcher.Similarity(candidate.Name, game.Name)*candidate.Weight, model.MatchSourceTitle)
+		}
+	}
+
+	best := 0.0
+	for _, match := range found {
+		best = max(best, match.score)
+	}
+
+	if best < autoMatchScore {
+		for _, candidate := range candidates {
+			for _, result := range m.searchIGDB(candidate.Name) {
+				id := int32(result.ID)
+				if m.stored[id] {
+					// It was scored by its stored name already.
+					continue
+				}
+				game := repoModel.Games{ID: &id, Name: result.Name, URL: result.URL}
+				add(game, matcher.Similarity(candidate.Name, result.Name)*candidate.Weight, model.MatchSourceIGDB)
+			}
+		}
+	}
+
+	matches := make([]gameMatch, 0, len(found))
+	for _, match := range found {
+		matches = append(matches, match)
+	}
+	slices.SortFunc(matches, func(a, b gameMatch) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(*a.game.ID, *b.game.ID))
+	})
+
+	return matches
+}
+
+// searchIGDB searches IGDB for a name, once per page.
+func (m *gameMatcher) searchIGDB(name string) []igdb.GameSearchResult {
+	key := matcher.Normalize(name)
+	if results, ok := m.searches[key]; ok || m.igdbFailed {
+		return results
+	}
+
+	results, err := m.igdb.SearchGames(name)
+	if err != nil {
+		fmt.Printf("failed to search igdb for %q, not searching it for the rest of the page: %v\n", name, err)
+		m.igdbFailed = true
+		return nil
+	}
+
+	m.searches[key] = results
+	return results
+}
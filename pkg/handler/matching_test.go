@@ -0,0 +1,154 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/igdbtest"
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/youtubetest"
+)
+
+// uploadTitled adds videos with the given titles to the channel, as vid01,
+// vid02 and so on.
+func (a *testApp) uploadTitled(titles ...string) {
+	for i, title := range titles {
+		a.youtube.Upload(testChannelID, youtubetest.Video{
+			ID:          fmt.Sprintf("vid%02d", i+1),
+			Title:       title,
+			PublishedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i),
+		})
+	}
+}
+
+// suggestion is a row of match_suggestions.
+type suggestion struct {
+	videoID string
+	gameID  int64
+	source  string
+	score   float64
+}
+
+// suggestions returns the stored match suggestions, by video and game.
+func (a *testApp) suggestions(t *testing.T) []suggestion {
+	t.Helper()
+
+	rows, err := a.d1.SQLite.Conn().Query(`SELECT video_id, game_id, source, score FROM match_suggestions ORDER BY video_id, game_id`)
+	if err != nil {
+		t.Fatalf("query suggestions: %v", err)
+	}
+	defer rows.Close()
+
+	var got []suggestion
+	for rows.Next() {
+		var s suggestion
+		if err := rows.Scan(&s.videoID, &s.gameID, &s.source, &s.score); err != nil {
+			t.Fatalf("scan suggestion: %v", err)
+		}
+		got = append(got, s)
+	}
+	return got
+}
+
+// videoGame returns the primary game of a video.
+func (a *testApp) videoGame(t *testing.T, videoID string) *model.GameInfo {
+	t.Helper()
+
+	var body model.APIResponse[model.VideoResponse]
+	a.do(t, http.MethodGet, "/api/videos/"+videoID, "", &body)
+	return body.Data.Game
+}
+
+func TestSyncMatchesStoredGames(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES
+		(119133, 'Elden Ring', 'https://www.igdb.com/games/elden-ring'),
+		(26758, 'Hades II', 'https://www.igdb.com/games/hades-ii')`)
+	app.uploadTitled(
+		"[Elden Ring] EP.3 บอสโหดมาก",
+		"Hades 2 ตอนที่ 5",
+	)
+
+	app.sync(t, "")
+
+	if game := app.videoGame(t, "vid01"); game == nil || game.ID != 119133 {
+		t.Fatalf("vid01 game = %+v, want Elden Ring", game)
+	}
+	if game := app.videoGame(t, "vid02"); game == nil || game.ID != 26758 {
+		t.Fatalf("vid02 game = %+v, want Hades II", game)
+	}
+
+	// Confident matches among the stored games don't need IGDB.
+	if searches := app.igdb.Searches(); len(searches) != 0 {
+		t.Fatalf("searched igdb for %q, want no searches", searches)
+	}
+}
+
+func TestSyncMatchesIGDBGames(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.igdb.AddGames(igdbtest.Game{ID: 1942, Name: "Phasmophobia", URL: "https://www.igdb.com/games/phasmophobia", Summary: "A co-op ghost hunt."})
+	app.uploadTitled(
+		"ผีบ้านสุดหลอน | Phasmophobia",
+		"ล่าผีกับเพื่อน | Phasmophobia",
+	)
+
+	app.sync(t, "")
+
+	for _, id := range []string{"vid01", "vid02"} {
+		if game := app.videoGame(t, id); game == nil || game.ID != 1942 || game.Name != "Phasmophobia" {
+			t.Fatalf("%s game = %+v, want Phasmophobia from igdb", id, game)
+		}
+	}
+
+	// A name is searched for once a page.
+	if searches := app.igdb.Searches(); !slices.Equal(searches, []string{"Phasmophobia"}) {
+		t.Fatalf("searched igdb for %q, want Phasmophobia once", searches)
+	}
+
+	// The game added is enriched with its metadata.
+	var game model.APIResponse[model.GameResponse]
+	app.do(t, http.MethodGet, "/api/games/1942", "", &game)
+	if game.Data.Summary == nil || *game.Data.Summary != "A co-op ghost hunt." {
+		t.Fatalf("game = %+v, want its summary from igdb", game.Data)
+	}
+}
+
+func TestSyncSuggestsUncertainMatches(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES (119133, 'Elden Ring', 'https://www.igdb.com/games/elden-ring')`)
+	app.igdb.AddGames(igdbtest.Game{ID: 207025, Name: "Elden Ring: Shadow of the Erdtree", URL: "https://www.igdb.com/games/elden-ring-shadow-of-the-erdtree"})
+	app.uploadTitled("Elden Ring Shadow EP.1 ลุยกับเพื่อน")
+
+	app.sync(t, "")
+
+	if game := app.videoGame(t, "vid01"); game != nil {
+		t.Fatalf("vid01 game = %+v, want it left unmatched", game)
+	}
+
+	got := app.suggestions(t)
+	if len(got) != 2 || got[0].gameID != 119133 || got[0].source != model.MatchSourceTitle || got[1].gameID != 207025 || got[1].source != model.MatchSourceIGDB {
+		t.Fatalf("suggestions = %+v, want Elden Ring from the title and its DLC from igdb", got)
+	}
+}
+
+func TestSyncStoresVideosWhenIGDBFails(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.igdb.Fail(1, http.StatusInternalServerError)
+	app.uploadTitled("Phasmophobia EP.1", "Stardew Valley EP.1")
+
+	if got := app.sync(t, ""); got.Added != 2 || got.Errors != 0 {
+		t.Fatalf("sync = %+v, want both videos added", got)
+	}
+
+	// IGDB isn't searched again for the rest of the page once it fails.
+	if searches := app.igdb.Searches(); len(searches) != 1 {
+		t.Fatalf("searched igdb for %q, want one failed search", searches)
+	}
+}
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	_ "time/tzdata" // for the Pacific time zone on hosts without tzdata
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+// DefaultQuotaBudget is the daily quota of a new YouTube Data API project.
+const DefaultQuotaBudget = 10000
+
+// quotaCosts are the units a call to each endpoint costs. Every list call
+// the app makes costs one unit, however many results it asks for.
+var quotaCosts = map[string]int{
+	"channels.list":      1,
+	"playlistItems.list": 1,
+	"videos.list":        1,
+}
+
+// syncPageQuotaCost is what a sync spends on a page of the uploads
+// playlist: the page itself, and a videos.list call for its new videos.
+const syncPageQuotaCost = 2
+
+// quotaTimeZone is where YouTube's day starts: quotas reset at midnight
+// Pacific time.
+var quotaTimeZone = func() *time.Location {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}()
+
+var errQuotaExhausted = errors.New("daily YouTube quota budget exhausted")
+
+// quotaLedger serializes the budget check and the record of each call, so
+// concurrent syncs can't both take the last units.
+type quotaLedger struct {
+	mu     sync.Mutex
+	budget int
+}
+
+// SetQuotaBudget sets how many YouTube quota units the app may spend a day.
+func (h *Handler) SetQuotaBudget(units int) {
+	h.quota.budget = units
+}
+
+// quotaDay is the ledger day of t, its date in Pacific time.
+func quotaDay(t time.Time) string {
+	return t.In(quotaTimeZone).Format(time.DateOnly)
+}
+
+// quotaResetAt is when the quota next resets after t.
+func quotaResetAt(t time.Time) time.Time {
+	year, month, day := t.In(quotaTimeZone).Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, quotaTimeZone)
+}
+
+// quotaUsed returns the units spent on day.
+func (h *Handler) quotaUsed(ctx context.Context, day string) (int, error) {
+	usage, err := h.repo.GetQuotaUsage(ctx, day)
+	if err != nil {
+		return 0, err
+	}
+
+	used := 0
+	for _, row := range usage {
+		used += int(row.Units)
+	}
+	return used, nil
+}
+
+// haveQuota reports whether units more fit in today's budget.
+func (h *Handler) haveQuota(ctx context.Context, units int) (bool, *syncError) {
+	used, err := h.quotaUsed(ctx, quotaDay(time.Now()))
+	if err != nil {
+		return false, databaseSyncError("failed to read quota usage", err)
+	}
+
+	return used+units <= h.quota.budget, nil
+}
+
+// spendQuota records a call to endpoint in today's ledger, before it is
+// made: YouTube charges for failed calls too. If the call would take the
+// day over the budget, it is neither recorded nor to be made.
+func (h *Handler) spendQuota(ctx context.Context, endpoint string) *syncError {
+	h.quota.mu.Lock()
+	defer h.quota.mu.Unlock()
+
+	now := time.Now()
+	day := quotaDay(now)
+	cost := quotaCosts[endpoint]
+
+	used, err := h.quotaUsed(ctx, day)
+	if err != nil {
+		return databaseSyncError("failed to read quota usage", err)
+	}
+	if used+cost > h.quota.budget {
+		return quotaSyncError(now)
+	}
+
+	if err := h.repo.RecordQuotaUsage(ctx, day, endpoint, int32(cost)); err != nil {
+		return databaseSyncError("failed to record quota usage", err)
+	}
+	if meter, ok := ctx.Value(quotaMeterKey{}).(*atomic.Int64); ok {
+		meter.Add(int64(cost))
+	}
+
+	return nil
+}
+
+type quotaMeterKey struct{}
+
+// withQuotaMeter returns a context that counts the quota spent under it,
+// so a sync run can tell what it spent from what concurrent syncs did.
+func withQuotaMeter(ctx context.Context) (context.Context, *atomic.Int64) {
+	meter := &atomic.Int64{}
+	return context.WithValue(ctx, quotaMeterKey{}, meter), meter
+}
+
+func quotaSyncError(now time.Time) *syncError {
+	resetAt := quotaResetAt(now)
+
+	return &syncError{
+		message:    fmt.Sprintf("%v until %s", errQuotaExhausted, resetAt.Format(time.RFC3339)),
+		statusCode: http.StatusTooManyRequests,
+		cause:      errQuotaExhausted,
+		retryAfter: resetAt.Sub(now),
+	}
+}
+
+func isQuotaExhausted(err *syncError) bool {
+	return err != nil && errors.Is(err.cause, errQuotaExhausted)
+}
+
+// GetQuotaUsage godoc
+// @Summary Get today's YouTube quota usage
+// @Description Get the YouTube Data API units spent today, by endpoint, against the daily budget. Days start at midnight Pacific time, when YouTube resets the quota. With backfill_channel_id, also project what a backfill of that channel would cost, from the size of its uploads playlist when it was last synced; the projection spends no quota.
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param backfill_channel_id query string false "Channel to project a backfill for"
+// @Param max_results query int false "max_results of the backfill to project; no limit if omitted"
+// @Success 200 {object} model.APIResponse[model.QuotaUsage]
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /admin/quota [get]
+func (h *Handler) GetQuotaUsage(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	now := time.Now()
+	day := quotaDay(now)
+
+	rows, err := h.repo.GetQuotaUsage(ctx, day)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	usage := model.QuotaUsage{
+		Day:       day,
+		Budget:    h.quota.budget,
+		ResetsAt:  quotaResetAt(now),
+		Endpoints: make([]model.QuotaEndpointUsage, len(rows)),
+	}
+	for i, row := range rows {
+		usage.Endpoints[i] = model.QuotaEndpointUsage{Endpoint: row.Endpoint, Calls: int(row.Calls), Units: int(row.Units)}
+		usage.Used += int(row.Units)
+	}
+	usage.Remaining = max(usage.Budget-usage.Used, 0)
+
+	if channelID := c.Query("backfill_channel_id"); channelID != "" {
+		if _, err := h.repo.GetChannelByID(ctx, channelID); err != nil {
+			return sendError(c, err)
+		}
+
+		state, syncErr := h.loadSyncState(ctx, channelID)
+		if syncErr != nil {
+			return sendSyncError(c, syncErr)
+		}
+
+		projection, syncErr := h.projectBackfill(ctx, state, fiber.Query(c, "max_results", 0))
+		if syncErr != nil {
+			return sendSyncError(c, syncErr)
+		}
+		projection.FitsToday = projection.Units <= usage.Remaining
+		usage.Backfill = projection
+	}
+
+	return c.JSON(Response(usage, nil))
+}
+
+// projectBackfill estimates the quota a backfill of a channel would spend:
+// a playlistItems.list call per page left to read, and a videos.list call
+// for each page with videos to look up, as if they came in pages of their
+// own. The size of the playlist is the one the last sync read, or the
+// number of stored videos if the channel has never been synced; reading it
+// again would spend quota itself.
+func (h *Handler) projectBackfill(ctx context.Context, state *repoModel.SyncState, maxResults int) (*model.BackfillProjection, *syncError) {
+	channelID := *state.ChannelID
+
+	// A backfill that was stopped resumes after the videos it has seen.
+	var since *time.Time
+	if state.BackfillPageToken != nil {
+		since = state.BackfillStartedAt
+	}
+	counts, err := h.repo.CountChannelVideos(ctx, channelID, since)
+	if err != nil {
+		return nil, databaseSyncError("failed to count channel videos", err)
+	}
+
+	videos := int(counts.Stored)
+	if state.PlaylistItemCount != nil {
+		videos = int(*state.PlaylistItemCount)
+	}
+
+	remaining := max(videos-int(counts.SeenSince), 0)
+	pages := pagesOf(remaining)
+	if maxResults > 0 {
+		// A backfill stops at the end of the page that reaches maxResults.
+		pages = min(pages, pagesOf(maxResults))
+	}
+	lookups := min(pages, pagesOf(max(videos-int(counts.WithDetails), 0)))
+
+	return &model.BackfillProjection{
+		ChannelID: channelID,
+		Videos:    videos,
+		Remaining: remaining,
+		Pages:     pages,
+		Units:     pages*quotaCosts["playlistItems.list"] + lookups*quotaCosts["videos.list"],
+	}, nil
+}
+
+// checkBackfillBudget refuses a backfill projected to spend more than is
+// left of today's budget. One that would spend more than the whole budget
+// can't be run in a day; it has to be split up with max_results.
+func (h *Handler) checkBackfillBudget(ctx context.Context, state *repoModel.SyncState, maxResults int) *syncError {
+	projection, syncErr := h.projectBackfill(ctx, state, maxResults)
+	if syncErr != nil {
+		return syncErr
+	}
+
+	now := time.Now()
+	used, err := h.quotaUsed(ctx, quotaDay(now))
+	if err != nil {
+		return databaseSyncError("failed to read quota usage", err)
+	}
+
+	// Even a backfill with nothing left to read reads the first page.
+	cost := max(projection.Units, syncPageQuotaCost)
+	if used+cost <= h.quota.budget {
+		return nil
+	}
+
+	syncErr = quotaSyncError(now)
+	syncErr.message = fmt.Sprintf("backfill projected to spend %d quota units, %d left until %s",
+		cost, max(h.quota.budget-used, 0), quotaResetAt(now).Format(time.RFC3339))
+	if cost > h.quota.budget {
+		syncErr.message = fmt.Sprintf("backfill projected to spend %d quota units, more than the daily budget of %d; set max_results to backfill part of the channel",
+			cost, h.quota.budget)
+		syncErr.retryAfter = 0
+	}
+	return syncErr
+}
+
+// pagesOf is the number of pages n results take.
+func pagesOf(n int) int {
+	return int(math.Ceil(float64(n) / youtubeMaxPageSize))
+}
+
+// sendSyncError writes a syncError, telling the client when to retry if
+// the quota ran out.
+func sendSyncError(c fiber.Ctx, err *syncError) error {
+	if err.retryAfter > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(err.retryAfter.Seconds()))))
+	}
+
+	return c.Status(err.statusCode).JSON(model.Error{Error: err.message})
+}
@@ -0,0 +1,192 @@
+package handler_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/handler"
+	"github.com/K0ng2/zeedzad/model"
+)
+
+func (a *testApp) quotaUsage(t *testing.T, query string) model.QuotaUsage {
+	t.Helper()
+
+	var body model.APIResponse[model.QuotaUsage]
+	resp := a.do(t, http.MethodGet, "/api/admin/quota"+query, "", &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("quota%s: status = %d, want 200", query, resp.StatusCode)
+	}
+	return body.Data
+}
+
+func TestQuotaDayStartsAtPacificMidnight(t *testing.T) {
+	tests := []struct {
+		now     time.Time
+		day     string
+		resetAt time.Time
+	}{
+		// The last minute of a day in standard time...
+		{time.Date(2024, 3, 10, 7, 59, 0, 0, time.UTC), "2024-03-09", time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)},
+		// ...and the first, which ends an hour early in daylight saving time.
+		{time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC), "2024-03-10", time.Date(2024, 3, 11, 7, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 7, 1, 6, 59, 0, 0, time.UTC), "2024-06-30", time.Date(2024, 7, 1, 7, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 7, 1, 7, 0, 0, 0, time.UTC), "2024-07-01", time.Date(2024, 7, 2, 7, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if day := handler.QuotaDay(tt.now); day != tt.day {
+			t.Errorf("QuotaDay(%v) = %s, want %s", tt.now, day, tt.day)
+		}
+		if resetAt := handler.QuotaResetAt(tt.now); !resetAt.Equal(tt.resetAt) {
+			t.Errorf("QuotaResetAt(%v) = %v, want %v", tt.now, resetAt.UTC(), tt.resetAt)
+		}
+	}
+}
+
+func TestSyncStopsAtQuotaBudget(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.PageSize = 1
+	app.upload(1, 3)
+	app.handler.SetQuotaBudget(3)
+
+	// Two units for the first page and its lookup, and one for the second
+	// page; the second lookup would go over.
+	got := app.sync(t, "")
+	if got.Added != 1 || !got.Deferred || got.Complete {
+		t.Fatalf("sync = %+v, want 1 added and deferred", got)
+	}
+	if n := app.youtube.Requests("videos"); n != 1 {
+		t.Fatalf("videos.list called %d times, want the refused call not made", n)
+	}
+
+	usage := app.quotaUsage(t, "")
+	if usage.Used != 3 || usage.Remaining != 0 || len(usage.Endpoints) != 2 {
+		t.Fatalf("usage = %+v, want the 3 units spent", usage)
+	}
+
+	// With the budget spent, a sync isn't started.
+	resp := app.do(t, http.MethodPost, "/api/videos/sync?api_key=key", "", nil)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("sync over budget: status = %d, want 429", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") == "" {
+		t.Fatal("sync over budget: no Retry-After")
+	}
+	if usage := app.quotaUsage(t, ""); usage.Used != 3 {
+		t.Fatalf("used = %d, want 3", usage.Used)
+	}
+}
+
+func TestBackfillDeferredByQuotaResumes(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.PageSize = 2
+	app.upload(1, 5)
+	app.handler.SetQuotaBudget(4)
+
+	got := app.sync(t, "&mode=backfill")
+	if got.Added != 4 || !got.Deferred || got.Complete {
+		t.Fatalf("backfill = %+v, want 2 pages and deferred", got)
+	}
+
+	var token string
+	if err := app.d1.SQLite.Conn().QueryRow(`SELECT backfill_page_token FROM sync_state`).Scan(&token); err != nil {
+		t.Fatalf("read page token: %v", err)
+	}
+	if token != "page-4" {
+		t.Fatalf("backfill_page_token = %q, want the third page", token)
+	}
+
+	// After the reset, the backfill picks up from the page it stopped at.
+	app.handler.SetQuotaBudget(100)
+	pagesBefore := app.youtube.Requests("playlistItems")
+	got = app.sync(t, "&mode=backfill")
+	if got.Added != 1 || got.Deferred || !got.Complete {
+		t.Fatalf("resumed backfill = %+v, want the last video", got)
+	}
+	if pages := app.youtube.Requests("playlistItems") - pagesBefore; pages != 1 {
+		t.Fatalf("resumed backfill read %d pages, want 1", pages)
+	}
+}
+
+func TestBackfillRefusedOverProjectedCost(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 120)
+
+	// The first sync stores 50 videos with their details and notes that
+	// the playlist has 120, for 2 units.
+	app.sync(t, "")
+
+	// The rest would take 3 pages and 2 lookups.
+	usage := app.quotaUsage(t, "?backfill_channel_id="+testChannelID)
+	want := model.BackfillProjection{ChannelID: testChannelID, Videos: 120, Remaining: 120, Pages: 3, Units: 5, FitsToday: true}
+	if usage.Backfill == nil || *usage.Backfill != want {
+		t.Fatalf("backfill = %+v, want %+v", usage.Backfill, want)
+	}
+
+	app.handler.SetQuotaBudget(6)
+	before := app.youtube.Requests("playlistItems")
+	resp := app.do(t, http.MethodPost, "/api/videos/sync?api_key=key&mode=backfill", "", nil)
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("backfill over budget: status = %d, want 429 with Retry-After", resp.StatusCode)
+	}
+	if n := app.youtube.Requests("playlistItems") - before; n != 0 {
+		t.Fatalf("refused backfill read %d pages, want none", n)
+	}
+
+	// One page fits.
+	if got := app.sync(t, "&mode=backfill&max_results=50"); got.Added != 0 || got.Skipped != 50 {
+		t.Fatalf("capped backfill = %+v, want the first page", got)
+	}
+
+	// The other 70 videos take 4 units, more than the whole budget, so
+	// waiting for the reset wouldn't help.
+	app.handler.SetQuotaBudget(3)
+	var body model.Error
+	resp = app.do(t, http.MethodPost, "/api/videos/sync?api_key=key&mode=backfill", "", &body)
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "" || !strings.Contains(body.Error, "max_results") {
+		t.Fatalf("backfill over the daily budget: status = %d, error %q", resp.StatusCode, body.Error)
+	}
+}
+
+func TestGetQuotaUsage(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 3)
+
+	usage := app.quotaUsage(t, "")
+	if usage.Used != 0 || usage.Budget != handler.DefaultQuotaBudget || usage.Backfill != nil {
+		t.Fatalf("usage = %+v, want nothing spent", usage)
+	}
+	if usage.Day != handler.QuotaDay(time.Now()) || !usage.ResetsAt.After(time.Now()) {
+		t.Fatalf("day = %s, resets at %v", usage.Day, usage.ResetsAt)
+	}
+
+	app.sync(t, "")
+	usage = app.quotaUsage(t, "")
+	want := []model.QuotaEndpointUsage{
+		{Endpoint: "playlistItems.list", Calls: 1, Units: 1},
+		{Endpoint: "videos.list", Calls: 1, Units: 1},
+	}
+	if usage.Used != 2 || usage.Remaining != handler.DefaultQuotaBudget-2 || len(usage.Endpoints) != 2 ||
+		usage.Endpoints[0] != want[0] || usage.Endpoints[1] != want[1] {
+		t.Fatalf("usage = %+v, want %+v", usage, want)
+	}
+
+	// Projecting a backfill costs nothing.
+	before := app.youtube.Requests("playlistItems")
+	usage = app.quotaUsage(t, "?backfill_channel_id="+testChannelID)
+	if b := usage.Backfill; b == nil || b.Videos != 3 || b.Pages != 1 || b.Units != 1 || !b.FitsToday {
+		t.Fatalf("backfill = %+v, want one page with nothing to look up", b)
+	}
+	if usage.Used != 2 || app.youtube.Requests("playlistItems") != before {
+		t.Fatalf("used = %d, want the projection not to call YouTube", usage.Used)
+	}
+
+	if resp := app.do(t, http.MethodGet, "/api/admin/quota?backfill_channel_id=UCnope", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown channel: status = %d, want 404", resp.StatusCode)
+	}
+}
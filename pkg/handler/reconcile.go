@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"google.golang.org/api/youtube/v3"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+// GetVideoEdits godoc
+// @Summary Get a video's edit history
+// @Description Get the changes the sync made to a video's title, thumbnail and status, oldest first
+// @Tags videos
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Video ID"
+// @Success 200 {object} model.APIResponse[[]model.VideoEdit]
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /videos/{id}/edits [get]
+func (h *Handler) GetVideoEdits(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	videoID := c.Params("id")
+
+	if _, err := h.repo.GetVideoByID(ctx, videoID); err != nil {
+		return sendError(c, err)
+	}
+
+	edits, err := h.repo.GetVideoEdits(ctx, videoID)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.JSON(Response(edits, nil))
+}
+
+// playlistItemStatus reads whether the video of a playlist item is
+// available. Playlists keep listing videos that were made private, titled
+// "Private video", and deleted ones, titled "Deleted video" with no privacy
+// status.
+func playlistItemStatus(item *youtube.PlaylistItem) string {
+	if item.Status == nil {
+		return model.VideoStatusAvailable
+	}
+
+	switch item.Status.PrivacyStatus {
+	case "private":
+		return model.VideoStatusPrivate
+	case "privacyStatusUnspecified":
+		return model.VideoStatusRemoved
+	}
+	return model.VideoStatusAvailable
+}
+
+// reconcileVideo brings a stored video in line with its status on YouTube
+// and, if item is given, with its title and thumbnail in the playlist. It
+// reports whether anything changed.
+func (h *Handler) reconcileVideo(ctx context.Context, stored repoModel.Videos, status string, item *youtube.PlaylistItem) (bool, error) {
+	video, edits := h.reconciledVideo(stored, status, item)
+
+	if err := h.repo.ReconcileVideo(ctx, video, edits); err != nil {
+		return false, err
+	}
+
+	return len(edits) > 0, nil
+}
+
+// reconciledVideo returns a stored video as reconcileVideo would save it,
+// with the edits that makes.
+func (h *Handler) reconciledVideo(stored repoModel.Videos, status string, item *youtube.PlaylistItem) (repoModel.Videos, []repoModel.VideoEdits) {
+	now := time.Now().UTC()
+	video := stored
+
+	var edits []repoModel.VideoEdits
+	edit := func(field string, oldValue, newValue *string) {
+		edits = append(edits, repoModel.VideoEdits{
+			VideoID:   *stored.ID,
+			Field:     field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			ChangedAt: now,
+		})
+	}
+
+	if status != stored.Status {
+		edit("status", &stored.Status, &status)
+		video.Status = status
+	}
+
+	// The items of private and deleted videos don't carry their real title
+	// or thumbnail.
+	if item != nil && status == model.VideoStatusAvailable {
+		if title := item.Snippet.Title; title != stored.Title {
+			edit("title", &stored.Title, &title)
+			video.Title = title
+		}
+
+		thumbnail := toNullableString(h.extractThumbnailURL(item.Snippet.Thumbnails))
+		if thumbnail != nil && (stored.Thumbnail == nil || *thumbnail != *stored.Thumbnail) {
+			edit("thumbnail", stored.Thumbnail, thumbnail)
+			video.Thumbnail = thumbnail
+		}
+	}
+
+	return video, edits
+}
+
+// reconcileRecentVideos reconciles the stored videos an incremental sync
+// found past its checkpoint, so renamed, private and deleted videos are
+// noticed without a backfill. items are the rest of the page the checkpoint
+// was on; if they are fewer than a page, the next page is read too. Running
+// out of quota for it only skips the check until the next sync.
+func (h *Handler) reconcileRecentVideos(ctx context.Context, service *youtube.Service, state *repoModel.SyncState, items []*youtube.PlaylistItem, nextToken string, stats *videoSyncStats) *syncError {
+	if len(items) == 0 {
+		return nil
+	}
+
+	if len(items) < recentReconcileWindow && nextToken != "" {
+		more, _, syncErr := h.fetchPlaylistPage(ctx, service, state, nextToken)
+		if isQuotaExhausted(syncErr) {
+			return nil
+		}
+		if syncErr != nil {
+			return syncErr
+		}
+		items = append(items, more...)
+	}
+	items = items[:min(len(items), recentReconcileWindow)]
+
+	stored, err := h.repo.GetStoredVideos(ctx, playlistVideoIDs(items))
+	if err != nil {
+		return databaseSyncError("failed to check existing videos", err)
+	}
+
+	for _, item := range items {
+		video, ok := stored[item.Snippet.ResourceId.VideoId]
+		if !ok {
+			continue
+		}
+
+		changed, err := h.reconcileVideo(ctx, video, playlistItemStatus(item), item)
+		if err != nil {
+			fmt.Printf("failed to update video %s: %v\n", *video.ID, err)
+			stats.fail(*video.ID, err)
+			continue
+		}
+		if changed {
+			stats.updated++
+		}
+	}
+
+	return nil
+}
+
+// markMissingVideosRemoved marks the videos that videos.list no longer
+// returns as removed. videos.list can't tell a private video from a deleted
+// one; a private video is marked private again once a sync finds it in the
+// playlist.
+func (h *Handler) markMissingVideosRemoved(ctx context.Context, ids []string) error {
+	stored, err := h.repo.GetStoredVideos(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, video := range stored {
+		if video.Status != model.VideoStatusAvailable {
+			continue
+		}
+		if _, err := h.reconcileVideo(ctx, video, model.VideoStatusRemoved, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// detectRemovedVideos runs at the end of a complete backfill and marks the
+// channel's videos that it didn't find in the playlist as removed. Each one
+// is looked up with videos.list first: a video uploaded after the backfill
+// passed the top of the playlist hasn't been seen either.
+func (h *Handler) detectRemovedVideos(ctx context.Context, service *youtube.Service, state *repoModel.SyncState, stats *videoSyncStats) *syncError {
+	if state.BackfillStartedAt == nil {
+		return nil
+	}
+
+	unseen, err := h.repo.GetVideosNotSeenSince(ctx, *state.ChannelID, *state.BackfillStartedAt)
+	if err != nil {
+		return databaseSyncError("failed to find unseen videos", err)
+	}
+	if len(unseen) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(unseen))
+	for i, video := range unseen {
+		ids[i] = *video.ID
+	}
+
+	found, syncErr := h.listVideos(ctx, service, []string{"id"}, ids)
+	if syncErr != nil {
+		return syncErr
+	}
+
+	for _, video := range unseen {
+		if found[*video.ID] != nil {
+			continue
+		}
+
+		changed, err := h.reconcileVideo(ctx, video, model.VideoStatusRemoved, nil)
+		if err != nil {
+			fmt.Printf("failed to mark video %s removed: %v\n", *video.ID, err)
+			stats.fail(*video.ID, err)
+			continue
+		}
+		if changed {
+			stats.updated++
+		}
+	}
+
+	return nil
+}
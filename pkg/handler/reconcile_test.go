@@ -0,0 +1,177 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/youtubetest"
+)
+
+func TestBackfillReconcilesVideos(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 4)
+
+	if got := app.sync(t, "&mode=backfill"); got.Added != 4 {
+		t.Fatalf("first backfill = %+v, want 4 added", got)
+	}
+
+	// Backfills compare times to the second; date the first one back.
+	app.seed(t, `UPDATE videos SET last_seen_at = '2024-06-01 00:00:00'`)
+
+	published := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	app.youtube.Update(youtubetest.Video{ID: "vid01", Title: "Video 1 (renamed)", PublishedAt: published.AddDate(0, 0, 1)})
+	app.youtube.Update(youtubetest.Video{ID: "vid02", Title: "Video 2", PublishedAt: published.AddDate(0, 0, 2), Privacy: "private"})
+	app.youtube.Update(youtubetest.Video{ID: "vid03", Title: "Video 3", PublishedAt: published.AddDate(0, 0, 3), Privacy: "deleted"})
+	app.youtube.Remove("vid04")
+
+	got := app.sync(t, "&mode=backfill")
+	if got.Updated != 4 || got.Added != 0 || got.Errors != 0 || !got.Complete {
+		t.Fatalf("second backfill = %+v, want 4 updated", got)
+	}
+
+	want := map[string]string{
+		"vid01": model.VideoStatusAvailable,
+		"vid02": model.VideoStatusPrivate,
+		"vid03": model.VideoStatusRemoved,
+		"vid04": model.VideoStatusRemoved,
+	}
+	for id, status := range want {
+		var body model.APIResponse[model.VideoResponse]
+		app.do(t, http.MethodGet, "/api/videos/"+id, "", &body)
+		if body.Data.Status != status {
+			t.Errorf("%s status = %q, want %q", id, body.Data.Status, status)
+		}
+	}
+
+	var edits model.APIResponse[[]model.VideoEdit]
+	app.do(t, http.MethodGet, "/api/videos/vid01/edits", "", &edits)
+	if len(edits.Data) != 1 {
+		t.Fatalf("edits = %+v, want 1", edits.Data)
+	}
+	if e := edits.Data[0]; e.Field != "title" || *e.OldValue != "Video 1" || *e.NewValue != "Video 1 (renamed)" {
+		t.Fatalf("edit = %+v, want the rename", e)
+	}
+
+	// The private video keeps its real title.
+	var body model.APIResponse[model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos/vid02", "", &body)
+	if body.Data.Title != "Video 2" {
+		t.Fatalf("vid02 title = %q, want it unchanged", body.Data.Title)
+	}
+
+	// A video that comes back is available again.
+	app.youtube.Update(youtubetest.Video{ID: "vid02", Title: "Video 2", PublishedAt: published.AddDate(0, 0, 2)})
+	if got := app.sync(t, "&mode=backfill"); got.Updated != 1 {
+		t.Fatalf("third backfill = %+v, want 1 updated", got)
+	}
+	app.do(t, http.MethodGet, "/api/videos/vid02/edits", "", &edits)
+	if len(edits.Data) != 2 || *edits.Data[1].NewValue != model.VideoStatusAvailable {
+		t.Fatalf("vid02 edits = %+v, want private then available", edits.Data)
+	}
+
+	if resp := app.do(t, http.MethodGet, "/api/videos/nope/edits", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown video: status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestIncompleteBackfillDoesNotRemoveVideos(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.PageSize = 2
+	app.upload(1, 4)
+	app.sync(t, "&mode=backfill")
+
+	app.youtube.Remove("vid01")
+	if got := app.sync(t, "&mode=backfill&max_results=1"); got.Complete || got.Updated != 0 {
+		t.Fatalf("capped backfill = %+v, want incomplete with nothing updated", got)
+	}
+
+	var body model.APIResponse[model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos/vid01", "", &body)
+	if body.Data.Status != model.VideoStatusAvailable {
+		t.Fatalf("vid01 status = %q, want available until the backfill completes", body.Data.Status)
+	}
+}
+
+func TestGetVideosHidesUnavailable(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO videos (id, title, published_at, status) VALUES
+		('up', 'Up', '2024-01-01 00:00:00', 'available'),
+		('priv', 'Private', '2024-01-02 00:00:00', 'private'),
+		('gone', 'Gone', '2024-01-03 00:00:00', 'removed')`)
+
+	var videos model.APIResponse[[]model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos", "", &videos)
+	if videos.Meta.Total != 1 || videos.Data[0].ID != "up" {
+		t.Fatalf("videos = %+v, want only the available one", videos.Data)
+	}
+
+	app.do(t, http.MethodGet, "/api/videos?include_unavailable=true", "", &videos)
+	if videos.Meta.Total != 3 {
+		t.Fatalf("total = %d, want 3", videos.Meta.Total)
+	}
+
+	if resp := app.do(t, http.MethodGet, "/api/videos?include_unavailable=maybe", "", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid include_unavailable: status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestIncrementalSyncReconcilesRecentVideos(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.PageSize = 2
+	app.upload(1, 3)
+	app.sync(t, "")
+
+	published := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	app.youtube.Update(youtubetest.Video{ID: "vid01", Title: "Video 1", PublishedAt: published.AddDate(0, 0, 1), Privacy: "private"})
+	app.youtube.Update(youtubetest.Video{ID: "vid03", Title: "Video 3 (renamed)", PublishedAt: published.AddDate(0, 0, 3)})
+	app.upload(4, 1)
+
+	got := app.sync(t, "")
+	if got.Added != 1 || got.Updated != 2 || got.Total != 1 || !got.Complete {
+		t.Fatalf("sync = %+v, want 1 added and the 2 changed videos updated", got)
+	}
+
+	var body model.APIResponse[model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos/vid03", "", &body)
+	if body.Data.Title != "Video 3 (renamed)" {
+		t.Fatalf("vid03 title = %q, want the new title", body.Data.Title)
+	}
+	app.do(t, http.MethodGet, "/api/videos/vid01", "", &body)
+	if body.Data.Status != model.VideoStatusPrivate {
+		t.Fatalf("vid01 status = %q, want private", body.Data.Status)
+	}
+}
+
+func TestStatsRefreshMarksMissingVideosRemoved(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.Upload(testChannelID, youtubetest.Video{ID: "new", Title: "New", PublishedAt: time.Now().Add(-6 * time.Hour)})
+	app.sync(t, "")
+
+	app.seed(t, `UPDATE videos SET stats_updated_at = datetime(stats_updated_at, '-1 hour')`)
+	app.youtube.Remove("new")
+
+	var result model.APIResponse[model.StatsRefreshResult]
+	app.do(t, http.MethodPost, "/api/videos/stats/refresh", "", &result)
+	if result.Data.Missing != 1 {
+		t.Fatalf("refresh = %+v, want the video missing", result.Data)
+	}
+
+	var body model.APIResponse[model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos/new", "", &body)
+	if body.Data.Status != model.VideoStatusRemoved {
+		t.Fatalf("status = %q, want removed", body.Data.Status)
+	}
+
+	// Removed videos are no longer due for a refresh.
+	app.seed(t, `UPDATE videos SET stats_updated_at = datetime(stats_updated_at, '-1 hour')`)
+	app.do(t, http.MethodPost, "/api/videos/stats/refresh", "", &result)
+	if result.Data.Checked != 0 {
+		t.Fatalf("second refresh = %+v, want nothing due", result.Data)
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// syncScheduler runs the scheduled sync of each enabled channel, on the
+// channel's own cron schedule or the default one.
+type syncScheduler struct {
+	mu              sync.Mutex
+	cron            *cron.Cron
+	defaultSchedule string
+	entries         map[string]scheduledSync // by channel ID
+}
+
+type scheduledSync struct {
+	schedule string
+	entryID  cron.EntryID
+}
+
+// StartScheduledSync schedules the sync of every enabled channel and keeps
+// the schedules in step as channels are added, changed or removed. Channels
+// without a schedule of their own use defaultSchedule; if that is empty,
+// they aren't synced on a schedule.
+func (h *Handler) StartScheduledSync(ctx context.Context, defaultSchedule string) error {
+	if err := validateSchedule(&defaultSchedule); err != nil {
+		return err
+	}
+
+	h.scheduler = &syncScheduler{
+		cron:            cron.New(),
+		defaultSchedule: defaultSchedule,
+		entries:         map[string]scheduledSync{},
+	}
+
+	if err := h.reloadSchedules(ctx); err != nil {
+		return err
+	}
+
+	h.scheduler.cron.Start()
+	return nil
+}
+
+// StopScheduledSync stops scheduling syncs and waits for running ones to
+// finish.
+func (h *Handler) StopScheduledSync() {
+	if h.scheduler != nil {
+		<-h.scheduler.cron.Stop().Done()
+	}
+}
+
+// reloadSchedules brings the cron entries in line with the channels table.
+func (h *Handler) reloadSchedules(ctx context.Context) error {
+	s := h.scheduler
+	if s == nil {
+		return nil
+	}
+
+	channels, err := h.repo.GetChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("load channels: %w", err)
+	}
+
+	wanted := make(map[string]string, len(channels))
+	for _, ch := range channels {
+		schedule := s.defaultSchedule
+		if ch.SyncSchedule != nil {
+			schedule = *ch.SyncSchedule
+		}
+		if ch.SyncEnabled && schedule != "" {
+			wanted[ch.ID] = schedule
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for channelID, entry := range s.entries {
+		if wanted[channelID] != entry.schedule {
+			s.cron.Remove(entry.entryID)
+			delete(s.entries, channelID)
+		}
+	}
+
+	for channelID, schedule := range wanted {
+		if _, ok := s.entries[channelID]; ok {
+			continue
+		}
+
+		entryID, err := s.cron.AddFunc(schedule, func() {
+			h.SyncChannelScheduled(channelID)
+		})
+		if err != nil {
+			log.Printf("%s invalid schedule %q for channel %s: %v", syncLogPrefix, schedule, channelID, err)
+			continue
+		}
+		s.entries[channelID] = scheduledSync{schedule: schedule, entryID: entryID}
+	}
+
+	return nil
+}
+
+// reloadSchedulesOrLog reloads the schedules after a channel changed. The
+// change itself has been saved, so a failure is only logged; the schedules
+// catch up on the next change.
+func (h *Handler) reloadSchedulesOrLog(ctx context.Context) {
+	if err := h.reloadSchedules(ctx); err != nil {
+		log.Printf("%s failed to reload schedules: %v", syncLogPrefix, err)
+	}
+}
+
+// nextScheduledSync returns when a channel is next synced on its schedule,
+// or nil if it isn't scheduled.
+func (h *Handler) nextScheduledSync(channelID string) *time.Time {
+	s := h.scheduler
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	entry, ok := s.entries[channelID]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	next := s.cron.Entry(entry.entryID).Schedule.Next(time.Now())
+	return &next
+}
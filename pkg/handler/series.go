@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/matcher"
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/repository"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	"github.com/K0ng2/zeedzad/utils"
+)
+
+// seriesAutoMatchShare is the share of the matched episodes of a series a
+// game needs to be assigned to the unmatched ones. A game with a smaller
+// share, of at least suggestionScore, is suggested for them instead.
+const seriesAutoMatchShare = 0.9
+
+// assignSeries puts the videos whose titles number an episode in the
+// series of their channel named like it. It returns the series they were
+// put in, and how many videos it put in one. Failures are only logged.
+func (h *Handler) assignSeries(ctx context.Context, videos []repoModel.Videos) (seriesIDs []int64, grouped int) {
+	episodes := make(map[string][]repository.SeriesEpisode)
+	for _, video := range videos {
+		title := matcher.ParseTitle(video.Title)
+		key := matcher.Normalize(title.Series)
+		if title.Episode == 0 || key == "" {
+			continue
+		}
+
+		channelID := ""
+		if video.ChannelID != nil {
+			channelID = *video.ChannelID
+		}
+		episodes[channelID] = append(episodes[channelID], repository.SeriesEpisode{
+			VideoID: *video.ID,
+			Key:     key,
+			Name:    title.Series,
+			Episode: int32(title.Episode),
+		})
+	}
+
+	for _, channelID := range slices.Sorted(maps.Keys(episodes)) {
+		ids, err := h.repo.AssignSeries(ctx, channelID, episodes[channelID])
+		if err != nil {
+			fmt.Printf("failed to put the videos of channel %q in series: %v\n", channelID, err)
+			continue
+		}
+		seriesIDs = append(seriesIDs, ids...)
+		grouped += len(episodes[channelID])
+	}
+
+	return seriesIDs, grouped
+}
+
+// inferSeriesGames gives the unmatched episodes of each series the game
+// most of its matched episodes have: it is assigned if at least
+// seriesAutoMatchShare of them have it, and suggested otherwise. Games
+// rejected for a video are never proposed for it again, and a game is
+// suggested for a video once. It returns how many games it assigned and
+// suggested; failures are only logged.
+func (h *Handler) inferSeriesGames(ctx context.Context, seriesIDs []int64) (assigned, suggested int) {
+	if len(seriesIDs) == 0 {
+		return 0, 0
+	}
+
+	episodes, err := h.repo.GetSeriesEpisodes(ctx, seriesIDs)
+	if err != nil {
+		fmt.Printf("failed to load series episodes to infer games from: %v\n", err)
+		return 0, 0
+	}
+
+	type seriesGames struct {
+		counts    map[int32]int
+		games     map[int32]repoModel.Games
+		matched   int
+		unmatched []string
+	}
+
+	series := make(map[int32]*seriesGames)
+	var unmatched []string
+	for _, episode := range episodes {
+		s, ok := series[*episode.SeriesID]
+		if !ok {
+			s = &seriesGames{counts: make(map[int32]int), games: make(map[int32]repoModel.Games)}
+			series[*episode.SeriesID] = s
+		}
+
+		if episode.Game == nil || episode.Game.ID == nil {
+			s.unmatched = append(s.unmatched, *episode.ID)
+			unmatched = append(unmatched, *episode.ID)
+			continue
+		}
+		s.counts[*episode.Game.ID]++
+		s.games[*episode.Game.ID] = *episode.Game
+		s.matched++
+	}
+	if len(unmatched) == 0 {
+		return 0, 0
+	}
+
+	rejected, err := h.repo.GetRejectedMatches(ctx, unmatched)
+	if err != nil {
+		fmt.Printf("failed to load rejected matches: %v\n", err)
+		return 0, 0
+	}
+
+	pending, err := h.repo.GetPendingMatches(ctx, unmatched)
+	if err != nil {
+		fmt.Printf("failed to load pending matches: %v\n", err)
+		return 0, 0
+	}
+
+	var videoGames []repoModel.VideoGames
+	var suggestions []repoModel.MatchSuggestions
+
+	for _, id := range slices.Sorted(maps.Keys(series)) {
+		s := series[id]
+		if s.matched == 0 || len(s.unmatched) == 0 {
+			continue
+		}
+
+		// The most common game; the lowest ID breaks ties.
+		var best int32
+		for _, gameID := range slices.Sorted(maps.Keys(s.counts)) {
+			if s.counts[gameID] > s.counts[best] {
+				best = gameID
+			}
+		}
+
+		share := float64(s.counts[best]) / float64(s.matched)
+		if share < suggestionScore {
+			continue
+		}
+
+		game := s.games[best]
+		for _, videoID := range s.unmatched {
+			if slices.Contains(rejected[videoID], best) {
+				continue
+			}
+
+			if share >= seriesAutoMatchShare {
+				videoGames = append(videoGames, repoModel.VideoGames{VideoID: videoID, GameID: best})
+				continue
+			}
+			if slices.Contains(pending[videoID], best) {
+				continue
+			}
+			suggestions = append(suggestions, repoModel.MatchSuggestions{
+				VideoID:  videoID,
+				GameID:   best,
+				GameName: game.Name,
+				GameURL:  game.URL,
+				Score:    share,
+				Source:   model.MatchSourceSeries,
+			})
+		}
+	}
+
+	if err := h.repo.SaveMatches(ctx, nil, videoGames, suggestions); err != nil {
+		fmt.Printf("failed to save the games inferred from series: %v\n", err)
+		return 0, 0
+	}
+
+	return len(videoGames), len(suggestions)
+}
+
+// inferVideoSeriesGames gives the other episodes of the series of videos
+// whose games were just changed the games they can now infer.
+func (h *Handler) inferVideoSeriesGames(ctx context.Context, videoIDs ...string) {
+	seriesIDs, err := h.repo.GetVideoSeriesIDs(ctx, videoIDs)
+	if err != nil {
+		fmt.Printf("failed to load the series of videos %q: %v\n", videoIDs, err)
+		return
+	}
+
+	h.inferSeriesGames(ctx, seriesIDs)
+}
+
+// GetSeries godoc
+// @Summary Get all series
+// @Description Get the series of videos found in titles numbering episodes, such as "Elden Ring EP.3", the most recently continued first
+// @Tags series
+// @Accept  json
+// @Produce  json
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Param channel_id query string false "Only series of this channel"
+// @Success 200 {object} model.APIResponse[[]model.Series]
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /series [get]
+func (h *Handler) GetSeries(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	q, err := GetOffset(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
+
+	filter := model.SeriesFilter{ChannelID: c.Query("channel_id")}
+
+	series, err := h.repo.GetSeries(ctx, *q, filter)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	total, err := h.repo.GetSeriesTotalItems(ctx, filter)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	meta := &model.Meta{
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+
+	return c.JSON(Response(series, meta))
+}
+
+// GetSeriesByID godoc
+// @Summary Get series by ID
+// @Description Get a series with its episodes, in episode order
+// @Tags series
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Series ID"
+// @Success 200 {object} model.APIResponse[model.Series]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /series/{id} [get]
+func (h *Handler) GetSeriesByID(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	id, err := fiber.Convert(c.Params("id"), utils.Atoi64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidPathParams)
+	}
+
+	series, err := h.repo.GetSeriesByID(ctx, id)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.JSON(Response(series, nil))
+}
+
+// InferSeries godoc
+// @Summary Infer series from stored videos
+// @Description Put the stored videos that aren't in a series yet in the series their titles number them in, then give the unmatched episodes of every series the game most of its matched episodes have. It is assigned if nearly all of them have it, and suggested otherwise. Syncs do this for the videos they add.
+// @Tags series
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.APIResponse[model.SeriesInferResult]
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /series/infer [post]
+func (h *Handler) InferSeries(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	videos, err := h.repo.GetVideosWithoutSeries(ctx)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	_, grouped := h.assignSeries(ctx, videos)
+
+	seriesIDs, err := h.repo.GetAllSeriesIDs(ctx)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	assigned, suggested := h.inferSeriesGames(ctx, seriesIDs)
+
+	return c.JSON(Response(model.SeriesInferResult{Grouped: grouped, Assigned: assigned, Suggested: suggested}, nil))
+}
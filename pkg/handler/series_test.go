@@ -0,0 +1,122 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/youtubetest"
+)
+
+func TestSyncGroupsSeriesAndPropagatesGames(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES (121, 'Minecraft', 'https://www.igdb.com/games/minecraft')`)
+	app.uploadTitled(
+		"Hardcore EP.1",
+		"[Hardcore] EP.2 บุกนรก",
+		"ขุดเพชร | Hardcore ตอนที่ 3",
+		"Video 4",
+	)
+
+	app.sync(t, "")
+
+	// None of the titles name the game, so the playthrough is only grouped.
+	for _, id := range []string{"vid01", "vid02", "vid03"} {
+		if game := app.videoGame(t, id); game != nil {
+			t.Fatalf("%s game = %+v, want none yet", id, game)
+		}
+	}
+
+	// Matching one episode by hand gives the others its game.
+	if resp := app.do(t, http.MethodPut, "/api/videos/vid01/game", `{"game_id": 121}`, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT game status = %d, want 200", resp.StatusCode)
+	}
+	for _, id := range []string{"vid02", "vid03"} {
+		if game := app.videoGame(t, id); game == nil || game.ID != 121 {
+			t.Fatalf("%s game = %+v, want Minecraft from its series", id, game)
+		}
+	}
+	if game := app.videoGame(t, "vid04"); game != nil {
+		t.Fatalf("vid04 game = %+v, want none: it's in no series", game)
+	}
+
+	// A new episode synced later joins the series, and gets its game.
+	app.youtube.Upload(testChannelID, youtubetest.Video{
+		ID:          "vid05",
+		Title:       "HARDCORE EP.4",
+		PublishedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+	app.sync(t, "")
+
+	if game := app.videoGame(t, "vid05"); game == nil || game.ID != 121 {
+		t.Fatalf("vid05 game = %+v, want Minecraft from its series", game)
+	}
+
+	var list model.APIResponse[[]model.Series]
+	app.do(t, http.MethodGet, "/api/series?channel_id="+testChannelID, "", &list)
+	if list.Meta == nil || list.Meta.Total != 1 || len(list.Data) != 1 {
+		t.Fatalf("series = %+v, want one", list)
+	}
+	series := list.Data[0]
+	if series.Name != "Hardcore" || series.EpisodeCount != 4 || series.MatchedCount != 4 {
+		t.Fatalf("series = %+v, want Hardcore with 4 matched episodes", series)
+	}
+
+	var body model.APIResponse[model.Series]
+	if resp := app.do(t, http.MethodGet, fmt.Sprintf("/api/series/%d", series.ID), "", &body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET series status = %d, want 200", resp.StatusCode)
+	}
+	for i, episode := range body.Data.Episodes {
+		if want := fmt.Sprintf("vid%02d", []int{1, 2, 3, 5}[i]); episode.ID != want || episode.Episode == nil || *episode.Episode != int32(i+1) || episode.SeriesID == nil || *episode.SeriesID != series.ID {
+			t.Fatalf("episode %d = %s (episode %v), want %s", i, episode.ID, episode.Episode, want)
+		}
+	}
+	if len(body.Data.Episodes) != 4 {
+		t.Fatalf("episodes = %d, want 4", len(body.Data.Episodes))
+	}
+
+	if resp := app.do(t, http.MethodGet, "/api/series/99", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("GET missing series status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestInferSeriesSuggestsMixedGames(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES
+		(121, 'Minecraft', 'https://www.igdb.com/games/minecraft'),
+		(1879, 'Terraria', 'https://www.igdb.com/games/terraria')`)
+	app.seed(t, `INSERT INTO videos (id, title, thumbnail, published_at) VALUES
+		('vid01', 'Survival EP.1', 'https://i.ytimg.com/vi/vid01/hqdefault.jpg', '2024-01-01 00:00:00'),
+		('vid02', 'Survival EP.2', 'https://i.ytimg.com/vi/vid02/hqdefault.jpg', '2024-01-02 00:00:00'),
+		('vid03', 'Survival EP.3', 'https://i.ytimg.com/vi/vid03/hqdefault.jpg', '2024-01-03 00:00:00'),
+		('vid04', 'Survival EP.4', 'https://i.ytimg.com/vi/vid04/hqdefault.jpg', '2024-01-04 00:00:00')`)
+	for id, game := range map[string]int{"vid01": 121, "vid02": 121, "vid03": 1879} {
+		app.do(t, http.MethodPut, "/api/videos/"+id+"/game", fmt.Sprintf(`{"game_id": %d}`, game), nil)
+	}
+
+	var body model.APIResponse[model.SeriesInferResult]
+	if resp := app.do(t, http.MethodPost, "/api/series/infer", "", &body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("infer status = %d, want 200", resp.StatusCode)
+	}
+	if want := (model.SeriesInferResult{Grouped: 4, Suggested: 1}); body.Data != want {
+		t.Fatalf("infer = %+v, want %+v", body.Data, want)
+	}
+
+	// Two of the three matched episodes aren't enough to assign the game.
+	if game := app.videoGame(t, "vid04"); game != nil {
+		t.Fatalf("vid04 game = %+v, want it left unmatched", game)
+	}
+	got := app.suggestions(t)
+	if len(got) != 1 || got[0].videoID != "vid04" || got[0].gameID != 121 || got[0].source != model.MatchSourceSeries {
+		t.Fatalf("suggestions = %+v, want Minecraft suggested for vid04 from its series", got)
+	}
+
+	// Inferring again finds nothing new.
+	app.do(t, http.MethodPost, "/api/series/infer", "", &body)
+	if want := (model.SeriesInferResult{Suggested: 0}); body.Data != want {
+		t.Fatalf("second infer = %+v, want %+v", body.Data, want)
+	}
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/robfig/cron/v3"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+const (
+	// statsRefreshBatchSize caps the videos read per refresh, ten
+	// videos.list calls.
+	statsRefreshBatchSize = 500
+	statsLogPrefix        = "[Stats Refresh]"
+)
+
+// GetVideoStats godoc
+// @Summary Get a video's stats history
+// @Description Get the view, like and comment counts of a video each time they were read, oldest first, with the views and likes gained since the previous snapshot
+// @Tags videos
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Video ID"
+// @Success 200 {object} model.APIResponse[[]model.VideoStatsPoint]
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /videos/{id}/stats [get]
+func (h *Handler) GetVideoStats(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	videoID := c.Params("id")
+
+	existing, err := h.repo.GetExistingVideoIDs(ctx, []string{videoID})
+	if err != nil {
+		return sendError(c, err)
+	}
+	if !existing[videoID] {
+		return c.Status(http.StatusNotFound).JSON(model.Error{Error: "video not found"})
+	}
+
+	snapshots, err := h.repo.GetVideoStats(ctx, videoID)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.JSON(Response(statsGrowth(snapshots), nil))
+}
+
+// RefreshVideoStats godoc
+// @Summary Refresh video stats
+// @Description Read the counts of the videos that are due for it now, as the scheduled stats refresh does. Videos are read hourly for their first two days, every 6 hours for their first week, daily for their first month and weekly until they are 90 days old.
+// @Tags videos
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.APIResponse[model.StatsRefreshResult]
+// @Failure 429 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /videos/stats/refresh [post]
+func (h *Handler) RefreshVideoStats(c fiber.Ctx) error {
+	result, err := h.refreshVideoStats(c.RequestCtx())
+	if err != nil {
+		var syncErr *syncError
+		if errors.As(err, &syncErr) {
+			return sendSyncError(c, syncErr)
+		}
+		return sendError(c, err)
+	}
+
+	return c.JSON(Response(result, nil))
+}
+
+// StartStatsRefresh refreshes the stats of recent videos on schedule.
+func (h *Handler) StartStatsRefresh(schedule string) error {
+	if err := validateSchedule(&schedule); err != nil {
+		return err
+	}
+
+	h.statsCron = cron.New()
+	if _, err := h.statsCron.AddFunc(schedule, h.refreshVideoStatsScheduled); err != nil {
+		return err
+	}
+
+	h.statsCron.Start()
+	return nil
+}
+
+// StopStatsRefresh stops the scheduled stats refresh and waits for a
+// running one to finish.
+func (h *Handler) StopStatsRefresh() {
+	if h.statsCron != nil {
+		<-h.statsCron.Stop().Done()
+	}
+}
+
+func (h *Handler) refreshVideoStatsScheduled() {
+	result, err := h.refreshVideoStats(context.Background())
+	if err != nil {
+		log.Printf("%s failed: %v", statsLogPrefix, err)
+		return
+	}
+
+	fmt.Printf("%s completed - Checked: %d, Updated: %d, Missing: %d\n",
+		statsLogPrefix, result.Checked, result.Updated, result.Missing)
+}
+
+// refreshVideoStats reads the counts of the videos that are due for it and
+// records them as a new snapshot.
+func (h *Handler) refreshVideoStats(ctx context.Context) (model.StatsRefreshResult, error) {
+	now := time.Now().UTC()
+
+	ids, err := h.repo.GetVideosDueForStatsRefresh(ctx, now, statsRefreshBatchSize)
+	if err != nil {
+		return model.StatsRefreshResult{}, err
+	}
+
+	result := model.StatsRefreshResult{Checked: len(ids)}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	service, err := h.createYouTubeService(ctx)
+	if err != nil {
+		return result, &syncError{
+			message:    "failed to create youtube service: " + err.Error(),
+			statusCode: http.StatusInternalServerError,
+		}
+	}
+
+	videos, syncErr := h.listVideos(ctx, service, []string{"statistics"}, ids)
+	if syncErr != nil {
+		return result, syncErr
+	}
+
+	var snapshots []repoModel.VideoStats
+	var missing []string
+	for _, id := range ids {
+		video := videos[id]
+		if video == nil || video.Statistics == nil {
+			missing = append(missing, id)
+			continue
+		}
+
+		views, likes, comments := int64(video.Statistics.ViewCount), int64(video.Statistics.LikeCount), int64(video.Statistics.CommentCount)
+		snapshots = append(snapshots, repoModel.VideoStats{
+			VideoID:      id,
+			CapturedAt:   now,
+			ViewCount:    &views,
+			LikeCount:    &likes,
+			CommentCount: &comments,
+		})
+	}
+
+	if err := h.repo.RecordVideoStats(ctx, snapshots, missing, now); err != nil {
+		return result, err
+	}
+	if err := h.markMissingVideosRemoved(ctx, missing); err != nil {
+		return result, err
+	}
+
+	result.Updated = len(snapshots)
+	result.Missing = len(missing)
+	return result, nil
+}
+
+// statsGrowth adds the gains since the previous snapshot to each snapshot.
+func statsGrowth(snapshots []repoModel.VideoStats) []model.VideoStatsPoint {
+	points := make([]model.VideoStatsPoint, len(snapshots))
+
+	for i, s := range snapshots {
+		points[i] = model.VideoStatsPoint{
+			CapturedAt:   s.CapturedAt,
+			ViewCount:    s.ViewCount,
+			LikeCount:    s.LikeCount,
+			CommentCount: s.CommentCount,
+		}
+		if i > 0 {
+			prev := snapshots[i-1]
+			points[i].ViewsGained = gained(prev.ViewCount, s.ViewCount)
+			points[i].LikesGained = gained(prev.LikeCount, s.LikeCount)
+		}
+	}
+
+	return points
+}
+
+func gained(before, after *int64) *int64 {
+	if before == nil || after == nil {
+		return nil
+	}
+	n := *after - *before
+	return &n
+}
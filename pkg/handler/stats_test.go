@@ -0,0 +1,79 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/youtubetest"
+)
+
+func TestRefreshVideoStats(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+
+	video := youtubetest.Video{ID: "new", Title: "New", PublishedAt: time.Now().Add(-6 * time.Hour), Views: 100, Likes: 10}
+	app.youtube.Upload(testChannelID, video,
+		youtubetest.Video{ID: "old", Title: "Old", PublishedAt: time.Now().AddDate(-1, 0, 0), Views: 5000})
+	app.sync(t, "")
+
+	var result model.APIResponse[model.StatsRefreshResult]
+	app.do(t, http.MethodPost, "/api/videos/stats/refresh", "", &result)
+	if result.Data.Checked != 0 {
+		t.Fatalf("refresh right after the sync = %+v, want nothing due", result.Data)
+	}
+
+	// An hour later, the new video is due again; the old one never is.
+	app.seed(t, `UPDATE videos SET stats_updated_at = datetime(stats_updated_at, '-1 hour')`)
+	app.seed(t, `UPDATE video_stats SET captured_at = datetime(captured_at, '-1 hour')`)
+	video.Views, video.Likes = 250, 25
+	app.youtube.Update(video)
+
+	resp := app.do(t, http.MethodPost, "/api/videos/stats/refresh", "", &result)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("refresh: status = %d, want 200", resp.StatusCode)
+	}
+	if result.Data != (model.StatsRefreshResult{Checked: 1, Updated: 1}) {
+		t.Fatalf("refresh = %+v, want the new video updated", result.Data)
+	}
+
+	var stats model.APIResponse[[]model.VideoStatsPoint]
+	app.do(t, http.MethodGet, "/api/videos/new/stats", "", &stats)
+	if len(stats.Data) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(stats.Data))
+	}
+	first, second := stats.Data[0], stats.Data[1]
+	if *first.ViewCount != 100 || first.ViewsGained != nil {
+		t.Fatalf("first snapshot = %+v", first)
+	}
+	if *second.ViewCount != 250 || *second.ViewsGained != 150 || *second.LikesGained != 15 || !second.CapturedAt.After(first.CapturedAt) {
+		t.Fatalf("second snapshot = %+v", second)
+	}
+
+	var body model.APIResponse[model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos/new", "", &body)
+	if body.Data.ViewCount == nil || *body.Data.ViewCount != 250 {
+		t.Fatalf("view_count = %v, want 250", body.Data.ViewCount)
+	}
+
+	if resp := app.do(t, http.MethodGet, "/api/videos/missing/stats", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown video: status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestRefreshVideoStatsReportsYouTubeErrors(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO videos (id, title, published_at) VALUES ('vid1', 'Video 1', datetime('now'))`)
+	app.youtube.Fail("videos", 1, http.StatusForbidden)
+
+	if resp := app.do(t, http.MethodPost, "/api/videos/stats/refresh", "", nil); resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", resp.StatusCode)
+	}
+
+	var result model.APIResponse[model.StatsRefreshResult]
+	app.do(t, http.MethodPost, "/api/videos/stats/refresh", "", &result)
+	if result.Data != (model.StatsRefreshResult{Checked: 1, Missing: 1}) {
+		t.Fatalf("refresh = %+v, want vid1 missing from YouTube", result.Data)
+	}
+}
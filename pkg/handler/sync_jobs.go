@@ -0,0 +1,375 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+const (
+	// syncJobRetention is how long a finished job can still be polled.
+	syncJobRetention = time.Hour
+	// syncJobHeartbeat is how often a progress stream repeats the job's
+	// state while nothing changes, so that clients that went away are
+	// noticed.
+	syncJobHeartbeat = 15 * time.Second
+)
+
+// pushSyncHolder holds a channel while a push notification of its videos is
+// stored. Job IDs are hex, so it can't be one.
+const pushSyncHolder = "push"
+
+// syncJobs keeps the background sync jobs, and which channels are being
+// synced: a channel is only synced by one job, its scheduled sync or a push
+// notification at a time.
+type syncJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*syncJob
+	// syncing maps each channel being synced to the ID of the job syncing
+	// it, "" for its scheduled sync, or pushSyncHolder for a notification.
+	syncing map[string]string
+}
+
+func newSyncJobs() syncJobs {
+	return syncJobs{jobs: map[string]*syncJob{}, syncing: map[string]string{}}
+}
+
+// claim marks channelIDs as being synced by jobID. If one of them already
+// is, none is claimed, and it is returned with the ID of the job syncing it.
+func (s *syncJobs) claim(jobID string, channelIDs []string) (busy, holder string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, channelID := range channelIDs {
+		if holder, ok := s.syncing[channelID]; ok {
+			return channelID, holder, false
+		}
+	}
+	for _, channelID := range channelIDs {
+		s.syncing[channelID] = jobID
+	}
+	return "", "", true
+}
+
+// release marks channelIDs as no longer being synced.
+func (s *syncJobs) release(channelIDs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, channelID := range channelIDs {
+		delete(s.syncing, channelID)
+	}
+}
+
+// add keeps a new job, forgetting the ones that finished too long ago.
+func (s *syncJobs) add(job *syncJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, old := range s.jobs {
+		if state, _ := old.snapshot(); state.FinishedAt != nil && time.Since(*state.FinishedAt) > syncJobRetention {
+			delete(s.jobs, id)
+		}
+	}
+	s.jobs[job.id] = job
+}
+
+func (s *syncJobs) get(id string) (*syncJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	return job, ok
+}
+
+// list returns the state of every job, newest first.
+func (s *syncJobs) list() []model.SyncJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]model.SyncJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		state, _ := job.snapshot()
+		states = append(states, state)
+	}
+	slices.SortFunc(states, func(a, b model.SyncJob) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+	return states
+}
+
+// syncHolderSuffix names the job holding a channel, for a log line or an
+// error message.
+func syncHolderSuffix(holder string) string {
+	switch holder {
+	case "":
+		return " on its schedule"
+	case pushSyncHolder:
+		return " from a push notification"
+	}
+	return " by job " + holder
+}
+
+// syncJob is a sync running in the background. Its state is updated by the
+// sync as it goes, and every update closes changed, so that progress
+// streams can wait for the next one.
+type syncJob struct {
+	id     string
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	state   model.SyncJob
+	changed chan struct{}
+}
+
+// snapshot returns the job's state, and a channel that is closed when it
+// next changes.
+func (j *syncJob) snapshot() (model.SyncJob, <-chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	state := j.state
+	state.ChannelIDs = slices.Clone(state.ChannelIDs)
+	state.Progress.RunIDs = slices.Clone(state.Progress.RunIDs)
+	return state, j.changed
+}
+
+func (j *syncJob) update(fn func(state *model.SyncJob)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	fn(&j.state)
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// startSyncJob starts a job syncing channelIDs in turn and responds with
+// it. It is refused if one of the channels is already being synced, or if
+// the first can't be synced within today's quota.
+func (h *Handler) startSyncJob(c fiber.Ctx, channelIDs []string, mode string, maxResults int) error {
+	// Strings from the request are only valid until it ends, and the job
+	// outlives it.
+	mode = strings.Clone(mode)
+	channelIDs = slices.Clone(channelIDs)
+	for i := range channelIDs {
+		channelIDs[i] = strings.Clone(channelIDs[i])
+	}
+
+	if len(channelIDs) > 0 {
+		state, syncErr := h.loadSyncState(c.RequestCtx(), channelIDs[0])
+		if syncErr != nil {
+			return sendSyncError(c, syncErr)
+		}
+		if syncErr := h.checkSyncQuota(c.RequestCtx(), state, mode, maxResults); syncErr != nil {
+			return sendSyncError(c, syncErr)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &syncJob{
+		id:      rand.Text(),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+	job.state = model.SyncJob{
+		ID:         job.id,
+		Status:     model.SyncRunRunning,
+		ChannelIDs: channelIDs,
+		StartedAt:  time.Now().UTC(),
+		Progress:   model.SyncResult{Mode: mode, RunIDs: []int64{}},
+	}
+
+	if busy, holder, ok := h.jobs.claim(job.id, channelIDs); !ok {
+		cancel()
+		if _, ok := h.jobs.get(holder); ok {
+			c.Location("/api/sync/jobs/" + holder)
+		}
+		return c.Status(http.StatusConflict).JSON(model.Error{
+			Error: fmt.Sprintf("channel %s is already being synced%s", busy, syncHolderSuffix(holder)),
+		})
+	}
+	h.jobs.add(job)
+
+	go h.runSyncJob(ctx, job, channelIDs, mode, maxResults)
+
+	state, _ := job.snapshot()
+	c.Location("/api/sync/jobs/" + job.id)
+	return c.Status(http.StatusAccepted).JSON(Response(state, nil))
+}
+
+// runSyncJob syncs each channel in turn, keeping the job's progress up to
+// date, until they are all synced, one fails or the job is cancelled.
+func (h *Handler) runSyncJob(ctx context.Context, job *syncJob, channelIDs []string, mode string, maxResults int) {
+	defer close(job.done)
+	defer job.cancel()
+	defer h.jobs.release(channelIDs)
+
+	total := &videoSyncStats{complete: true}
+	runIDs := []int64{}
+	report := func(stats *videoSyncStats) {
+		progress := *total
+		progress.add(stats)
+		job.update(func(state *model.SyncJob) {
+			state.Progress = progress.result(mode)
+			state.Progress.RunIDs = slices.Clone(runIDs)
+		})
+	}
+
+	var jobErr *syncError
+	for i, channelID := range channelIDs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		stats := &videoSyncStats{onProgress: report}
+		runID, syncErr := h.runSync(ctx, model.SyncTriggerManual, channelID, mode, maxResults, stats)
+		if runID != 0 {
+			runIDs = append(runIDs, runID)
+		}
+		total.add(stats)
+		if isQuotaExhausted(syncErr) && i > 0 {
+			// The channels synced so far are kept; the rest wait for the
+			// reset.
+			total.deferred = true
+		}
+		if syncErr != nil {
+			jobErr = syncErr
+			break
+		}
+	}
+
+	finishedAt := time.Now().UTC()
+	job.update(func(state *model.SyncJob) {
+		state.FinishedAt = &finishedAt
+		state.Status = model.SyncRunSucceeded
+		switch {
+		case ctx.Err() != nil:
+			state.Status = model.SyncRunCancelled
+			total.complete = false
+		case total.deferred:
+			total.complete = false
+		case jobErr != nil:
+			state.Status = model.SyncRunFailed
+			state.Error = &jobErr.message
+			total.complete = false
+		}
+		state.Progress = total.result(mode)
+		state.Progress.RunIDs = runIDs
+	})
+}
+
+// GetSyncJobs godoc
+// @Summary Get the sync jobs
+// @Description Get the running sync jobs, and those that finished within the last hour, newest first
+// @Tags sync
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.APIResponse[[]model.SyncJob]
+// @Router /sync/jobs [get]
+func (h *Handler) GetSyncJobs(c fiber.Ctx) error {
+	return c.JSON(Response(h.jobs.list(), nil))
+}
+
+// GetSyncJob godoc
+// @Summary Get a sync job
+// @Description Get a sync job and its progress. Jobs are kept for an hour after they finish; the runs they recorded stay in the sync history.
+// @Tags sync
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Sync job ID"
+// @Success 200 {object} model.APIResponse[model.SyncJob]
+// @Failure 404 {object} model.Error
+// @Router /sync/jobs/{id} [get]
+func (h *Handler) GetSyncJob(c fiber.Ctx) error {
+	job, ok := h.jobs.get(c.Params("id"))
+	if !ok {
+		return c.Status(http.StatusNotFound).JSON(model.Error{Error: "sync job not found"})
+	}
+
+	state, _ := job.snapshot()
+	return c.JSON(Response(state, nil))
+}
+
+// StreamSyncJob godoc
+// @Summary Stream a sync job's progress
+// @Description Stream a sync job as server-sent events: a progress event with the job each time it changes, and a done event with the job when it has finished, after which the stream ends.
+// @Tags sync
+// @Produce  text/event-stream
+// @Param id path string true "Sync job ID"
+// @Success 200 {object} model.SyncJob
+// @Failure 404 {object} model.Error
+// @Router /sync/jobs/{id}/events [get]
+func (h *Handler) StreamSyncJob(c fiber.Ctx) error {
+	job, ok := h.jobs.get(c.Params("id"))
+	if !ok {
+		return c.Status(http.StatusNotFound).JSON(model.Error{Error: "sync job not found"})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		for {
+			state, changed := job.snapshot()
+
+			event := "progress"
+			if state.Status != model.SyncRunRunning {
+				event = "done"
+			}
+			data, err := json.Marshal(state)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+			if err := w.Flush(); err != nil || event == "done" {
+				return
+			}
+
+			select {
+			case <-changed:
+			case <-time.After(syncJobHeartbeat):
+			}
+		}
+	})
+}
+
+// CancelSyncJob godoc
+// @Summary Cancel a sync job
+// @Description Cancel a running sync job, and return it once it has stopped. The page of videos being stored is stored whole; a backfill resumes after it, and an incremental sync catches up next time.
+// @Tags sync
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Sync job ID"
+// @Success 200 {object} model.APIResponse[model.SyncJob]
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Router /sync/jobs/{id} [delete]
+func (h *Handler) CancelSyncJob(c fiber.Ctx) error {
+	job, ok := h.jobs.get(c.Params("id"))
+	if !ok {
+		return c.Status(http.StatusNotFound).JSON(model.Error{Error: "sync job not found"})
+	}
+
+	if state, _ := job.snapshot(); state.Status != model.SyncRunRunning {
+		return c.Status(http.StatusConflict).JSON(model.Error{Error: "sync job has already finished"})
+	}
+
+	job.cancel()
+	<-job.done
+
+	state, _ := job.snapshot()
+	return c.JSON(Response(state, nil))
+}
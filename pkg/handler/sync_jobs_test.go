@@ -0,0 +1,180 @@
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+// startSyncJob starts a sync job and returns it while it is running.
+func (a *testApp) startSyncJob(t *testing.T, target string) model.SyncJob {
+	t.Helper()
+
+	var body model.APIResponse[model.SyncJob]
+	resp := a.do(t, http.MethodPost, target, "", &body)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST %s: status = %d, want 202", target, resp.StatusCode)
+	}
+	if location := resp.Header.Get("Location"); location != "/api/sync/jobs/"+body.Data.ID {
+		t.Fatalf("POST %s: Location = %q, want the job", target, location)
+	}
+	return body.Data
+}
+
+// waitForRequest waits until a call to a YouTube resource has been made.
+func (a *testApp) waitForRequest(t *testing.T, resource string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for a.youtube.Requests(resource) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("no call to %s", resource)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSyncJobReportsProgress(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 3)
+
+	job := app.startSyncJob(t, "/api/videos/sync?api_key=key")
+	if job.Status != model.SyncRunRunning || len(job.ChannelIDs) != 1 || job.ChannelIDs[0] != testChannelID {
+		t.Fatalf("started job = %+v, want it running on the channel", job)
+	}
+
+	job = app.waitForJob(t, job.ID)
+	if job.Status != model.SyncRunSucceeded || job.FinishedAt == nil || job.Progress.Added != 3 || len(job.Progress.RunIDs) != 1 {
+		t.Fatalf("job = %+v, want 3 added in one run", job)
+	}
+
+	var jobs model.APIResponse[[]model.SyncJob]
+	app.do(t, http.MethodGet, "/api/sync/jobs", "", &jobs)
+	if len(jobs.Data) != 1 || jobs.Data[0].ID != job.ID {
+		t.Fatalf("jobs = %+v, want the job", jobs.Data)
+	}
+}
+
+func TestSyncJobHoldsChannel(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 1)
+
+	release := app.youtube.Hold("playlistItems")
+	defer release()
+
+	job := app.startSyncJob(t, "/api/videos/sync?api_key=key")
+	app.waitForRequest(t, "playlistItems")
+
+	var conflict model.Error
+	resp := app.do(t, http.MethodPost, "/api/channels/"+testChannelID+"/sync?api_key=key", "", &conflict)
+	if resp.StatusCode != http.StatusConflict || conflict.Error == "" {
+		t.Fatalf("second sync: status = %d, want 409", resp.StatusCode)
+	}
+	if location := resp.Header.Get("Location"); location != "/api/sync/jobs/"+job.ID {
+		t.Fatalf("second sync: Location = %q, want the running job", location)
+	}
+
+	// A scheduled sync leaves the channel to the job.
+	app.handler.SyncChannelScheduled(testChannelID)
+	if runs := app.syncRuns(t, ""); runs.Meta.Total != 1 {
+		t.Fatalf("runs = %+v, want only the job's run", runs)
+	}
+
+	release()
+	if job = app.waitForJob(t, job.ID); job.Status != model.SyncRunSucceeded {
+		t.Fatalf("job = %+v, want it to succeed", job)
+	}
+
+	// Once the job is done, the channel can be synced again.
+	app.runSyncJob(t, "/api/channels/"+testChannelID+"/sync?api_key=key")
+}
+
+func TestCancelSyncJob(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 1)
+
+	release := app.youtube.Hold("playlistItems")
+	defer release()
+
+	job := app.startSyncJob(t, "/api/videos/sync?api_key=key")
+	app.waitForRequest(t, "playlistItems")
+
+	var body model.APIResponse[model.SyncJob]
+	if resp := app.do(t, http.MethodDelete, "/api/sync/jobs/"+job.ID, "", &body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("cancel: status = %d, want 200", resp.StatusCode)
+	}
+	if body.Data.Status != model.SyncRunCancelled || body.Data.Progress.Complete || len(body.Data.Progress.RunIDs) != 1 {
+		t.Fatalf("cancelled job = %+v", body.Data)
+	}
+
+	if run := app.syncRun(t, body.Data.Progress.RunIDs[0]); run.Status != model.SyncRunCancelled || run.FinishedAt == nil {
+		t.Fatalf("run = %+v, want it cancelled", run)
+	}
+
+	if resp := app.do(t, http.MethodDelete, "/api/sync/jobs/"+job.ID, "", nil); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("cancel again: status = %d, want 409", resp.StatusCode)
+	}
+}
+
+func TestStreamSyncJob(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 2)
+
+	release := app.youtube.Hold("playlistItems")
+	defer release()
+
+	job := app.startSyncJob(t, "/api/videos/sync?api_key=key")
+	app.waitForRequest(t, "playlistItems")
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		release()
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sync/jobs/"+job.ID+"/events", nil)
+	resp, err := app.Test(req, fiber.TestConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	events := strings.Split(strings.TrimSpace(string(body)), "\n\n")
+	if !strings.HasPrefix(events[0], "event: progress\n") {
+		t.Fatalf("first event = %q, want progress", events[0])
+	}
+	last := events[len(events)-1]
+	if !strings.HasPrefix(last, "event: done\n") || !strings.Contains(last, `"status":"succeeded"`) {
+		t.Fatalf("last event = %q, want done", last)
+	}
+}
+
+func TestSyncJobNotFound(t *testing.T) {
+	app := newTestApp(t)
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if resp := app.do(t, method, "/api/sync/jobs/unknown", "", nil); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s unknown job: status = %d, want 404", method, resp.StatusCode)
+		}
+	}
+	if resp := app.do(t, http.MethodGet, "/api/sync/jobs/unknown/events", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown job events: status = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	"github.com/K0ng2/zeedzad/utils"
+)
+
+// recordSyncRun runs sync as a run of a channel's sync, counting in stats,
+// and records it in the sync history: what triggered it, how it went, the
+// videos it failed on and the quota it spent. A run that fails keeps the
+// counts it reached. A failure to save the outcome is only logged; the sync
+// itself is done.
+func (h *Handler) recordSyncRun(ctx context.Context, trigger, channelID, mode string, stats *videoSyncStats, sync func(ctx context.Context) *syncError) (int64, *syncError) {
+	runID, err := h.repo.CreateSyncRun(ctx, repoModel.SyncRuns{
+		ChannelID:     channelID,
+		TriggerSource: trigger,
+		Mode:          mode,
+		Status:        model.SyncRunRunning,
+		StartedAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		return 0, databaseSyncError("failed to record sync run", err)
+	}
+
+	meterCtx, meter := withQuotaMeter(ctx)
+	syncErr := sync(meterCtx)
+
+	id := int32(runID)
+	finishedAt := time.Now().UTC()
+	run := repoModel.SyncRuns{
+		ID:         &id,
+		Status:     model.SyncRunSucceeded,
+		FinishedAt: &finishedAt,
+		Added:      int32(stats.added),
+		Updated:    int32(stats.updated),
+		Skipped:    int32(stats.skipped),
+		Errors:     int32(stats.errors),
+		Total:      int32(stats.totalFetched),
+		Complete:   stats.complete,
+		Deferred:   stats.deferred || isQuotaExhausted(syncErr),
+		QuotaUnits: int32(meter.Load()),
+	}
+	switch {
+	case syncErr != nil && ctx.Err() != nil:
+		run.Status = model.SyncRunCancelled
+		run.Error = &syncErr.message
+	case syncErr != nil:
+		run.Status = model.SyncRunFailed
+		run.Error = &syncErr.message
+	}
+
+	failures := make([]repoModel.SyncRunErrors, len(stats.failures))
+	for i, failure := range stats.failures {
+		failures[i] = repoModel.SyncRunErrors{VideoID: failure.VideoID, Message: failure.Message}
+	}
+
+	if err := h.repo.FinishSyncRun(context.WithoutCancel(ctx), run, failures); err != nil {
+		log.Printf("%s failed to record the end of sync run %d: %v", syncLogPrefix, runID, err)
+	}
+
+	return runID, syncErr
+}
+
+// GetSyncRuns godoc
+// @Summary Get the sync history
+// @Description Get the recorded syncs of every channel, newest first. Every sync is recorded, whether it was asked for, run on a channel's schedule or started by a WebSub notification, along with its counts and the YouTube quota it spent. The videos a run failed on are listed by GET /sync/runs/{id}.
+// @Tags sync
+// @Accept  json
+// @Produce  json
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Param channel_id query string false "Only runs of this channel"
+// @Param trigger query string false "Only runs started this way" Enums(manual, schedule, push)
+// @Param status query string false "Only runs in this state" Enums(running, succeeded, failed, cancelled)
+// @Success 200 {object} model.APIResponse[[]model.SyncRun]
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /sync/runs [get]
+func (h *Handler) GetSyncRuns(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	q, err := GetOffset(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
+
+	filter := model.SyncRunFilter{
+		ChannelID: c.Query("channel_id"),
+		Trigger:   c.Query("trigger"),
+		Status:    c.Query("status"),
+	}
+	triggers := []string{model.SyncTriggerManual, model.SyncTriggerSchedule, model.SyncTriggerPush}
+	if filter.Trigger != "" && !slices.Contains(triggers, filter.Trigger) {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: fmt.Sprintf("unknown trigger %q", filter.Trigger)})
+	}
+	statuses := []string{model.SyncRunRunning, model.SyncRunSucceeded, model.SyncRunFailed, model.SyncRunCancelled}
+	if filter.Status != "" && !slices.Contains(statuses, filter.Status) {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: fmt.Sprintf("unknown status %q", filter.Status)})
+	}
+
+	runs, err := h.repo.GetSyncRuns(ctx, *q, filter)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	total, err := h.repo.GetSyncRunTotalItems(ctx, filter)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	meta := &model.Meta{
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+
+	return c.JSON(Response(runs, meta))
+}
+
+// GetSyncRunByID godoc
+// @Summary Get a sync run
+// @Description Get a recorded sync, with the videos it failed to store or update
+// @Tags sync
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Sync run ID"
+// @Success 200 {object} model.APIResponse[model.SyncRun]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /sync/runs/{id} [get]
+func (h *Handler) GetSyncRunByID(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	id, err := fiber.Convert(c.Params("id"), utils.Atoi64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidPathParams)
+	}
+
+	run, err := h.repo.GetSyncRunByID(ctx, id)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.JSON(Response(run, nil))
+}
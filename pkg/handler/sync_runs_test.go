@@ -0,0 +1,138 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+func (a *testApp) syncRuns(t *testing.T, query string) model.APIResponse[[]model.SyncRun] {
+	t.Helper()
+
+	var body model.APIResponse[[]model.SyncRun]
+	resp := a.do(t, http.MethodGet, "/api/sync/runs"+query, "", &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("sync runs%s: status = %d, want 200", query, resp.StatusCode)
+	}
+	return body
+}
+
+func (a *testApp) syncRun(t *testing.T, id int64) model.SyncRun {
+	t.Helper()
+
+	var body model.APIResponse[model.SyncRun]
+	resp := a.do(t, http.MethodGet, fmt.Sprintf("/api/sync/runs/%d", id), "", &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("sync run %d: status = %d, want 200", id, resp.StatusCode)
+	}
+	return body.Data
+}
+
+func TestSyncRunsRecordSyncs(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 3)
+
+	result := app.sync(t, "")
+	if len(result.RunIDs) != 1 {
+		t.Fatalf("run_ids = %v, want one run", result.RunIDs)
+	}
+
+	app.upload(4, 1)
+	app.handler.SyncChannelScheduled(testChannelID)
+
+	runs := app.syncRuns(t, "")
+	if runs.Meta.Total != 2 || len(runs.Data) != 2 {
+		t.Fatalf("runs = %+v, want 2", runs)
+	}
+
+	scheduled, manual := runs.Data[0], runs.Data[1]
+	if manual.ID != result.RunIDs[0] || manual.Trigger != model.SyncTriggerManual || manual.Mode != "incremental" {
+		t.Fatalf("manual run = %+v", manual)
+	}
+	if manual.Status != model.SyncRunSucceeded || manual.FinishedAt == nil || manual.Added != 3 || manual.Total != 3 || !manual.Complete {
+		t.Fatalf("manual run = %+v, want 3 added", manual)
+	}
+	// One page of the playlist and one lookup of its videos.
+	if manual.QuotaUnits != 2 {
+		t.Fatalf("manual run spent %d units, want 2", manual.QuotaUnits)
+	}
+	if scheduled.Trigger != model.SyncTriggerSchedule || scheduled.Status != model.SyncRunSucceeded || scheduled.Added != 1 {
+		t.Fatalf("scheduled run = %+v, want 1 added", scheduled)
+	}
+
+	runs = app.syncRuns(t, "?trigger=schedule&channel_id="+testChannelID)
+	if runs.Meta.Total != 1 || runs.Data[0].ID != scheduled.ID {
+		t.Fatalf("scheduled runs = %+v, want only the scheduled run", runs)
+	}
+}
+
+func TestSyncRunRecordsVideoErrors(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 3)
+	app.seed(t, `CREATE TRIGGER reject_vid02 BEFORE INSERT ON videos WHEN NEW.id = 'vid02'
+		BEGIN SELECT RAISE(ABORT, 'rejected'); END`)
+
+	result := app.sync(t, "")
+	if result.Added != 2 || result.Errors != 1 {
+		t.Fatalf("sync = %+v, want 2 added and 1 error", result)
+	}
+
+	run := app.syncRun(t, result.RunIDs[0])
+	if run.Status != model.SyncRunSucceeded || run.Errors != 1 || len(run.VideoErrors) != 1 {
+		t.Fatalf("run = %+v, want 1 video error", run)
+	}
+	if run.VideoErrors[0].VideoID != "vid02" || run.VideoErrors[0].Message == "" {
+		t.Fatalf("video errors = %+v, want vid02", run.VideoErrors)
+	}
+
+	// Only a single run lists its video errors.
+	if runs := app.syncRuns(t, ""); runs.Data[0].VideoErrors != nil {
+		t.Fatalf("listed run = %+v, want no video errors", runs.Data[0])
+	}
+}
+
+func TestSyncRunRecordsFailures(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 1)
+	app.handler.SetQuotaBudget(0)
+
+	app.handler.SyncChannelScheduled(testChannelID)
+
+	runs := app.syncRuns(t, "?status=failed")
+	if runs.Meta.Total != 1 {
+		t.Fatalf("failed runs = %+v, want 1", runs)
+	}
+	if run := runs.Data[0]; !run.Deferred || run.Error == nil || run.QuotaUnits != 0 {
+		t.Fatalf("failed run = %+v, want deferred with an error", run)
+	}
+
+	// A channel that isn't registered has no run.
+	app.handler.SyncChannelScheduled("UCunknown")
+	if runs := app.syncRuns(t, ""); runs.Meta.Total != 1 {
+		t.Fatalf("runs = %+v, want only the failed run", runs)
+	}
+}
+
+func TestGetSyncRunErrors(t *testing.T) {
+	app := newTestApp(t)
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/api/sync/runs/1", http.StatusNotFound},
+		{"/api/sync/runs/abc", http.StatusBadRequest},
+		{"/api/sync/runs?trigger=cron", http.StatusBadRequest},
+		{"/api/sync/runs?status=done", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if resp := app.do(t, http.MethodGet, tt.target, "", nil); resp.StatusCode != tt.status {
+			t.Errorf("GET %s: status = %d, want %d", tt.target, resp.StatusCode, tt.status)
+		}
+	}
+}
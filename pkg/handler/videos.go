@@ -1,25 +1,41 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 
 	"github.com/gofiber/fiber/v3"
 
 	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/utils"
 )
 
 // GetVideos godoc
 // @Summary Get all videos
-// @Description Get all videos with optional search and pagination
+// @Description Get all videos with optional search, filters and pagination. The meta counts the videos found by each genre, platform, developer and release year of their games, and by whether they have a game; each count leaves out its own filter.
 // @Tags videos
 // @Accept  json
 // @Produce  json
 // @Param offset query int false "Offset" default(0)
 // @Param limit query int false "Limit" default(24)
-// @Param search query string false "Search by video title or game name"
+// @Param search query string false "Search by video title, description or game name"
+// @Param mode query string false "Search mode" Enums(ranked)
+// @Param channel_id query string false "Only videos from this channel"
+// @Param min_duration query int false "Minimum duration in seconds"
+// @Param max_duration query int false "Maximum duration in seconds"
+// @Param is_live query bool false "Only live streams (true) or only regular uploads (false)"
+// @Param include_unavailable query bool false "Include videos that were made private or removed" default(false)
+// @Param genre query int false "Only videos with a game of this IGDB genre ID"
+// @Param platform query int false "Only videos with a game on this IGDB platform ID"
+// @Param developer query int false "Only videos with a game by this IGDB company ID"
+// @Param min_release_year query int false "Only videos with a game first released in or after this year"
+// @Param max_release_year query int false "Only videos with a game first released in or before this year"
+// @Param matched query bool false "Only videos with a game (true) or without one (false)"
 // @Success 200 {object} model.APIResponse[[]model.VideoResponse]
 // @Failure 400 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
 // @Router /videos [get]
 func (h *Handler) GetVideos(c fiber.Ctx) error {
 	ctx := c.RequestCtx()
@@ -29,22 +45,31 @@ func (h *Handler) GetVideos(c fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
 	}
 
-	search := c.Query("search", "")
+	filter, err := GetVideoFilter(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
 
-	videos, err := h.repo.GetVideos(ctx, *q, search)
+	videos, err := h.repo.GetVideos(ctx, *q, filter)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
 	}
 
-	total, err := h.repo.GetVideoTotalItems(ctx, search)
+	total, err := h.repo.GetVideoTotalItems(ctx, filter)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
+	}
+
+	facets, err := h.repo.GetVideoFacets(ctx, filter)
+	if err != nil {
+		return sendError(c, err)
 	}
 
 	meta := &model.Meta{
 		Total:  total,
 		Limit:  q.Limit,
 		Offset: q.Offset,
+		Facets: facets,
 	}
 
 	return c.JSON(Response(videos, meta))
@@ -59,7 +84,9 @@ func (h *Handler) GetVideos(c fiber.Ctx) error {
 // @Param id path string true "Video ID"
 // @Success 200 {object} model.APIResponse[model.VideoResponse]
 // @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
 // @Router /videos/{id} [get]
 func (h *Handler) GetVideoByID(c fiber.Ctx) error {
 	ctx := c.RequestCtx()
@@ -71,15 +98,15 @@ func (h *Handler) GetVideoByID(c fiber.Ctx) error {
 
 	video, err := h.repo.GetVideoByID(ctx, id)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
 	}
 
 	return c.JSON(Response(video, nil))
 }
 
 // UpdateVideoGame godoc
-// @Summary Update video's game match
-// @Description Match a video with a game
+// @Summary Set video's primary game
+// @Description Make a game the primary game of a video, adding it to the video's games if needed. The unmatched episodes of the video's series may then be given the game.
 // @Tags videos
 // @Accept  json
 // @Produce  json
@@ -87,7 +114,10 @@ func (h *Handler) GetVideoByID(c fiber.Ctx) error {
 // @Param game body model.UpdateVideoGameRequest true "Game ID to match"
 // @Success 200
 // @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
 // @Router /videos/{id}/game [put]
 func (h *Handler) UpdateVideoGame(c fiber.Ctx) error {
 	ctx := c.RequestCtx()
@@ -102,17 +132,23 @@ func (h *Handler) UpdateVideoGame(c fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(ErrInvalidRequestBody)
 	}
 
+	if _, err := h.repo.GetVideoByID(ctx, videoID); err != nil {
+		return sendError(c, err)
+	}
+
 	err := h.repo.UpdateVideoGame(ctx, videoID, requestBody.GameID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
 	}
 
+	h.inferVideoSeriesGames(ctx, videoID)
+
 	return c.SendStatus(http.StatusOK)
 }
 
 // DeleteVideoGame godoc
-// @Summary Remove game match from video
-// @Description Remove the game association from a video
+// @Summary Remove video's primary game
+// @Description Remove the primary game from a video; its next game, if any, becomes primary
 // @Tags videos
 // @Accept  json
 // @Produce  json
@@ -120,6 +156,7 @@ func (h *Handler) UpdateVideoGame(c fiber.Ctx) error {
 // @Success 200
 // @Failure 400 {object} model.Error
 // @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
 // @Router /videos/{id}/game [delete]
 func (h *Handler) DeleteVideoGame(c fiber.Ctx) error {
 	ctx := c.RequestCtx()
@@ -131,8 +168,163 @@ func (h *Handler) DeleteVideoGame(c fiber.Ctx) error {
 
 	err := h.repo.DeleteVideoGame(ctx, videoID)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(model.Error{Error: err.Error()})
+		return sendError(c, err)
 	}
 
 	return c.SendStatus(http.StatusOK)
 }
+
+// AddVideoGame godoc
+// @Summary Add a game to a video
+// @Description Add a game to the end of a video's games, optionally with the segment of the video it covers. If the game is already on the video, its segment is updated.
+// @Tags videos
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Video ID"
+// @Param game body model.AddVideoGameRequest true "Game and segment"
+// @Success 200 {object} model.APIResponse[model.VideoResponse]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /videos/{id}/games [post]
+func (h *Handler) AddVideoGame(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	videoID := c.Params("id")
+	if videoID == "" {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidPathParams)
+	}
+
+	var requestBody model.AddVideoGameRequest
+	if err := c.Bind().JSON(&requestBody); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidRequestBody)
+	}
+
+	if err := validateSegment(requestBody.StartSeconds, requestBody.EndSeconds); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
+
+	if _, err := h.repo.GetVideoByID(ctx, videoID); err != nil {
+		return sendError(c, err)
+	}
+
+	if err := h.repo.AddVideoGame(ctx, videoID, requestBody); err != nil {
+		return sendError(c, err)
+	}
+
+	return h.sendVideo(c, videoID)
+}
+
+// RemoveVideoGame godoc
+// @Summary Remove a game from a video
+// @Description Remove one of a video's games; if it was primary, the next game becomes primary
+// @Tags videos
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Video ID"
+// @Param gameId path int true "Game ID"
+// @Success 200 {object} model.APIResponse[model.VideoResponse]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /videos/{id}/games/{gameId} [delete]
+func (h *Handler) RemoveVideoGame(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	videoID := c.Params("id")
+	gameID, err := fiber.Convert(c.Params("gameId"), utils.Atoi64)
+	if videoID == "" || err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidPathParams)
+	}
+
+	video, err := h.repo.GetVideoByID(ctx, videoID)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	if !slices.ContainsFunc(video.Games, func(g model.VideoGame) bool { return int64(g.ID) == gameID }) {
+		return c.Status(http.StatusNotFound).JSON(model.Error{Error: "game is not on this video"})
+	}
+
+	if err := h.repo.RemoveVideoGame(ctx, videoID, gameID); err != nil {
+		return sendError(c, err)
+	}
+
+	return h.sendVideo(c, videoID)
+}
+
+// ReorderVideoGames godoc
+// @Summary Reorder a video's games
+// @Description Put a video's games in the given order; the first becomes the primary game. Every game on the video must be listed exactly once.
+// @Tags videos
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Video ID"
+// @Param order body model.ReorderVideoGamesRequest true "Game IDs in the new order"
+// @Success 200 {object} model.APIResponse[model.VideoResponse]
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /videos/{id}/games/order [put]
+func (h *Handler) ReorderVideoGames(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	videoID := c.Params("id")
+	if videoID == "" {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidPathParams)
+	}
+
+	var requestBody model.ReorderVideoGamesRequest
+	if err := c.Bind().JSON(&requestBody); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrInvalidRequestBody)
+	}
+
+	video, err := h.repo.GetVideoByID(ctx, videoID)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	current := make([]int64, len(video.Games))
+	for i, g := range video.Games {
+		current[i] = int64(g.ID)
+	}
+	requested := slices.Clone(requestBody.GameIDs)
+	slices.Sort(current)
+	slices.Sort(requested)
+	if !slices.Equal(current, requested) {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: "game_ids must list every game on the video exactly once"})
+	}
+
+	if err := h.repo.ReorderVideoGames(ctx, videoID, requestBody.GameIDs); err != nil {
+		return sendError(c, err)
+	}
+
+	return h.sendVideo(c, videoID)
+}
+
+// sendVideo responds with the current state of a video.
+func (h *Handler) sendVideo(c fiber.Ctx, videoID string) error {
+	video, err := h.repo.GetVideoByID(c.RequestCtx(), videoID)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.JSON(Response(video, nil))
+}
+
+func validateSegment(start, end *int32) error {
+	if start != nil && *start < 0 {
+		return errors.New("start_seconds must not be negative")
+	}
+	if end != nil && *end <= 0 {
+		return errors.New("end_seconds must be positive")
+	}
+	if start != nil && end != nil && *end <= *start {
+		return errors.New("end_seconds must be after start_seconds")
+	}
+	return nil
+}
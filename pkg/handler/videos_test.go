@@ -0,0 +1,305 @@
+package handler_test
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+func TestGetVideosReturnsPageAndMeta(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO videos (id, title, published_at) VALUES
+		('vid1', 'Hollow Knight EP.1', '2024-01-01 10:00:00'),
+		('vid2', 'Hollow Knight EP.2', '2024-01-02 10:00:00'),
+		('vid3', 'Celeste EP.1', '2024-01-03 10:00:00')`)
+
+	var body model.APIResponse[[]model.VideoResponse]
+	resp := app.do(t, http.MethodGet, "/api/videos?search=hollow&limit=1", "", &body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if len(body.Data) != 1 || body.Data[0].ID != "vid2" {
+		t.Fatalf("data = %+v, want only vid2", body.Data)
+	}
+	if body.Meta == nil || body.Meta.Total != 2 || body.Meta.Limit != 1 {
+		t.Fatalf("meta = %+v, want total 2 limit 1", body.Meta)
+	}
+}
+
+func TestUpdateAndDeleteVideoGame(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES (14593, 'Hollow Knight', 'https://www.igdb.com/games/hollow-knight')`)
+	app.seed(t, `INSERT INTO videos (id, title, published_at) VALUES ('vid1', 'Hollow Knight EP.1', '2024-01-01 10:00:00')`)
+
+	resp := app.do(t, http.MethodPut, "/api/videos/vid1/game", `{"game_id": 14593}`, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200", resp.StatusCode)
+	}
+
+	var body model.APIResponse[model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos/vid1", "", &body)
+	if body.Data.Game == nil || body.Data.Game.ID != 14593 || body.Data.Game.Name != "Hollow Knight" {
+		t.Fatalf("game = %+v, want Hollow Knight", body.Data.Game)
+	}
+
+	resp = app.do(t, http.MethodDelete, "/api/videos/vid1/game", "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("DELETE status = %d, want 200", resp.StatusCode)
+	}
+
+	body = model.APIResponse[model.VideoResponse]{}
+	app.do(t, http.MethodGet, "/api/videos/vid1", "", &body)
+	if body.Data.Game != nil {
+		t.Fatalf("game = %+v, want nil", body.Data.Game)
+	}
+}
+
+func TestUpdateVideoGameRejectsInvalidBody(t *testing.T) {
+	app := newTestApp(t)
+
+	resp := app.do(t, http.MethodPut, "/api/videos/vid1/game", `{"game_id": "abc"}`, nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestVideoGamesEndpoints(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES (1, 'Hades', 'u1'), (2, 'Celeste', 'u2')`)
+	app.seed(t, `INSERT INTO videos (id, title, published_at) VALUES ('vid1', 'Indie marathon', '2024-01-01 10:00:00')`)
+
+	var body model.APIResponse[model.VideoResponse]
+	resp := app.do(t, http.MethodPost, "/api/videos/vid1/games", `{"game_id": 1, "start_seconds": 0, "end_seconds": 1800}`, &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST status = %d, want 200", resp.StatusCode)
+	}
+	app.do(t, http.MethodPost, "/api/videos/vid1/games", `{"game_id": 2, "start_seconds": 1800}`, nil)
+
+	body = model.APIResponse[model.VideoResponse]{}
+	resp = app.do(t, http.MethodPut, "/api/videos/vid1/games/order", `{"game_ids": [2, 1]}`, &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PUT order status = %d, want 200", resp.StatusCode)
+	}
+	if body.Data.Game == nil || body.Data.Game.ID != 2 || len(body.Data.Games) != 2 || body.Data.Games[1].ID != 1 {
+		t.Fatalf("video = %+v, want Celeste then Hades", body.Data)
+	}
+	if end := body.Data.Games[1].EndSeconds; end == nil || *end != 1800 {
+		t.Fatalf("Hades end_seconds = %v, want 1800", end)
+	}
+
+	body = model.APIResponse[model.VideoResponse]{}
+	resp = app.do(t, http.MethodDelete, "/api/videos/vid1/games/2", "", &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("DELETE status = %d, want 200", resp.StatusCode)
+	}
+	if body.Data.Game == nil || body.Data.Game.ID != 1 || len(body.Data.Games) != 1 {
+		t.Fatalf("video = %+v, want only Hades", body.Data)
+	}
+}
+
+func TestVideoGamesEndpointsValidate(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO games (id, name, url) VALUES (1, 'Hades', 'u1'), (2, 'Celeste', 'u2')`)
+	app.seed(t, `INSERT INTO videos (id, title, published_at) VALUES ('vid1', 'Indie marathon', '2024-01-01 10:00:00')`)
+	app.seed(t, `INSERT INTO video_games (video_id, game_id, position) VALUES ('vid1', 1, 0)`)
+
+	tests := []struct {
+		method, target, body string
+		want                 int
+	}{
+		{http.MethodPost, "/api/videos/vid1/games", `{"game_id": 2, "start_seconds": 60, "end_seconds": 30}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/videos/vid1/games", `{"game_id": 2, "start_seconds": -1}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/videos/missing/games", `{"game_id": 2}`, http.StatusNotFound},
+		{http.MethodPost, "/api/videos/vid1/games", `{"game_id": 99}`, http.StatusConflict},
+		{http.MethodPut, "/api/videos/vid1/games/order", `{"game_ids": [1, 2]}`, http.StatusBadRequest},
+		{http.MethodPut, "/api/videos/vid1/games/order", `{"game_ids": [1, 1]}`, http.StatusBadRequest},
+		{http.MethodDelete, "/api/videos/vid1/games/2", "", http.StatusNotFound},
+		{http.MethodDelete, "/api/videos/vid1/games/abc", "", http.StatusBadRequest},
+		{http.MethodPut, "/api/videos/missing/game", `{"game_id": 1}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		if resp := app.do(t, tt.method, tt.target, tt.body, nil); resp.StatusCode != tt.want {
+			t.Errorf("%s %s %s: status = %d, want %d", tt.method, tt.target, tt.body, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+func TestGetVideosRankedSearch(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO videos (id, title, description, published_at) VALUES
+		('vid1', 'Hollow Knight EP.1', NULL, '2024-01-02 10:00:00'),
+		('vid2', 'Bug game', 'Hollow Knight, part one', '2024-01-01 10:00:00')`)
+
+	var body model.APIResponse[[]model.VideoResponse]
+	resp := app.do(t, http.MethodGet, "/api/videos?search=knight&mode=ranked", "", &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if len(body.Data) != 2 || body.Data[0].Match == nil || body.Data[1].Match == nil {
+		t.Fatalf("data = %+v, want two ranked videos", body.Data)
+	}
+	if h := body.Data[0].Match.Highlights["title"]; h != "Hollow <mark>Knight</mark> EP.1" {
+		t.Fatalf("title highlight = %q", h)
+	}
+	if h := body.Data[1].Match.Highlights["description"]; h != "Hollow <mark>Knight</mark>, part one" {
+		t.Fatalf("description highlight = %q", h)
+	}
+
+	resp = app.do(t, http.MethodGet, "/api/videos?search=knight&mode=fuzzy", "", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unknown mode: status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestGetVideosFilterByDetails(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO videos (id, title, published_at, duration_seconds, live_status) VALUES
+		('short', 'Short', '2024-01-01 10:00:00', 60, 'none'),
+		('long', 'Long', '2024-01-02 10:00:00', 7200, 'none'),
+		('stream', 'Stream', '2024-01-03 10:00:00', 10800, 'completed'),
+		('upcoming', 'Upcoming', '2024-01-04 10:00:00', NULL, 'upcoming'),
+		('unknown', 'Not looked up', '2024-01-05 10:00:00', NULL, NULL)`)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"unknown", "upcoming", "stream", "long", "short"}},
+		{"min_duration=3600", []string{"stream", "long"}},
+		{"max_duration=3600", []string{"short"}},
+		{"min_duration=3600&max_duration=7200", []string{"long"}},
+		{"is_live=true", []string{"upcoming", "stream"}},
+		{"is_live=false", []string{"long", "short"}},
+		{"is_live=false&min_duration=120", []string{"long"}},
+	}
+
+	for _, tt := range tests {
+		var body model.APIResponse[[]model.VideoResponse]
+		resp := app.do(t, http.MethodGet, "/api/videos?"+tt.query, "", &body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", tt.query, resp.StatusCode)
+		}
+
+		var got []string
+		for _, v := range body.Data {
+			got = append(got, v.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") || body.Meta.Total != int64(len(tt.want)) {
+			t.Errorf("%s: got %v (total %d), want %v", tt.query, got, body.Meta.Total, tt.want)
+		}
+	}
+
+	for _, query := range []string{"is_live=maybe", "min_duration=-1", "max_duration=1h"} {
+		if resp := app.do(t, http.MethodGet, "/api/videos?"+query, "", nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestGetVideosFilterAndFacetByGame(t *testing.T) {
+	app := newTestApp(t)
+	app.seed(t, `INSERT INTO games (id, name, url, first_release_date) VALUES
+		(1, 'Hades', 'u1', '2020-09-17 00:00:00'),
+		(2, 'Celeste', 'u2', '2018-01-25 00:00:00'),
+		(3, 'Hollow Knight', 'u3', '2017-02-24 00:00:00')`)
+	app.seed(t, `INSERT INTO genres (id, name) VALUES (12, 'RPG'), (31, 'Adventure'), (8, 'Platform')`)
+	app.seed(t, `INSERT INTO game_genres (game_id, genre_id) VALUES (1, 12), (1, 31), (2, 8), (3, 8), (3, 31)`)
+	app.seed(t, `INSERT INTO platforms (id, name, abbreviation) VALUES (6, 'PC (Microsoft Windows)', 'PC'), (130, 'Nintendo Switch', 'Switch')`)
+	app.seed(t, `INSERT INTO game_platforms (game_id, platform_id) VALUES (1, 6), (1, 130), (2, 6), (3, 130)`)
+	app.seed(t, `INSERT INTO companies (id, name) VALUES (1, 'Supergiant Games'), (2, 'Maddy Makes Games'), (3, 'Team Cherry'), (4, 'Fangamer')`)
+	app.seed(t, `INSERT INTO game_companies (game_id, company_id, developer, publisher) VALUES
+		(1, 1, 1, 1), (2, 2, 1, 1), (3, 3, 1, 1), (3, 4, 0, 1)`)
+	app.seed(t, `INSERT INTO videos (id, title, published_at, game_id) VALUES
+		('hades', 'Hades EP.1', '2024-01-01 10:00:00', 1),
+		('celeste', 'Celeste EP.1', '2024-01-02 10:00:00', 2),
+		('marathon', 'Indie marathon', '2024-01-03 10:00:00', 2),
+		('chat', 'Just chatting', '2024-01-04 10:00:00', NULL)`)
+	app.seed(t, `INSERT INTO video_games (video_id, game_id, position) VALUES
+		('hades', 1, 0), ('celeste', 2, 0), ('marathon', 2, 0), ('marathon', 3, 1)`)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"chat", "marathon", "celeste", "hades"}},
+		{"genre=8", []string{"marathon", "celeste"}},
+		{"platform=130", []string{"marathon", "hades"}},
+		{"genre=8&platform=130", []string{"marathon"}},
+		{"developer=3", []string{"marathon"}},
+		{"developer=4", nil},
+		{"min_release_year=2018", []string{"marathon", "celeste", "hades"}},
+		{"max_release_year=2017", []string{"marathon"}},
+		{"min_release_year=2019&max_release_year=2020", []string{"hades"}},
+		{"matched=false", []string{"chat"}},
+		{"matched=true&search=hades", []string{"hades"}},
+	}
+
+	for _, tt := range tests {
+		var body model.APIResponse[[]model.VideoResponse]
+		resp := app.do(t, http.MethodGet, "/api/videos?"+tt.query, "", &body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", tt.query, resp.StatusCode)
+		}
+
+		var got []string
+		for _, v := range body.Data {
+			got = append(got, v.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") || body.Meta.Total != int64(len(tt.want)) {
+			t.Errorf("%s: got %v (total %d), want %v", tt.query, got, body.Meta.Total, tt.want)
+		}
+	}
+
+	var body model.APIResponse[[]model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos", "", &body)
+	facets := body.Meta.Facets
+	if facets == nil {
+		t.Fatal("facets = nil, want counts")
+	}
+	if want := []model.Facet{{ID: 31, Name: "Adventure", Count: 2}, {ID: 8, Name: "Platform", Count: 2}, {ID: 12, Name: "RPG", Count: 1}}; !slices.Equal(facets.Genres, want) {
+		t.Errorf("genres = %+v, want %+v", facets.Genres, want)
+	}
+	if want := []model.ReleaseYearFacet{{Year: 2020, Count: 1}, {Year: 2018, Count: 2}, {Year: 2017, Count: 1}}; !slices.Equal(facets.ReleaseYears, want) {
+		t.Errorf("release years = %+v, want %+v", facets.ReleaseYears, want)
+	}
+	// Fangamer only published Hollow Knight.
+	if len(facets.Developers) != 3 || slices.ContainsFunc(facets.Developers, func(f model.Facet) bool { return f.ID == 4 }) {
+		t.Errorf("developers = %+v, want the three developers", facets.Developers)
+	}
+	if want := (model.MatchedFacet{Matched: 3, Unmatched: 1}); facets.Matched != want {
+		t.Errorf("matched = %+v, want %+v", facets.Matched, want)
+	}
+
+	// The genre counts leave out the genre filter; the others don't.
+	body = model.APIResponse[[]model.VideoResponse]{}
+	app.do(t, http.MethodGet, "/api/videos?genre=8", "", &body)
+	facets = body.Meta.Facets
+	if len(facets.Genres) != 3 {
+		t.Errorf("genres with genre=8 = %+v, want every genre", facets.Genres)
+	}
+	if want := []model.Facet{{ID: 6, Name: "PC (Microsoft Windows)", Count: 2}, {ID: 130, Name: "Nintendo Switch", Count: 1}}; !slices.Equal(facets.Platforms, want) {
+		t.Errorf("platforms with genre=8 = %+v, want %+v", facets.Platforms, want)
+	}
+	if want := (model.MatchedFacet{Matched: 2}); facets.Matched != want {
+		t.Errorf("matched with genre=8 = %+v, want %+v", facets.Matched, want)
+	}
+
+	// Every filter with a search of short terms stays within D1's limit of
+	// bound parameters.
+	all := "genre=8&platform=130&developer=3&min_release_year=2010&max_release_year=2020&matched=true" +
+		"&channel_id=UC1&min_duration=1&max_duration=9000&is_live=false&search=in+ma+ep+1+hd+v2"
+	if resp := app.do(t, http.MethodGet, "/api/videos?"+all, "", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("every filter: status = %d, want 200", resp.StatusCode)
+	}
+
+	for _, query := range []string{"genre=rpg", "platform=0", "developer=-1", "min_release_year=20xx", "max_release_year=0", "matched=maybe"} {
+		if resp := app.do(t, http.MethodGet, "/api/videos?"+query, "", nil); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,542 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/robfig/cron/v3"
+	"google.golang.org/api/youtube/v3"
+
+	"github.com/K0ng2/zeedzad/db"
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+const (
+	// YouTubeHubURL is the hub YouTube publishes channel feeds to.
+	YouTubeHubURL = "https://pubsubhubbub.appspot.com/subscribe"
+
+	channelFeedURL = "https://www.youtube.com/xml/feeds/videos.xml?channel_id="
+
+	// websubLeaseSeconds is the lease asked for, 10 days; the hub may grant
+	// a shorter one.
+	websubLeaseSeconds = 10 * 24 * 60 * 60
+	// Subscriptions are renewed when their lease has less than a day left,
+	// and requested again if the hub hasn't verified them within an hour.
+	websubRenewBefore = 24 * time.Hour
+	websubRetryAfter  = time.Hour
+	websubLogPrefix   = "[WebSub]"
+
+	// websubBusyRetryAfter is when the hub is asked to deliver a
+	// notification again if its channel is being synced.
+	websubBusyRetryAfter = time.Minute
+)
+
+// WebSubConfig configures the WebSub subscriptions that push new uploads
+// as they are published.
+type WebSubConfig struct {
+	// HubURL is the hub to subscribe with; YouTubeHubURL if empty.
+	HubURL string
+	// CallbackURL is the public URL of /api/websub/callback, which the hub
+	// calls back with the channel ID appended.
+	CallbackURL string
+	// Secret is shared with the hub to sign notifications. It is required:
+	// each notification spends YouTube quota, so unsigned ones would let
+	// anyone use up the day's budget.
+	Secret string
+	// Client sends requests to the hub; http.DefaultClient if nil.
+	Client *http.Client
+}
+
+type webSubscriber struct {
+	WebSubConfig
+	cron *cron.Cron
+}
+
+// StartWebSub subscribes every enabled channel to push notifications and
+// renews the subscriptions hourly before their leases run out.
+func (h *Handler) StartWebSub(ctx context.Context, cfg WebSubConfig) error {
+	callback, err := url.Parse(cfg.CallbackURL)
+	if err != nil || !callback.IsAbs() {
+		return fmt.Errorf("invalid websub callback url %q", cfg.CallbackURL)
+	}
+	if cfg.Secret == "" {
+		return errors.New("websub needs a secret to authenticate notifications")
+	}
+	if cfg.HubURL == "" {
+		cfg.HubURL = YouTubeHubURL
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+
+	h.websub = &webSubscriber{WebSubConfig: cfg, cron: cron.New()}
+
+	if _, err := h.websub.cron.AddFunc("@hourly", func() {
+		h.updateSubscriptionsOrLog(context.Background())
+	}); err != nil {
+		return err
+	}
+
+	if err := h.updateSubscriptions(ctx); err != nil {
+		return err
+	}
+
+	h.websub.cron.Start()
+	return nil
+}
+
+// StopWebSub stops renewing subscriptions. The hub keeps pushing until the
+// leases run out.
+func (h *Handler) StopWebSub() {
+	if h.websub != nil {
+		<-h.websub.cron.Stop().Done()
+	}
+}
+
+// updateSubscriptions subscribes the enabled channels that aren't
+// subscribed or whose lease is about to run out, and unsubscribes the
+// disabled ones.
+func (h *Handler) updateSubscriptions(ctx context.Context) error {
+	if h.websub == nil {
+		return nil
+	}
+
+	channels, err := h.repo.GetChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("load channels: %w", err)
+	}
+
+	subscriptions, err := h.repo.GetWebSubSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("load websub subscriptions: %w", err)
+	}
+
+	byChannel := make(map[string]*repoModel.WebsubSubscriptions, len(subscriptions))
+	for i := range subscriptions {
+		byChannel[*subscriptions[i].ChannelID] = &subscriptions[i]
+	}
+
+	now := time.Now().UTC()
+	for _, ch := range channels {
+		subscription := byChannel[ch.ID]
+
+		if !ch.SyncEnabled {
+			if subscription != nil {
+				h.unsubscribeOrLog(ctx, ch.ID)
+			}
+			continue
+		}
+
+		if subscriptionDue(subscription, now) {
+			if err := h.subscribe(ctx, ch.ID, subscription); err != nil {
+				log.Printf("%s failed to subscribe to %s: %v", websubLogPrefix, ch.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateSubscriptionsOrLog updates the subscriptions after a channel
+// changed. The change itself has been saved, so a failure is only logged;
+// the hourly renewal catches up.
+func (h *Handler) updateSubscriptionsOrLog(ctx context.Context) {
+	if err := h.updateSubscriptions(ctx); err != nil {
+		log.Printf("%s failed to update subscriptions: %v", websubLogPrefix, err)
+	}
+}
+
+// subscriptionDue reports whether a channel should be subscribed again.
+func subscriptionDue(subscription *repoModel.WebsubSubscriptions, now time.Time) bool {
+	switch {
+	case subscription == nil:
+		return true
+	case subscription.Status == model.PushStatusActive:
+		return subscription.ExpiresAt == nil || subscription.ExpiresAt.Before(now.Add(websubRenewBefore))
+	default:
+		return subscription.RequestedAt.Before(now.Add(-websubRetryAfter))
+	}
+}
+
+// subscribe asks the hub for a subscription, or for a new lease on an
+// active one. The request is saved first, since the hub verifies it by
+// calling back before it answers.
+func (h *Handler) subscribe(ctx context.Context, channelID string, current *repoModel.WebsubSubscriptions) error {
+	subscription := repoModel.WebsubSubscriptions{ChannelID: &channelID, Status: model.PushStatusPending}
+	if current != nil && current.Status == model.PushStatusActive {
+		// The hub keeps the current lease until it verifies the new one.
+		subscription = *current
+	}
+	subscription.Topic = channelTopic(channelID)
+	subscription.RequestedAt = time.Now().UTC()
+
+	if err := h.repo.SaveWebSubSubscription(ctx, subscription); err != nil {
+		return err
+	}
+
+	return h.websub.request(ctx, "subscribe", channelID)
+}
+
+// unsubscribeOrLog stops the push notifications of a channel. A failure is
+// only logged: without renewals, the lease runs out on its own.
+func (h *Handler) unsubscribeOrLog(ctx context.Context, channelID string) {
+	if h.websub == nil {
+		return
+	}
+
+	if _, err := h.repo.GetWebSubSubscription(ctx, channelID); errors.Is(err, db.ErrNotFound) {
+		return
+	}
+
+	// The subscription is forgotten first, so the hub's verification
+	// finds it unwanted.
+	if err := h.repo.DeleteWebSubSubscription(ctx, channelID); err != nil {
+		log.Printf("%s failed to unsubscribe from %s: %v", websubLogPrefix, channelID, err)
+		return
+	}
+	if err := h.websub.request(ctx, "unsubscribe", channelID); err != nil {
+		log.Printf("%s failed to unsubscribe from %s: %v", websubLogPrefix, channelID, err)
+	}
+}
+
+// request sends a subscribe or unsubscribe request to the hub.
+func (w *webSubscriber) request(ctx context.Context, mode, channelID string) error {
+	form := url.Values{
+		"hub.callback": {strings.TrimSuffix(w.CallbackURL, "/") + "/" + url.PathEscape(channelID)},
+		"hub.mode":     {mode},
+		"hub.topic":    {channelTopic(channelID)},
+		"hub.verify":   {"async"},
+	}
+	if mode == "subscribe" {
+		form.Set("hub.lease_seconds", strconv.Itoa(websubLeaseSeconds))
+		form.Set("hub.secret", w.Secret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.HubURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("hub responded %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// channelTopic is the feed of a channel's uploads that the hub publishes.
+func channelTopic(channelID string) string {
+	return channelFeedURL + url.QueryEscape(channelID)
+}
+
+// VerifyWebSub godoc
+// @Summary Verify a WebSub subscription
+// @Description Called by the hub to confirm a subscribe or unsubscribe request by echoing hub.challenge, or to report that it denied a subscription
+// @Tags websub
+// @Produce  plain
+// @Param id path string true "Channel ID"
+// @Param hub.mode query string true "Request being verified" Enums(subscribe, unsubscribe, denied)
+// @Param hub.topic query string true "Channel feed URL"
+// @Param hub.challenge query string false "Challenge to echo"
+// @Param hub.lease_seconds query int false "Lease granted by the hub"
+// @Success 200 {string} string "The challenge"
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Router /websub/callback/{id} [get]
+func (h *Handler) VerifyWebSub(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	channelID := c.Params("id")
+	if h.websub == nil || c.Query("hub.topic") != channelTopic(channelID) {
+		return c.Status(http.StatusNotFound).JSON(model.Error{Error: "no such subscription"})
+	}
+
+	subscription, err := h.repo.GetWebSubSubscription(ctx, channelID)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return sendError(c, err)
+	}
+
+	switch c.Query("hub.mode") {
+	case "subscribe":
+		now := time.Now().UTC()
+		if !awaitingVerification(subscription, now) {
+			return c.Status(http.StatusNotFound).JSON(model.Error{Error: "no such subscription"})
+		}
+
+		subscription.Status = model.PushStatusActive
+		subscription.VerifiedAt = &now
+		subscription.LeaseSeconds, subscription.ExpiresAt = nil, nil
+		if lease := fiber.Query(c, "hub.lease_seconds", 0); lease > 0 {
+			// A hub may grant less than was asked for, never more.
+			lease = min(lease, websubLeaseSeconds)
+			seconds, expiresAt := int32(lease), now.Add(time.Duration(lease)*time.Second)
+			subscription.LeaseSeconds, subscription.ExpiresAt = &seconds, &expiresAt
+		}
+		if err := h.repo.SaveWebSubSubscription(ctx, *subscription); err != nil {
+			return sendError(c, err)
+		}
+
+	case "unsubscribe":
+		if subscription != nil {
+			return c.Status(http.StatusNotFound).JSON(model.Error{Error: "subscription still wanted"})
+		}
+
+	case "denied":
+		if subscription != nil {
+			log.Printf("%s hub denied subscription to %s: %s", websubLogPrefix, channelID, c.Query("hub.reason"))
+			subscription.Status = model.PushStatusDenied
+			if err := h.repo.SaveWebSubSubscription(ctx, *subscription); err != nil {
+				return sendError(c, err)
+			}
+		}
+		return c.SendStatus(http.StatusOK)
+
+	default:
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: "unknown hub.mode"})
+	}
+
+	return c.SendString(c.Query("hub.challenge"))
+}
+
+// ReceiveWebSub godoc
+// @Summary Receive a WebSub notification
+// @Description Called by the hub when a channel publishes or updates a video, with the Atom entry of the video, or deletes one. The videos are looked up and stored right away, unless the channel is being synced: then the notification is refused with a Retry-After, for the hub to deliver again. Notifications whose X-Hub-Signature doesn't match the secret are acknowledged and ignored.
+// @Tags websub
+// @Accept  xml
+// @Param id path string true "Channel ID"
+// @Param X-Hub-Signature header string false "HMAC of the body, e.g. sha1=..."
+// @Success 204
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 503 {object} model.Error
+// @Router /websub/callback/{id} [post]
+func (h *Handler) ReceiveWebSub(c fiber.Ctx) error {
+	ctx := c.RequestCtx()
+
+	channelID := c.Params("id")
+	if h.websub == nil {
+		return c.Status(http.StatusNotFound).JSON(model.Error{Error: "no such subscription"})
+	}
+
+	body := c.Body()
+	if !validHubSignature(c.Get("X-Hub-Signature"), body, h.websub.Secret) {
+		// A subscriber must acknowledge notifications it ignores.
+		log.Printf("%s ignored notification for %s with an invalid signature", websubLogPrefix, channelID)
+		return c.SendStatus(http.StatusNoContent)
+	}
+
+	subscription, err := h.repo.GetWebSubSubscription(ctx, channelID)
+	if errors.Is(err, db.ErrNotFound) {
+		return c.SendStatus(http.StatusNoContent)
+	}
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: "invalid atom feed: " + err.Error()})
+	}
+
+	// A channel is synced by one job, schedule or notification at a time.
+	// The hub delivers the notification again later if it is busy.
+	if _, holder, ok := h.jobs.claim(pushSyncHolder, []string{channelID}); !ok {
+		log.Printf("%s %s notification deferred: already being synced%s", websubLogPrefix, channelID, syncHolderSuffix(holder))
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(websubBusyRetryAfter.Seconds())))
+		return c.Status(http.StatusServiceUnavailable).JSON(model.Error{
+			Error: fmt.Sprintf("channel %s is already being synced%s", channelID, syncHolderSuffix(holder)),
+		})
+	}
+	defer h.jobs.release([]string{channelID})
+
+	// A failure is returned to the hub, which delivers the notification
+	// again later.
+	stats := &videoSyncStats{}
+	_, syncErr := h.recordSyncRun(ctx, model.SyncTriggerPush, channelID, syncPush, stats, func(ctx context.Context) *syncError {
+		return h.ingestFeed(ctx, channelID, feed, stats)
+	})
+	if syncErr != nil {
+		log.Printf("%s %s notification failed: %s", websubLogPrefix, channelID, syncErr.message)
+		return sendSyncError(c, syncErr)
+	}
+	log.Printf("%s %s notified - Added: %d, Updated: %d, Skipped: %d, Errors: %d",
+		websubLogPrefix, channelID, stats.added, stats.updated, stats.skipped, stats.errors)
+
+	now := time.Now().UTC()
+	subscription.LastNotifiedAt = &now
+	if err := h.repo.SaveWebSubSubscription(ctx, *subscription); err != nil {
+		log.Printf("%s failed to record notification for %s: %v", websubLogPrefix, channelID, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// awaitingVerification reports whether a subscribe request for the
+// subscription is outstanding: it hasn't been verified yet, or a renewal was
+// asked for within the hour. Anyone can call the callback, so verifications
+// nobody asked for are refused.
+func awaitingVerification(subscription *repoModel.WebsubSubscriptions, now time.Time) bool {
+	if subscription == nil {
+		return false
+	}
+	return subscription.Status == model.PushStatusPending || subscription.RequestedAt.After(now.Add(-websubRetryAfter))
+}
+
+// validHubSignature checks the X-Hub-Signature of a notification, an HMAC
+// of the body keyed with the secret, e.g. "sha1=<hex>".
+func validHubSignature(header string, body []byte, secret string) bool {
+	method, signature, _ := strings.Cut(header, "=")
+
+	var newHash func() hash.Hash
+	switch method {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha384":
+		newHash = sha512.New384
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return false
+	}
+
+	want, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), want)
+}
+
+// atomFeed is a notification from YouTube's hub: an entry for each video
+// that was published or updated, and a tombstone for each deleted one.
+type atomFeed struct {
+	Entries []struct {
+		VideoID   string `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+		ChannelID string `xml:"http://www.youtube.com/xml/schemas/2015 channelId"`
+	} `xml:"http://www.w3.org/2005/Atom entry"`
+	Deleted []struct {
+		Ref string `xml:"ref,attr"` // yt:video:<id>
+	} `xml:"http://purl.org/atompub/tombstones/1.0 deleted-entry"`
+}
+
+// ingestFeed stores the videos of a notification as a sync would. Anyone
+// can post to the callback, so the videos are looked up rather than taken
+// from the feed: only the channel's own videos are stored, and a video is
+// only marked removed if YouTube no longer has it.
+func (h *Handler) ingestFeed(ctx context.Context, channelID string, feed atomFeed, stats *videoSyncStats) *syncError {
+	var ids, deleted []string
+	for _, entry := range feed.Entries {
+		if entry.ChannelID == channelID && entry.VideoID != "" {
+			ids = append(ids, entry.VideoID)
+		}
+	}
+	for _, tombstone := range feed.Deleted {
+		if videoID, ok := strings.CutPrefix(tombstone.Ref, "yt:video:"); ok {
+			deleted = append(deleted, videoID)
+		}
+	}
+	if len(ids)+len(deleted) == 0 {
+		return nil
+	}
+
+	service, err := h.createYouTubeService(ctx)
+	if err != nil {
+		return &syncError{
+			message:    "failed to create youtube service: " + err.Error(),
+			statusCode: http.StatusInternalServerError,
+		}
+	}
+
+	lookup := append(slices.Clone(ids), deleted...)
+	details, syncErr := h.fetchVideoDetails(ctx, service, lookup)
+	if syncErr != nil {
+		return syncErr
+	}
+
+	stored, err := h.repo.GetStoredVideos(ctx, lookup)
+	if err != nil {
+		return databaseSyncError("failed to check existing videos", err)
+	}
+
+	items := make([]*youtube.PlaylistItem, 0, len(ids))
+	for _, videoID := range ids {
+		video := details[videoID]
+		if video == nil || video.Snippet == nil || video.Snippet.ChannelId != channelID {
+			// Not public yet, or not the channel's.
+			stats.totalFetched++
+			stats.skipped++
+			continue
+		}
+		items = append(items, pushedPlaylistItem(video))
+	}
+	h.storeVideoItems(ctx, channelID, items, stored, details, stats)
+
+	for _, videoID := range deleted {
+		stats.totalFetched++
+
+		video, ok := stored[videoID]
+		if !ok || details[videoID] != nil || video.ChannelID == nil || *video.ChannelID != channelID {
+			stats.skipped++
+			continue
+		}
+
+		changed, err := h.reconcileVideo(ctx, video, model.VideoStatusRemoved, nil)
+		switch {
+		case err != nil:
+			log.Printf("%s failed to mark video %s removed: %v", websubLogPrefix, videoID, err)
+			stats.fail(videoID, err)
+		case changed:
+			stats.updated++
+		default:
+			stats.skipped++
+		}
+	}
+
+	return nil
+}
+
+// pushedPlaylistItem dresses a pushed video up as the item the uploads
+// playlist lists it as, so it is stored the same way a sync stores it.
+func pushedPlaylistItem(video *youtube.Video) *youtube.PlaylistItem {
+	return &youtube.PlaylistItem{
+		Snippet: &youtube.PlaylistItemSnippet{
+			Title:       video.Snippet.Title,
+			Description: video.Snippet.Description,
+			PublishedAt: video.Snippet.PublishedAt,
+			Thumbnails:  video.Snippet.Thumbnails,
+			ResourceId:  &youtube.ResourceId{Kind: "youtube#video", VideoId: video.Id},
+		},
+		Status: &youtube.PlaylistItemStatus{PrivacyStatus: "public"},
+	}
+}
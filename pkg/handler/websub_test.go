@@ -0,0 +1,358 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/handler"
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/websubtest"
+	"github.com/K0ng2/zeedzad/youtubetest"
+)
+
+const (
+	testCallbackURL = "http://zeedzad.test/api/websub/callback"
+	testSecret      = "s3cret"
+)
+
+var testTopic = websubtest.ChannelTopic(testChannelID)
+
+// appTransport delivers the hub's requests to the app, which isn't
+// listening.
+type appTransport struct {
+	app *testApp
+}
+
+func (t appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.app.Test(req, fiber.TestConfig{Timeout: 5 * time.Second})
+}
+
+// startWebSub subscribes the app's channels with a fake hub.
+func (a *testApp) startWebSub(t *testing.T, hub *websubtest.Hub) {
+	t.Helper()
+
+	hub.Subscribers = &http.Client{Transport: appTransport{a}}
+
+	err := a.handler.StartWebSub(context.Background(), handler.WebSubConfig{
+		HubURL:      hub.SubscribeURL(),
+		CallbackURL: testCallbackURL,
+		Secret:      testSecret,
+	})
+	if err != nil {
+		t.Fatalf("StartWebSub: %v", err)
+	}
+	t.Cleanup(a.handler.StopWebSub)
+}
+
+// notify posts a notification to the app's callback as the hub would.
+func (a *testApp) notify(t *testing.T, channelID string, feed []byte, signature string) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/websub/callback/"+channelID, bytes.NewReader(feed))
+	req.Header.Set("Content-Type", "application/atom+xml")
+	req.Header.Set("X-Hub-Signature", signature)
+
+	resp, err := a.Test(req, fiber.TestConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
+
+func TestWebSubSubscribesChannels(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(otherChannel)
+	hub := websubtest.NewHub(t)
+	app.startWebSub(t, hub)
+
+	subscriptions := hub.Subscriptions()
+	if len(subscriptions) != 1 {
+		t.Fatalf("got %d subscriptions, want 1", len(subscriptions))
+	}
+	if s := subscriptions[0]; s.Topic != testTopic || s.Callback != testCallbackURL+"/"+testChannelID || s.Secret != testSecret {
+		t.Fatalf("subscription = %+v", s)
+	}
+
+	var body model.APIResponse[model.ChannelResponse]
+	app.do(t, http.MethodGet, "/api/channels/"+testChannelID, "", &body)
+	if s := body.Data.PushStatus; s == nil || *s != model.PushStatusActive {
+		t.Fatalf("push_status = %v, want active", s)
+	}
+	if e := body.Data.PushExpiresAt; e == nil || e.Sub(time.Now()) < 9*24*time.Hour {
+		t.Fatalf("push_expires_at = %v, want the 10 day lease", e)
+	}
+
+	// New channels are subscribed as they are registered...
+	app.do(t, http.MethodPost, "/api/channels", fmt.Sprintf(`{"channel":%q}`, otherChannel.ID), nil)
+	if n := len(hub.Subscriptions()); n != 2 {
+		t.Fatalf("got %d subscriptions, want 2", n)
+	}
+
+	// ...and unsubscribed when their sync is disabled or they are removed.
+	app.do(t, http.MethodPut, "/api/channels/"+testChannelID, `{"sync_enabled":false}`, &body)
+	if body.Data.PushStatus != nil {
+		t.Fatalf("push_status = %v, want nil once disabled", *body.Data.PushStatus)
+	}
+	app.do(t, http.MethodDelete, "/api/channels/"+otherChannel.ID, "", nil)
+	if n := len(hub.Subscriptions()); n != 0 {
+		t.Fatalf("got %d subscriptions, want 0", n)
+	}
+}
+
+func TestWebSubRenewsLeases(t *testing.T) {
+	app := newTestApp(t)
+	hub := websubtest.NewHub(t)
+	hub.MaxLeaseSeconds = 3600
+	app.startWebSub(t, hub)
+
+	if n := hub.Requests(); n != 1 {
+		t.Fatalf("hub got %d requests, want 1", n)
+	}
+
+	// A lease of an hour is due for renewal straight away.
+	app.handler.StopWebSub()
+	app.startWebSub(t, hub)
+	if n := hub.Requests(); n != 2 {
+		t.Fatalf("hub got %d requests, want the renewal", n)
+	}
+
+	// A long lease isn't.
+	hub.MaxLeaseSeconds = 0
+	app.handler.StopWebSub()
+	app.startWebSub(t, hub)
+	app.handler.StopWebSub()
+	app.startWebSub(t, hub)
+	if n := hub.Requests(); n != 3 {
+		t.Fatalf("hub got %d requests, want 3", n)
+	}
+}
+
+func TestWebSubVerification(t *testing.T) {
+	app := newTestApp(t)
+	hub := websubtest.NewHub(t)
+	app.startWebSub(t, hub)
+
+	verify := func(channelID, query string) *http.Response {
+		return app.do(t, http.MethodGet, "/api/websub/callback/"+channelID+"?hub.challenge=abc&"+query, "", nil)
+	}
+
+	tests := []struct {
+		name      string
+		channelID string
+		query     string
+		status    int
+	}{
+		{"other topic", testChannelID, "hub.mode=subscribe&hub.topic=" + websubtest.ChannelTopic(otherChannel.ID), http.StatusNotFound},
+		{"not subscribed", otherChannel.ID, "hub.mode=subscribe&hub.topic=" + websubtest.ChannelTopic(otherChannel.ID), http.StatusNotFound},
+		{"unwanted unsubscribe", testChannelID, "hub.mode=unsubscribe&hub.topic=" + testTopic, http.StatusNotFound},
+		{"unknown mode", testChannelID, "hub.mode=publish&hub.topic=" + testTopic, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := verify(tt.channelID, tt.query); resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+
+	if err := hub.Deny(testCallbackURL+"/"+testChannelID, testTopic, "over quota"); err != nil {
+		t.Fatalf("Deny: %v", err)
+	}
+
+	var body model.APIResponse[model.ChannelResponse]
+	app.do(t, http.MethodGet, "/api/channels/"+testChannelID, "", &body)
+	if s := body.Data.PushStatus; s == nil || *s != model.PushStatusDenied {
+		t.Fatalf("push_status = %v, want denied", s)
+	}
+}
+
+func TestWebSubNotificationStoresVideo(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.AddChannel(otherChannel)
+	hub := websubtest.NewHub(t)
+	app.startWebSub(t, hub)
+
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	app.youtube.Upload(testChannelID, youtubetest.Video{ID: "pushed", Title: "Elden Ring EP.1", PublishedAt: published, Views: 10})
+
+	entry := websubtest.Entry{VideoID: "pushed", ChannelID: testChannelID, Title: "Elden Ring EP.1", PublishedAt: published}
+	if err := hub.Publish(testTopic, websubtest.Feed(entry)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	var body model.APIResponse[model.VideoResponse]
+	resp := app.do(t, http.MethodGet, "/api/videos/pushed", "", &body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("pushed video: status = %d, want 200", resp.StatusCode)
+	}
+	if v := body.Data; v.Title != "Elden Ring EP.1" || v.ViewCount == nil || *v.ViewCount != 10 || v.Thumbnail == nil {
+		t.Fatalf("pushed video = %+v, want it stored with its details", v)
+	}
+	if c := body.Data.ChannelID; c == nil || *c != testChannelID {
+		t.Fatalf("channel_id = %v, want %s", c, testChannelID)
+	}
+	runs := app.syncRuns(t, "?trigger=push")
+	if runs.Meta.Total != 1 || runs.Data[0].Mode != "push" || runs.Data[0].Added != 1 || runs.Data[0].QuotaUnits != 1 {
+		t.Fatalf("push runs = %+v, want the notification recorded", runs)
+	}
+
+	// An update renames the stored video.
+	app.youtube.Update(youtubetest.Video{ID: "pushed", Title: "Elden Ring EP.1 (Thai)", PublishedAt: published})
+	entry.Title = "Elden Ring EP.1 (Thai)"
+	if err := hub.Publish(testTopic, websubtest.Feed(entry)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	app.do(t, http.MethodGet, "/api/videos/pushed", "", &body)
+	if body.Data.Title != "Elden Ring EP.1 (Thai)" {
+		t.Fatalf("title = %q, want the new title", body.Data.Title)
+	}
+
+	// A deleted video is removed once YouTube no longer has it.
+	if err := hub.Publish(testTopic, websubtest.DeletedFeed(testChannelID, "pushed")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	app.do(t, http.MethodGet, "/api/videos/pushed", "", &body)
+	if body.Data.Status != model.VideoStatusAvailable {
+		t.Fatalf("status = %q, want available while YouTube still has it", body.Data.Status)
+	}
+	app.youtube.Remove("pushed")
+	if err := hub.Publish(testTopic, websubtest.DeletedFeed(testChannelID, "pushed")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	app.do(t, http.MethodGet, "/api/videos/pushed", "", &body)
+	if body.Data.Status != model.VideoStatusRemoved {
+		t.Fatalf("status = %q, want removed", body.Data.Status)
+	}
+
+	var channel model.APIResponse[model.ChannelResponse]
+	app.do(t, http.MethodGet, "/api/channels/"+testChannelID, "", &channel)
+	if channel.Data.LastSyncedAt != nil {
+		t.Fatalf("last_synced_at = %v, want notifications to leave the sync alone", channel.Data.LastSyncedAt)
+	}
+}
+
+func TestWebSubNotificationWaitsForRunningSync(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	hub := websubtest.NewHub(t)
+	app.startWebSub(t, hub)
+
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	app.youtube.Upload(testChannelID, youtubetest.Video{ID: "pushed", Title: "Elden Ring EP.1", PublishedAt: published})
+	feed := websubtest.Feed(websubtest.Entry{VideoID: "pushed", ChannelID: testChannelID, Title: "Elden Ring EP.1", PublishedAt: published})
+
+	release := app.youtube.Hold("playlistItems")
+	defer release()
+
+	job := app.startSyncJob(t, "/api/channels/"+testChannelID+"/sync?api_key=key")
+	app.waitForRequest(t, "playlistItems")
+
+	// The hub is asked to deliver the notification again later.
+	resp := app.notify(t, testChannelID, feed, websubtest.Sign(testSecret, feed))
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Fatalf("notification during a sync: status = %d, Retry-After = %q; want 503 with a Retry-After", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	if runs := app.syncRuns(t, "?trigger=push"); runs.Meta.Total != 0 {
+		t.Fatalf("push runs = %+v, want none while the job syncs", runs)
+	}
+
+	release()
+	if job = app.waitForJob(t, job.ID); job.Status != model.SyncRunSucceeded {
+		t.Fatalf("job = %+v, want it to succeed", job)
+	}
+
+	if resp := app.notify(t, testChannelID, feed, websubtest.Sign(testSecret, feed)); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("notification delivered again: status = %d, want 204", resp.StatusCode)
+	}
+	if runs := app.syncRuns(t, "?trigger=push"); runs.Meta.Total != 1 {
+		t.Fatalf("push runs = %+v, want the notification recorded", runs)
+	}
+}
+
+func TestWebSubIgnoresUntrustedNotifications(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.AddChannel(otherChannel)
+	hub := websubtest.NewHub(t)
+	app.startWebSub(t, hub)
+
+	app.youtube.Upload(testChannelID, youtubetest.Video{ID: "mine", Title: "Mine", PublishedAt: time.Now()})
+	app.youtube.Upload(otherChannel.ID, youtubetest.Video{ID: "theirs", Title: "Theirs", PublishedAt: time.Now()})
+
+	feed := websubtest.Feed(websubtest.Entry{VideoID: "mine", ChannelID: testChannelID, Title: "Mine", PublishedAt: time.Now()})
+	if resp := app.notify(t, testChannelID, feed, websubtest.Sign("wrong", feed)); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("bad signature: status = %d, want 204", resp.StatusCode)
+	}
+	if resp := app.notify(t, testChannelID, feed, ""); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("no signature: status = %d, want 204", resp.StatusCode)
+	}
+
+	// Another channel's video, claimed to be this channel's.
+	forged := websubtest.Feed(websubtest.Entry{VideoID: "theirs", ChannelID: testChannelID, Title: "Theirs", PublishedAt: time.Now()})
+	if resp := app.notify(t, testChannelID, forged, websubtest.Sign(testSecret, forged)); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("forged entry: status = %d, want 204", resp.StatusCode)
+	}
+
+	var videos model.APIResponse[[]model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos", "", &videos)
+	if videos.Meta.Total != 0 {
+		t.Fatalf("videos = %+v, want none stored", videos.Data)
+	}
+
+	garbage := []byte("<feed")
+	if resp := app.notify(t, testChannelID, garbage, websubtest.Sign(testSecret, garbage)); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid feed: status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestWebSubRefusesUnrequestedVerifications(t *testing.T) {
+	app := newTestApp(t)
+	hub := websubtest.NewHub(t)
+	app.startWebSub(t, hub)
+
+	verify := func(lease int) *http.Response {
+		return app.do(t, http.MethodGet, fmt.Sprintf("/api/websub/callback/%s?hub.mode=subscribe&hub.challenge=abc&hub.topic=%s&hub.lease_seconds=%d",
+			testChannelID, testTopic, lease), "", nil)
+	}
+
+	// While the request is outstanding, a longer lease than was asked for
+	// is cut down to the 10 days.
+	if resp := verify(999999999); resp.StatusCode != http.StatusOK {
+		t.Fatalf("outstanding request: status = %d, want 200", resp.StatusCode)
+	}
+	var body model.APIResponse[model.ChannelResponse]
+	app.do(t, http.MethodGet, "/api/channels/"+testChannelID, "", &body)
+	if e := body.Data.PushExpiresAt; e == nil || e.Sub(time.Now()) > 10*24*time.Hour {
+		t.Fatalf("push_expires_at = %v, want at most 10 days away", e)
+	}
+
+	// Once it has been answered, nobody can verify it again.
+	app.seed(t, `UPDATE websub_subscriptions SET requested_at = datetime('now', '-2 hours')`)
+	if resp := verify(3600); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unrequested verification: status = %d, want 404", resp.StatusCode)
+	}
+	app.do(t, http.MethodGet, "/api/channels/"+testChannelID, "", &body)
+	if e := body.Data.PushExpiresAt; e == nil || e.Sub(time.Now()) < 9*24*time.Hour {
+		t.Fatalf("push_expires_at = %v, want the lease left alone", e)
+	}
+}
+
+func TestWebSubRequiresSecret(t *testing.T) {
+	app := newTestApp(t)
+
+	err := app.handler.StartWebSub(context.Background(), handler.WebSubConfig{CallbackURL: testCallbackURL})
+	if err == nil {
+		app.handler.StopWebSub()
+		t.Fatal("StartWebSub without a secret succeeded")
+	}
+}
@@ -2,159 +2,546 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
 
 	"github.com/K0ng2/zeedzad/config"
+	"github.com/K0ng2/zeedzad/db"
 	"github.com/K0ng2/zeedzad/model"
 	repoModel "github.com/K0ng2/zeedzad/repository/model"
 )
 
 const (
-	opztvChannelID     = "UCsGx1qSnAS2P1YCJPYnYVUg"
 	defaultMaxResults  = 50
 	youtubeMaxPageSize = 50
-	syncLogPrefix      = "[YouTube Sync]"
+	// videos.list takes at most 50 IDs per call.
+	videoDetailsBatchSize = 50
+	syncLogPrefix         = "[YouTube Sync]"
+	// recentReconcileWindow is how many stored videos past the checkpoint
+	// an incremental sync reconciles.
+	recentReconcileWindow = youtubeMaxPageSize
+)
+
+// Sync modes. An incremental sync reads the uploads playlist from the top
+// until it reaches the last checkpoint; a backfill pages through the whole
+// playlist, saving its place after every page. A push sync stores the
+// videos of a WebSub notification, and can't be asked for.
+const (
+	syncIncremental = "incremental"
+	syncBackfill    = "backfill"
+	syncPush        = "push"
 )
 
 type videoSyncStats struct {
 	added        int
+	updated      int
 	skipped      int
 	errors       int
 	totalFetched int
+
+	// deferred is set when the sync stopped because the day's quota budget
+	// ran out.
+	deferred bool
+	// complete is set when an incremental sync reached the checkpoint or a
+	// backfill reached the end of the playlist.
+	complete bool
+
+	// failures are the videos counted in errors, with what went wrong.
+	failures []model.SyncRunError
+	// onProgress, if set, is called with the counts so far after each page
+	// of videos is stored.
+	onProgress func(*videoSyncStats)
+}
+
+// fail counts a video the sync failed to store or update.
+func (s *videoSyncStats) fail(videoID string, err error) {
+	s.errors++
+	s.failures = append(s.failures, model.SyncRunError{VideoID: videoID, Message: err.Error()})
 }
 
 // SyncYouTubeVideos godoc
-// @Summary Sync videos from YouTube channel
-// @Description Fetch and sync videos from OPZTV YouTube channel
+// @Summary Sync videos from YouTube channels
+// @Description Start a background job syncing videos from one registered channel, or from every channel with sync enabled, and return it. Poll the job, or stream its progress, until it is done. An incremental sync (the default) stops at the newest video of the previous sync. A backfill pages through the whole uploads playlist; if it is interrupted or stopped by max_results, the next backfill resumes where it left off. A sync is refused if one of its channels is already being synced, or if the first can't be synced within what is left of today's YouTube quota: a backfill projected to spend more than that is refused. A sync that runs out of quota part-way stops and reports deferred.
 // @Tags videos
 // @Accept  json
 // @Produce  json
 // @Param api_key query string true "YouTube API Key"
-// @Param max_results query int false "Maximum results to fetch" default(50)
-// @Success 200 {object} model.APIResponse[model.SyncResult]
+// @Param channel_id query string false "Channel to sync; every enabled channel if omitted"
+// @Param mode query string false "Sync mode" Enums(incremental, backfill) default(incremental)
+// @Param max_results query int false "Maximum results to fetch per channel; backfills stop at the end of a page and default to no limit" default(50)
+// @Success 202 {object} model.APIResponse[model.SyncJob]
+// @Header 202 {string} Location "The job's URL"
 // @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 429 {object} model.Error
 // @Failure 500 {object} model.Error
 // @Router /videos/sync [post]
 func (h *Handler) SyncYouTubeVideos(c fiber.Ctx) error {
-	maxResults := fiber.Query(c, "max_results", defaultMaxResults)
+	ctx := c.RequestCtx()
 
-	stats, err := h.syncVideosFromChannel(c.RequestCtx(), maxResults)
+	mode, maxResults, err := getSyncParams(c)
 	if err != nil {
-		return c.Status(err.statusCode).JSON(model.Error{Error: err.message})
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
 	}
 
-	result := model.SyncResult{
-		Added:   stats.added,
-		Skipped: stats.skipped,
-		Errors:  stats.errors,
-		Total:   stats.totalFetched,
+	channelIDs := []string{}
+	if channelID := c.Query("channel_id"); channelID != "" {
+		if _, err := h.repo.GetChannelByID(ctx, channelID); err != nil {
+			return sendError(c, err)
+		}
+		channelIDs = append(channelIDs, channelID)
+	} else {
+		channels, err := h.repo.GetChannels(ctx)
+		if err != nil {
+			return sendError(c, err)
+		}
+		for _, ch := range channels {
+			if ch.SyncEnabled {
+				channelIDs = append(channelIDs, ch.ID)
+			}
+		}
 	}
 
-	return c.JSON(Response(result, nil))
+	return h.startSyncJob(c, channelIDs, mode, maxResults)
+}
+
+// SyncChannel godoc
+// @Summary Sync videos from a channel
+// @Description Start a background job syncing videos from one registered channel, whether or not its scheduled sync is enabled, and return it
+// @Tags channels
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Channel ID"
+// @Param mode query string false "Sync mode" Enums(incremental, backfill) default(incremental)
+// @Param max_results query int false "Maximum results to fetch; backfills stop at the end of a page and default to no limit" default(50)
+// @Success 202 {object} model.APIResponse[model.SyncJob]
+// @Header 202 {string} Location "The job's URL"
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 429 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /channels/{id}/sync [post]
+func (h *Handler) SyncChannel(c fiber.Ctx) error {
+	mode, maxResults, err := getSyncParams(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(model.Error{Error: err.Error()})
+	}
+
+	channelID := c.Params("id")
+	if _, err := h.repo.GetChannelByID(c.RequestCtx(), channelID); err != nil {
+		return sendError(c, err)
+	}
+
+	return h.startSyncJob(c, []string{channelID}, mode, maxResults)
+}
+
+// getSyncParams extracts the mode and max_results query parameters.
+func getSyncParams(c fiber.Ctx) (mode string, maxResults int, err error) {
+	mode = c.Query("mode", syncIncremental)
+
+	switch mode {
+	case syncIncremental:
+		maxResults = fiber.Query(c, "max_results", defaultMaxResults)
+	case syncBackfill:
+		maxResults = fiber.Query(c, "max_results", 0)
+	default:
+		return "", 0, fmt.Errorf("unknown sync mode %q", mode)
+	}
+
+	return mode, maxResults, nil
+}
+
+func (s *videoSyncStats) add(other *videoSyncStats) {
+	s.added += other.added
+	s.updated += other.updated
+	s.skipped += other.skipped
+	s.errors += other.errors
+	s.totalFetched += other.totalFetched
+	s.failures = append(s.failures, other.failures...)
+	s.deferred = s.deferred || other.deferred
+	s.complete = s.complete && other.complete
+}
+
+func (s *videoSyncStats) result(mode string) model.SyncResult {
+	return model.SyncResult{
+		Mode:     mode,
+		Added:    s.added,
+		Updated:  s.updated,
+		Skipped:  s.skipped,
+		Errors:   s.errors,
+		Total:    s.totalFetched,
+		Complete: s.complete,
+		Deferred: s.deferred,
+	}
 }
 
-func (h *Handler) SyncYouTubeVideosScheduled() {
-	stats, err := h.syncVideosFromChannel(context.Background(), defaultMaxResults)
+// SyncChannelScheduled runs the scheduled incremental sync of a channel,
+// unless the channel is already being synced. The run is recorded in the
+// sync history; the log only summarizes it.
+func (h *Handler) SyncChannelScheduled(channelID string) {
+	if _, holder, ok := h.jobs.claim("", []string{channelID}); !ok {
+		fmt.Printf("%s %s skipped: already being synced%s\n", syncLogPrefix, channelID, syncHolderSuffix(holder))
+		return
+	}
+	defer h.jobs.release([]string{channelID})
+
+	stats := &videoSyncStats{}
+	runID, err := h.runSync(context.Background(), model.SyncTriggerSchedule, channelID, syncIncremental, defaultMaxResults, stats)
+	if isQuotaExhausted(err) {
+		fmt.Printf("%s %s deferred (run %d): %s\n", syncLogPrefix, channelID, runID, err.message)
+		return
+	}
 	if err != nil {
-		fmt.Printf("%s failed: %s\n", syncLogPrefix, err.message)
+		fmt.Printf("%s %s failed (run %d): %s\n", syncLogPrefix, channelID, runID, err.message)
 		return
 	}
 
-	fmt.Printf("%s completed - Added: %d, Skipped: %d, Errors: %d, Total: %d\n",
-		syncLogPrefix, stats.added, stats.skipped, stats.errors, stats.totalFetched)
+	fmt.Printf("%s %s completed (run %d) - Added: %d, Skipped: %d, Errors: %d, Total: %d\n",
+		syncLogPrefix, channelID, runID, stats.added, stats.skipped, stats.errors, stats.totalFetched)
+	if stats.deferred {
+		fmt.Printf("%s %s stopped after %d videos when the quota budget ran out; the next sync continues\n",
+			syncLogPrefix, channelID, stats.totalFetched)
+	} else if !stats.complete {
+		fmt.Printf("%s %s stopped after %d videos without reaching the last synced video; run a backfill to fill the gap\n",
+			syncLogPrefix, channelID, stats.totalFetched)
+	}
 }
 
 type syncError struct {
 	message    string
 	statusCode int
+	cause      error
+	// retryAfter is how long the client should wait before trying again.
+	retryAfter time.Duration
 }
 
 func (e *syncError) Error() string {
 	return e.message
 }
 
-func (h *Handler) syncVideosFromChannel(ctx context.Context, maxResults int) (*videoSyncStats, *syncError) {
-	service, err := h.createYouTubeService()
+func databaseSyncError(message string, err error) *syncError {
+	return &syncError{
+		message:    message + ": " + err.Error(),
+		statusCode: errorStatus(err),
+	}
+}
+
+// runSync syncs a registered channel, counting what it does in stats, and
+// records the run in the sync history. A channel that isn't registered has
+// nothing to record.
+func (h *Handler) runSync(ctx context.Context, trigger, channelID, mode string, maxResults int, stats *videoSyncStats) (int64, *syncError) {
+	channel, err := h.repo.GetChannelByID(ctx, channelID)
+	if errors.Is(err, db.ErrNotFound) {
+		return 0, &syncError{
+			message:    "channel not found",
+			statusCode: http.StatusNotFound,
+		}
+	}
+	if err != nil {
+		return 0, databaseSyncError("failed to load channel", err)
+	}
+
+	return h.recordSyncRun(ctx, trigger, channelID, mode, stats, func(ctx context.Context) *syncError {
+		return h.syncVideosFromChannel(ctx, channel, mode, maxResults, stats)
+	})
+}
+
+// syncVideosFromChannel syncs a channel, counting what it does in stats as
+// it goes, so a sync that fails part-way still accounts for its videos.
+func (h *Handler) syncVideosFromChannel(ctx context.Context, channel *model.ChannelResponse, mode string, maxResults int, stats *videoSyncStats) *syncError {
+	service, err := h.createYouTubeService(ctx)
 	if err != nil {
-		return nil, &syncError{
+		return &syncError{
 			message:    "failed to create youtube service: " + err.Error(),
 			statusCode: http.StatusInternalServerError,
 		}
 	}
 
-	uploadsPlaylistID, syncErr := h.getUploadsPlaylistID(service, opztvChannelID)
+	state, syncErr := h.loadSyncState(ctx, channel.ID)
 	if syncErr != nil {
-		return nil, syncErr
+		return syncErr
 	}
+	state.UploadsPlaylistID = &channel.UploadsPlaylistID
 
-	stats := &videoSyncStats{}
-	if syncErr := h.fetchAndStoreVideos(ctx, service, uploadsPlaylistID, maxResults, stats); syncErr != nil {
-		return nil, syncErr
+	if syncErr := h.checkSyncQuota(ctx, state, mode, maxResults); syncErr != nil {
+		return syncErr
+	}
+
+	if mode == syncBackfill {
+		syncErr = h.backfillVideos(ctx, service, state, maxResults, stats)
+	} else {
+		syncErr = h.fetchNewVideos(ctx, service, state, maxResults, stats)
+	}
+	if syncErr != nil {
+		return syncErr
+	}
+
+	now := time.Now()
+	state.LastSyncedAt = &now
+	if err := h.repo.SaveSyncState(ctx, *state); err != nil {
+		return databaseSyncError("failed to save sync state", err)
 	}
 
-	return stats, nil
+	return nil
 }
 
-func (h *Handler) createYouTubeService() (*youtube.Service, error) {
-	return youtube.NewService(context.Background(), option.WithAPIKey(config.YOUTUBE_API_KEY))
+// checkSyncQuota refuses a sync that can't read a page, or a backfill that
+// would run out of quota part-way.
+func (h *Handler) checkSyncQuota(ctx context.Context, state *repoModel.SyncState, mode string, maxResults int) *syncError {
+	if mode == syncBackfill {
+		return h.checkBackfillBudget(ctx, state, maxResults)
+	}
+
+	ok, syncErr := h.haveQuota(ctx, syncPageQuotaCost)
+	if syncErr != nil {
+		return syncErr
+	}
+	if !ok {
+		return quotaSyncError(time.Now())
+	}
+	return nil
 }
 
-func (h *Handler) getUploadsPlaylistID(service *youtube.Service, channelID string) (string, *syncError) {
-	channelCall := service.Channels.List([]string{"contentDetails"}).Id(channelID)
-	channelResponse, err := channelCall.Do()
+// loadSyncState returns the saved sync progress for a channel, or a fresh
+// state if it has never been synced.
+func (h *Handler) loadSyncState(ctx context.Context, channelID string) (*repoModel.SyncState, *syncError) {
+	state, err := h.repo.GetSyncState(ctx, channelID)
+	if errors.Is(err, db.ErrNotFound) {
+		return &repoModel.SyncState{ChannelID: &channelID}, nil
+	}
 	if err != nil {
-		return "", &syncError{
-			message:    "failed to fetch channel: " + err.Error(),
-			statusCode: http.StatusInternalServerError,
-		}
+		return nil, databaseSyncError("failed to load sync state", err)
 	}
 
-	if len(channelResponse.Items) == 0 {
-		return "", &syncError{
-			message:    "channel not found",
-			statusCode: http.StatusNotFound,
-		}
-	}
+	return state, nil
+}
 
-	return channelResponse.Items[0].ContentDetails.RelatedPlaylists.Uploads, nil
+func (h *Handler) createYouTubeService(ctx context.Context) (*youtube.Service, error) {
+	opts := append([]option.ClientOption{option.WithAPIKey(config.YOUTUBE_API_KEY)}, h.youtubeOptions...)
+	return youtube.NewService(ctx, opts...)
 }
 
-func (h *Handler) fetchAndStoreVideos(ctx context.Context, service *youtube.Service, playlistID string, maxResults int, stats *videoSyncStats) *syncError {
+// fetchNewVideos reads the uploads playlist, newest first, until it reaches
+// the checkpoint or maxResults. The checkpoint moves to the newest video only
+// if every video before it was stored: otherwise the next sync would stop
+// short of the videos that were missed.
+func (h *Handler) fetchNewVideos(ctx context.Context, service *youtube.Service, state *repoModel.SyncState, maxResults int, stats *videoSyncStats) *syncError {
+	if maxResults <= 0 {
+		maxResults = defaultMaxResults
+	}
+
+	var newest *youtube.PlaylistItem
 	pageToken := ""
 
 	for stats.totalFetched < maxResults {
-		items, nextToken, syncErr := h.fetchPlaylistPage(service, playlistID, pageToken)
+		items, nextToken, syncErr := h.fetchPlaylistPage(ctx, service, state, pageToken)
+		if isQuotaExhausted(syncErr) {
+			stats.deferred = true
+			break
+		}
 		if syncErr != nil {
 			return syncErr
 		}
 
-		if len(items) == 0 {
-			break
+		reached := nextToken == ""
+		var past []*youtube.PlaylistItem
+		for i, item := range items {
+			if reachedCheckpoint(state, item) {
+				items, past = items[:i], items[i:]
+				reached = true
+				break
+			}
+		}
+
+		if remaining := maxResults - stats.totalFetched; len(items) > remaining {
+			items = items[:remaining]
+			reached, past = false, nil
 		}
 
-		if syncErr := h.processVideoItems(ctx, items, maxResults, stats); syncErr != nil {
+		if syncErr := h.processVideoItems(ctx, service, state, items, stats); isQuotaExhausted(syncErr) {
+			stats.deferred = true
+			break
+		} else if syncErr != nil {
 			return syncErr
 		}
 
-		if nextToken == "" {
+		for _, item := range items {
+			if newest == nil || h.parsePublishedDate(item.Snippet.PublishedAt).After(h.parsePublishedDate(newest.Snippet.PublishedAt)) {
+				newest = item
+			}
+		}
+
+		if reached {
+			stats.complete = true
+			if syncErr := h.reconcileRecentVideos(ctx, service, state, past, nextToken, stats); syncErr != nil {
+				return syncErr
+			}
 			break
 		}
 		pageToken = nextToken
 	}
 
+	// The first sync has nothing to reach; older videos are left to a
+	// backfill.
+	firstSync := state.NewestVideoID == nil
+	if newest != nil && stats.errors == 0 && (stats.complete || firstSync) {
+		h.advanceCheckpoint(state, newest)
+	}
+
 	return nil
 }
 
-func (h *Handler) fetchPlaylistPage(service *youtube.Service, playlistID, pageToken string) ([]*youtube.PlaylistItem, string, *syncError) {
-	call := service.PlaylistItems.List([]string{"snippet"}).
-		PlaylistId(playlistID).
-		MaxResults(youtubeMaxPageSize)
+// backfillVideos pages through the whole uploads playlist, resuming from
+// the saved page token if a previous backfill didn't finish. It always stops
+// at the end of a page, so no video is skipped when it resumes.
+func (h *Handler) backfillVideos(ctx context.Context, service *youtube.Service, state *repoModel.SyncState, maxResults int, stats *videoSyncStats) *syncError {
+	pageToken := ""
+	if state.BackfillPageToken != nil {
+		pageToken = *state.BackfillPageToken
+	} else {
+		now := time.Now()
+		state.BackfillStartedAt = &now
+		state.BackfillCompletedAt = nil
+	}
+
+	for {
+		items, nextToken, syncErr := h.fetchPlaylistPage(ctx, service, state, pageToken)
+		if isQuotaExhausted(syncErr) {
+			// The page token is saved; the backfill resumes here.
+			stats.deferred = true
+			return nil
+		}
+		if syncErr != nil && pageToken != "" && isInvalidPageToken(syncErr) {
+			// Page tokens don't last forever; start over from the top.
+			fmt.Printf("%s saved page token expired, restarting backfill\n", syncLogPrefix)
+			pageToken = ""
+			continue
+		}
+		if syncErr != nil {
+			return syncErr
+		}
+
+		errorsBefore := stats.errors
+		if syncErr := h.processVideoItems(ctx, service, state, items, stats); isQuotaExhausted(syncErr) {
+			stats.deferred = true
+			return nil
+		} else if syncErr != nil {
+			return syncErr
+		}
+
+		// Private and deleted videos are still listed, and keep the status
+		// their items give them.
+		if err := h.repo.MarkVideosSeen(ctx, playlistVideoIDs(items), time.Now().UTC()); err != nil {
+			return databaseSyncError("failed to mark videos seen", err)
+		}
+
+		if pageToken == "" && len(items) > 0 && stats.errors == errorsBefore {
+			h.advanceCheckpoint(state, items[0])
+		}
+
+		if nextToken == "" {
+			if syncErr := h.detectRemovedVideos(ctx, service, state, stats); isQuotaExhausted(syncErr) {
+				// The last page is read again when the backfill resumes.
+				stats.deferred = true
+				return nil
+			} else if syncErr != nil {
+				return syncErr
+			}
+
+			now := time.Now()
+			state.BackfillPageToken = nil
+			state.BackfillCompletedAt = &now
+			stats.complete = true
+		} else {
+			state.BackfillPageToken = &nextToken
+		}
+
+		if err := h.repo.SaveSyncState(ctx, *state); err != nil {
+			return databaseSyncError("failed to save sync state", err)
+		}
+
+		if stats.complete || maxResults > 0 && stats.totalFetched >= maxResults {
+			return nil
+		}
+		pageToken = nextToken
+	}
+}
+
+// playlistVideoIDs returns the IDs of the videos of items.
+func playlistVideoIDs(items []*youtube.PlaylistItem) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.Snippet.ResourceId.VideoId
+	}
+	return ids
+}
+
+// reachedCheckpoint reports whether item is the newest video of the last
+// sync, or older than it.
+func reachedCheckpoint(state *repoModel.SyncState, item *youtube.PlaylistItem) bool {
+	if state.NewestVideoID == nil {
+		return false
+	}
+	if item.Snippet.ResourceId.VideoId == *state.NewestVideoID {
+		return true
+	}
+
+	publishedAt, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt)
+	return err == nil && state.NewestPublishedAt != nil && !publishedAt.After(*state.NewestPublishedAt)
+}
+
+// advanceCheckpoint moves the checkpoint to item unless it is older than
+// the current one.
+func (h *Handler) advanceCheckpoint(state *repoModel.SyncState, item *youtube.PlaylistItem) {
+	publishedAt := h.parsePublishedDate(item.Snippet.PublishedAt)
+	if state.NewestPublishedAt != nil && publishedAt.Before(*state.NewestPublishedAt) {
+		return
+	}
+
+	videoID := item.Snippet.ResourceId.VideoId
+	state.NewestVideoID = &videoID
+	state.NewestPublishedAt = &publishedAt
+}
+
+func isInvalidPageToken(err *syncError) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err.cause, &apiErr) && apiErr.Code == http.StatusBadRequest
+}
+
+// fetchPlaylistPage reads a page of a channel's uploads playlist, and notes
+// the size of the playlist in state for projecting backfills.
+func (h *Handler) fetchPlaylistPage(ctx context.Context, service *youtube.Service, state *repoModel.SyncState, pageToken string) ([]*youtube.PlaylistItem, string, *syncError) {
+	// A cancelled sync stops before its next page.
+	if err := ctx.Err(); err != nil {
+		return nil, "", &syncError{
+			message:    "sync cancelled",
+			statusCode: errorStatus(err),
+			cause:      err,
+		}
+	}
+
+	if syncErr := h.spendQuota(ctx, "playlistItems.list"); syncErr != nil {
+		return nil, "", syncErr
+	}
+
+	call := service.PlaylistItems.List([]string{"snippet", "status"}).
+		PlaylistId(*state.UploadsPlaylistID).
+		MaxResults(youtubeMaxPageSize).
+		Context(ctx)
 
 	if pageToken != "" {
 		call = call.PageToken(pageToken)
@@ -165,45 +552,313 @@ func (h *Handler) fetchPlaylistPage(service *youtube.Service, playlistID, pageTo
 		return nil, "", &syncError{
 			message:    "failed to fetch playlist items: " + err.Error(),
 			statusCode: http.StatusInternalServerError,
+			cause:      err,
 		}
 	}
 
+	if response.PageInfo != nil {
+		count := int32(min(response.PageInfo.TotalResults, math.MaxInt32))
+		state.PlaylistItemCount = &count
+	}
+
 	return response.Items, response.NextPageToken, nil
 }
 
-func (h *Handler) processVideoItems(ctx context.Context, items []*youtube.PlaylistItem, maxResults int, stats *videoSyncStats) *syncError {
+// processVideoItems stores the videos that aren't stored yet, checking which
+// exist with one query for the whole page. New videos, and stored ones that
+// were synced before details were kept, are looked up with videos.list.
+func (h *Handler) processVideoItems(ctx context.Context, service *youtube.Service, state *repoModel.SyncState, items []*youtube.PlaylistItem, stats *videoSyncStats) *syncError {
+	ids := playlistVideoIDs(items)
+
+	stored, err := h.repo.GetStoredVideos(ctx, ids)
+	if err != nil {
+		return databaseSyncError("failed to check existing videos", err)
+	}
+
+	lookup := make([]string, 0, len(ids))
 	for _, item := range items {
-		if stats.totalFetched >= maxResults {
-			break
+		videoID := item.Snippet.ResourceId.VideoId
+		if video, ok := stored[videoID]; ok && video.DetailsUpdatedAt != nil {
+			continue
+		}
+		if playlistItemStatus(item) == model.VideoStatusAvailable {
+			lookup = append(lookup, videoID)
 		}
+	}
+	details, syncErr := h.fetchVideoDetails(ctx, service, lookup)
+	if syncErr != nil {
+		return syncErr
+	}
+
+	// A page that was read is stored whole, even if the sync is cancelled
+	// meanwhile.
+	h.storeVideoItems(context.WithoutCancel(ctx), *state.ChannelID, items, stored, details, stats)
+	return nil
+}
+
+// storeVideoItems stores the videos of items in one go, creating the ones
+// that aren't stored yet and reconciling the stored ones, using the details
+// looked up for them. If the page can't be stored at once, its videos are
+// stored one at a time, so that one bad video doesn't fail the others. The
+// videos created are then put in their series and matched with games, and
+// the series they joined give their unmatched episodes the games of the
+// others.
+func (h *Handler) storeVideoItems(ctx context.Context, channelID string, items []*youtube.PlaylistItem, stored map[string]repoModel.Videos, details map[string]*youtube.Video, stats *videoSyncStats) {
+	page := make([]repoModel.Videos, 0, len(items))
+	edits := make(map[string][]repoModel.VideoEdits, len(stored))
+
+	for _, item := range items {
 		stats.totalFetched++
 
-		h.processVideoItem(ctx, item, stats)
+		videoID := item.Snippet.ResourceId.VideoId
+		status := playlistItemStatus(item)
+
+		if video, ok := stored[videoID]; ok {
+			video, edits[videoID] = h.reconciledVideo(video, status, item)
+			if video.DetailsUpdatedAt == nil && details[videoID] != nil {
+				video.Description = toNullableString(item.Snippet.Description)
+				applyVideoDetails(&video, details[videoID])
+			}
+			page = append(page, video)
+			continue
+		}
+
+		if status != model.VideoStatusAvailable {
+			// A video that was private or deleted before it was ever synced
+			// has nothing worth storing.
+			stats.skipped++
+			continue
+		}
+
+		video := h.buildVideoModel(item, videoID)
+		video.ChannelID = &channelID
+		video.Status = status
+		if d := details[videoID]; d != nil {
+			applyVideoDetails(&video, d)
+		}
+		page = append(page, video)
+	}
+
+	var added []repoModel.Videos
+
+	inserted, updated, err := h.repo.UpsertVideos(ctx, page)
+	if err != nil {
+		fmt.Printf("failed to store a page of videos, storing them one at a time: %v\n", err)
+		for _, video := range page {
+			if h.storeVideo(ctx, video, stored, edits[*video.ID], stats) {
+				added = append(added, video)
+			}
+		}
+	} else {
+		h.recordStoredVideos(ctx, page, stored, edits, inserted, updated, stats)
+		for _, video := range page {
+			if slices.Contains(inserted, *video.ID) {
+				added = append(added, video)
+			}
+		}
+	}
+
+	seriesIDs, _ := h.assignSeries(ctx, added)
+	h.matchVideos(ctx, added)
+	h.inferSeriesGames(ctx, seriesIDs)
+
+	if stats.onProgress != nil {
+		stats.onProgress(stats)
 	}
-	return nil
 }
 
-func (h *Handler) processVideoItem(ctx context.Context, item *youtube.PlaylistItem, stats *videoSyncStats) {
-	videoID := item.Snippet.ResourceId.VideoId
+// recordStoredVideos counts the videos of a page UpsertVideos stored, and
+// records the history of those it wrote: the edits of the reconciled ones
+// and the first stats snapshot of those looked up. A stored video that
+// someone else changed first is left with their edits. A failure to record
+// the history is only logged; the videos are stored.
+func (h *Handler) recordStoredVideos(ctx context.Context, page []repoModel.Videos, stored map[string]repoModel.Videos, edits map[string][]repoModel.VideoEdits, inserted, updated []string, stats *videoSyncStats) {
+	var history []repoModel.VideoEdits
+	var snapshots []repoModel.VideoStats
+
+	for _, video := range page {
+		videoID := *video.ID
+		wasInserted := slices.Contains(inserted, videoID)
+
+		switch {
+		case wasInserted:
+			stats.added++
+		case slices.Contains(updated, videoID) && len(edits[videoID]) > 0:
+			stats.updated++
+			history = append(history, edits[videoID]...)
+		default:
+			stats.skipped++
+		}
+
+		lookedUp := wasInserted || (slices.Contains(updated, videoID) && stored[videoID].DetailsUpdatedAt == nil)
+		if lookedUp && video.StatsUpdatedAt != nil {
+			snapshots = append(snapshots, repoModel.VideoStats{
+				VideoID:      videoID,
+				CapturedAt:   *video.StatsUpdatedAt,
+				ViewCount:    video.ViewCount,
+				LikeCount:    video.LikeCount,
+				CommentCount: video.CommentCount,
+			})
+		}
+	}
+
+	if err := h.repo.RecordVideoHistory(ctx, history, snapshots); err != nil {
+		fmt.Printf("failed to record the history of a page of videos: %v\n", err)
+	}
+}
+
+// storeVideo stores a video of a page on its own: it creates a new one, or
+// saves the edits and details of a stored one. It reports whether it
+// created the video.
+func (h *Handler) storeVideo(ctx context.Context, video repoModel.Videos, stored map[string]repoModel.Videos, edits []repoModel.VideoEdits, stats *videoSyncStats) bool {
+	videoID := *video.ID
+
+	previous, ok := stored[videoID]
+	if !ok {
+		if err := h.repo.CreateVideo(ctx, video); err != nil {
+			fmt.Printf("failed to insert video %s: %v\n", videoID, err)
+			stats.fail(videoID, err)
+			return false
+		}
+		stats.added++
+		return true
+	}
 
-	if h.videoExists(ctx, videoID) {
+	err := h.repo.ReconcileVideo(ctx, video, edits)
+	switch {
+	case err != nil:
+		fmt.Printf("failed to update video %s: %v\n", videoID, err)
+		stats.fail(videoID, err)
+	case len(edits) > 0:
+		stats.updated++
+	default:
 		stats.skipped++
-		return
 	}
 
-	video := h.buildVideoModel(item, videoID)
-	if err := h.repo.CreateVideo(ctx, video); err != nil {
-		fmt.Printf("failed to insert video %s: %v\n", videoID, err)
-		stats.errors++
-		return
+	if previous.DetailsUpdatedAt == nil && video.DetailsUpdatedAt != nil {
+		if err := h.repo.UpdateVideoDetails(ctx, video); err != nil {
+			fmt.Printf("failed to update details of video %s: %v\n", videoID, err)
+		}
+	}
+
+	return false
+}
+
+// fetchVideoDetails looks videos up with videos.list for all the details
+// the sync stores.
+func (h *Handler) fetchVideoDetails(ctx context.Context, service *youtube.Service, ids []string) (map[string]*youtube.Video, *syncError) {
+	return h.listVideos(ctx, service, []string{"snippet", "contentDetails", "statistics", "liveStreamingDetails"}, ids)
+}
+
+// listVideos calls videos.list in batches of 50 IDs. Videos that have been
+// deleted or made private are missing from the result.
+func (h *Handler) listVideos(ctx context.Context, service *youtube.Service, parts, ids []string) (map[string]*youtube.Video, *syncError) {
+	details := make(map[string]*youtube.Video, len(ids))
+
+	for batch := range slices.Chunk(ids, videoDetailsBatchSize) {
+		if syncErr := h.spendQuota(ctx, "videos.list"); syncErr != nil {
+			return nil, syncErr
+		}
+
+		response, err := service.Videos.List(parts).
+			Id(batch...).
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, &syncError{
+				message:    "failed to fetch video details: " + err.Error(),
+				statusCode: http.StatusInternalServerError,
+				cause:      err,
+			}
+		}
+
+		for _, video := range response.Items {
+			details[video.Id] = video
+		}
 	}
 
-	stats.added++
+	return details, nil
 }
 
-func (h *Handler) videoExists(ctx context.Context, videoID string) bool {
-	existingVideo, err := h.repo.GetVideoByYouTubeID(ctx, videoID)
-	return err == nil && existingVideo != nil
+// applyVideoDetails copies the details of a videos.list result onto video.
+func applyVideoDetails(video *repoModel.Videos, details *youtube.Video) {
+	now := time.Now().UTC()
+	video.DetailsUpdatedAt = &now
+
+	status := liveStatus(details)
+	video.LiveStatus = &status
+
+	// Upcoming and live streams report a duration of zero.
+	if details.ContentDetails != nil {
+		if seconds, ok := parseISODuration(details.ContentDetails.Duration); ok && seconds > 0 {
+			video.DurationSeconds = &seconds
+		}
+	}
+
+	if stats := details.Statistics; stats != nil {
+		views, likes, comments := int64(stats.ViewCount), int64(stats.LikeCount), int64(stats.CommentCount)
+		video.ViewCount = &views
+		video.LikeCount = &likes
+		video.CommentCount = &comments
+		video.StatsUpdatedAt = &now
+	}
+
+	if snippet := details.Snippet; snippet != nil {
+		if snippet.Description != "" {
+			video.Description = &snippet.Description
+		}
+		if len(snippet.Tags) > 0 {
+			if tags, err := json.Marshal(snippet.Tags); err == nil {
+				video.Tags = toNullableString(string(tags))
+			}
+		}
+		video.CategoryID = toNullableString(snippet.CategoryId)
+		video.DefaultLanguage = toNullableString(snippet.DefaultLanguage)
+		if video.DefaultLanguage == nil {
+			video.DefaultLanguage = toNullableString(snippet.DefaultAudioLanguage)
+		}
+	}
+}
+
+// liveStatus tells regular uploads from live streams. Only streams have
+// liveStreamingDetails; once a stream has ended its liveBroadcastContent goes
+// back to "none".
+func liveStatus(video *youtube.Video) string {
+	if video.Snippet != nil {
+		switch video.Snippet.LiveBroadcastContent {
+		case model.LiveStatusLive, model.LiveStatusUpcoming:
+			return video.Snippet.LiveBroadcastContent
+		}
+	}
+	if video.LiveStreamingDetails != nil {
+		return model.LiveStatusCompleted
+	}
+	return model.LiveStatusNone
+}
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseISODuration converts an ISO 8601 duration such as PT1H2M3S, as
+// videos.list reports it, to seconds.
+func parseISODuration(s string) (int32, bool) {
+	match := isoDurationPattern.FindStringSubmatch(s)
+	if match == nil {
+		return 0, false
+	}
+
+	var seconds int64
+	for i, unit := range []int64{7 * 24 * 3600, 24 * 3600, 3600, 60, 1} {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(match[i+1], 10, 32)
+		if err != nil {
+			return 0, false
+		}
+		seconds += n * unit
+	}
+
+	return int32(min(seconds, int64(math.MaxInt32))), true
 }
 
 func (h *Handler) buildVideoModel(item *youtube.PlaylistItem, videoID string) repoModel.Videos {
@@ -214,6 +869,7 @@ func (h *Handler) buildVideoModel(item *youtube.PlaylistItem, videoID string) re
 	return repoModel.Videos{
 		ID:          toNullableString(videoID),
 		Title:       item.Snippet.Title,
+		Description: toNullableString(item.Snippet.Description),
 		Thumbnail:   toNullableString(thumbnail),
 		PublishedAt: publishedAt,
 		CreatedAt:   now,
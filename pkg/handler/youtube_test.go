@@ -0,0 +1,297 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/model"
+	"github.com/K0ng2/zeedzad/youtubetest"
+)
+
+const (
+	testChannelID = "UCsGx1qSnAS2P1YCJPYnYVUg"
+	testUploadsID = "UUsGx1qSnAS2P1YCJPYnYVUg"
+)
+
+// testChannel is the channel the migrations register.
+var testChannel = youtubetest.Channel{
+	ID:                testChannelID,
+	Handle:            "@OPZTV",
+	Title:             "OPZTV",
+	UploadsPlaylistID: testUploadsID,
+}
+
+// upload adds n videos to the channel, numbered on from the videos already
+// there, each published a day after the last.
+func (a *testApp) upload(first, n int) {
+	for i := first; i < first+n; i++ {
+		a.youtube.Upload(testChannelID, youtubetest.Video{
+			ID:          fmt.Sprintf("vid%02d", i),
+			Title:       fmt.Sprintf("Video %d", i),
+			PublishedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i),
+		})
+	}
+}
+
+// sync runs a sync job that must succeed, and returns its result.
+func (a *testApp) sync(t *testing.T, query string) model.SyncResult {
+	t.Helper()
+
+	job := a.runSyncJob(t, "/api/videos/sync?api_key=key"+query)
+	if job.Status != model.SyncRunSucceeded {
+		t.Fatalf("sync%s: job = %+v, want it to succeed", query, job)
+	}
+	return job.Progress
+}
+
+// runSyncJob starts a sync job and waits for it to finish.
+func (a *testApp) runSyncJob(t *testing.T, target string) model.SyncJob {
+	t.Helper()
+
+	var body model.APIResponse[model.SyncJob]
+	resp := a.do(t, http.MethodPost, target, "", &body)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST %s: status = %d, want 202", target, resp.StatusCode)
+	}
+	return a.waitForJob(t, body.Data.ID)
+}
+
+// waitForJob polls a sync job until it is no longer running.
+func (a *testApp) waitForJob(t *testing.T, id string) model.SyncJob {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var body model.APIResponse[model.SyncJob]
+		if resp := a.do(t, http.MethodGet, "/api/sync/jobs/"+id, "", &body); resp.StatusCode != http.StatusOK {
+			t.Fatalf("sync job %s: status = %d, want 200", id, resp.StatusCode)
+		}
+		if body.Data.Status != model.SyncRunRunning {
+			return body.Data
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("sync job %s still running", id)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestIncrementalSyncStopsAtCheckpoint(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.PageSize = 2
+	app.upload(1, 3)
+
+	got := app.sync(t, "")
+	if got.Mode != "incremental" || got.Added != 3 || !got.Complete {
+		t.Fatalf("first sync = %+v, want 3 added", got)
+	}
+
+	app.upload(4, 3)
+	pagesBefore := app.youtube.Requests("playlistItems")
+
+	got = app.sync(t, "")
+	if got.Added != 3 || got.Skipped != 0 || got.Total != 3 || !got.Complete {
+		t.Fatalf("second sync = %+v, want only the 3 new videos", got)
+	}
+	// Two pages to reach the checkpoint, and the one after it to reconcile
+	// the stored videos.
+	if pages := app.youtube.Requests("playlistItems") - pagesBefore; pages != 3 {
+		t.Fatalf("second sync read %d pages, want 3", pages)
+	}
+
+	got = app.sync(t, "")
+	if got.Added != 0 || got.Total != 0 || !got.Complete {
+		t.Fatalf("third sync = %+v, want nothing to do", got)
+	}
+	if n := app.youtube.Requests("channels"); n != 0 {
+		t.Fatalf("channels.list called %d times, want 0", n)
+	}
+}
+
+func TestIncrementalSyncLimitedByMaxResults(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 2)
+	app.sync(t, "")
+
+	app.upload(3, 5)
+	got := app.sync(t, "&max_results=3")
+	if got.Added != 3 || got.Complete {
+		t.Fatalf("capped sync = %+v, want 3 added and incomplete", got)
+	}
+
+	// The checkpoint didn't move, so the next sync still finds the gap.
+	got = app.sync(t, "")
+	if got.Added != 2 || got.Skipped != 3 || !got.Complete {
+		t.Fatalf("next sync = %+v, want the 2 missed videos", got)
+	}
+}
+
+func TestBackfillResumes(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.PageSize = 2
+	app.upload(1, 5)
+
+	got := app.sync(t, "&mode=backfill&max_results=1")
+	if got.Mode != "backfill" || got.Added != 2 || got.Complete {
+		t.Fatalf("first backfill = %+v, want one page", got)
+	}
+
+	// Interrupted backfills pick up from the saved page token.
+	app.youtube.Fail("playlistItems", 1, http.StatusInternalServerError)
+	if job := app.runSyncJob(t, "/api/videos/sync?mode=backfill"); job.Status != model.SyncRunFailed || job.Error == nil {
+		t.Fatalf("failing backfill = %+v, want it to fail", job)
+	}
+
+	got = app.sync(t, "&mode=backfill")
+	if got.Added != 3 || got.Skipped != 0 || !got.Complete {
+		t.Fatalf("resumed backfill = %+v, want the remaining 3 videos", got)
+	}
+
+	var videos model.APIResponse[[]model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos", "", &videos)
+	if videos.Meta.Total != 5 {
+		t.Fatalf("total = %d, want 5", videos.Meta.Total)
+	}
+
+	// The backfill set the checkpoint, so an incremental sync is a no-op.
+	app.upload(6, 1)
+	got = app.sync(t, "")
+	if got.Added != 1 || got.Total != 1 || !got.Complete {
+		t.Fatalf("incremental sync = %+v, want only the new video", got)
+	}
+}
+
+func TestBackfillRestartsOnExpiredPageToken(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 3)
+	app.seed(t, `INSERT INTO sync_state (channel_id, uploads_playlist_id, backfill_page_token) VALUES (?, ?, 'expired')`,
+		testChannelID, testUploadsID)
+
+	got := app.sync(t, "&mode=backfill")
+	if got.Added != 3 || !got.Complete {
+		t.Fatalf("backfill = %+v, want all 3 videos", got)
+	}
+}
+
+func TestSyncRejectsUnknownMode(t *testing.T) {
+	app := newTestApp(t)
+
+	resp := app.do(t, http.MethodPost, "/api/videos/sync?mode=everything", "", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestSyncStoresVideoDetails(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.youtube.Upload(testChannelID,
+		youtubetest.Video{
+			ID:              "vod",
+			Title:           "Elden Ring EP.1",
+			PublishedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Duration:        1*time.Hour + 2*time.Minute + 3*time.Second,
+			Views:           3_000_000_000,
+			Likes:           120,
+			Comments:        7,
+			Tags:            []string{"elden ring", "เกม"},
+			CategoryID:      "20",
+			DefaultLanguage: "th",
+		},
+		youtubetest.Video{
+			ID:          "stream",
+			Title:       "Live now",
+			PublishedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			LiveStatus:  "live",
+		},
+	)
+
+	got := app.sync(t, "")
+	if got.Added != 2 {
+		t.Fatalf("sync = %+v, want 2 added", got)
+	}
+	if n := app.youtube.Requests("videos"); n != 1 {
+		t.Fatalf("videos.list called %d times, want 1", n)
+	}
+
+	var body model.APIResponse[model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos/vod", "", &body)
+	vod := body.Data
+	if vod.DurationSeconds == nil || *vod.DurationSeconds != 3723 {
+		t.Fatalf("duration = %v, want 3723", vod.DurationSeconds)
+	}
+	if vod.ViewCount == nil || *vod.ViewCount != 3_000_000_000 || *vod.LikeCount != 120 || *vod.CommentCount != 7 {
+		t.Fatalf("counts = %v %v %v", vod.ViewCount, vod.LikeCount, vod.CommentCount)
+	}
+	if len(vod.Tags) != 2 || vod.Tags[1] != "เกม" {
+		t.Fatalf("tags = %v", vod.Tags)
+	}
+	if vod.CategoryID == nil || *vod.CategoryID != "20" || vod.DefaultLanguage == nil || *vod.DefaultLanguage != "th" {
+		t.Fatalf("category = %v, language = %v", vod.CategoryID, vod.DefaultLanguage)
+	}
+	if vod.LiveStatus == nil || *vod.LiveStatus != model.LiveStatusNone {
+		t.Fatalf("live_status = %v, want none", vod.LiveStatus)
+	}
+
+	app.do(t, http.MethodGet, "/api/videos/stream", "", &body)
+	if s := body.Data.LiveStatus; s == nil || *s != model.LiveStatusLive || body.Data.DurationSeconds != nil {
+		t.Fatalf("stream = %v, duration %v, want live without a duration", s, body.Data.DurationSeconds)
+	}
+}
+
+func TestSyncStoresPageInOneGo(t *testing.T) {
+	requests := func(n int) int {
+		app := newTestApp(t)
+		app.youtube.AddChannel(testChannel)
+		app.upload(1, n)
+
+		before := app.d1.Requests()
+		if got := app.sync(t, ""); got.Added != n {
+			t.Fatalf("sync = %+v, want %d added", got, n)
+		}
+		return app.d1.Requests() - before
+	}
+
+	// A page of 50 videos costs no more database requests than one video.
+	if one, page := requests(1), requests(50); page != one {
+		t.Fatalf("syncing 50 videos made %d database requests, want %d as for one", page, one)
+	}
+}
+
+func TestBackfillFillsInMissingDetails(t *testing.T) {
+	app := newTestApp(t)
+	app.youtube.AddChannel(testChannel)
+	app.upload(1, 3)
+	app.seed(t, `INSERT INTO videos (id, title, thumbnail, published_at)
+		VALUES ('vid01', 'Video 1', 'https://i.ytimg.com/vi/vid01/hqdefault.jpg', '2024-01-02 00:00:00')`)
+	app.youtube.Fail("videos", 1, http.StatusForbidden)
+
+	// A failed lookup fails the sync, so no video is stored without details.
+	if job := app.runSyncJob(t, "/api/videos/sync?mode=backfill"); job.Status != model.SyncRunFailed {
+		t.Fatalf("failing sync = %+v, want it to fail", job)
+	}
+
+	got := app.sync(t, "&mode=backfill")
+	if got.Added != 2 || got.Skipped != 1 {
+		t.Fatalf("backfill = %+v, want 2 added, 1 skipped", got)
+	}
+
+	var body model.APIResponse[model.VideoResponse]
+	app.do(t, http.MethodGet, "/api/videos/vid01", "", &body)
+	if body.Data.LiveStatus == nil || body.Data.ViewCount == nil {
+		t.Fatalf("vid01 = %+v, want its details filled in", body.Data)
+	}
+
+	// Videos with details aren't looked up again.
+	before := app.youtube.Requests("videos")
+	app.sync(t, "&mode=backfill")
+	if n := app.youtube.Requests("videos") - before; n != 0 {
+		t.Fatalf("videos.list called %d times, want 0", n)
+	}
+}
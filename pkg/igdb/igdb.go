@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -13,6 +15,21 @@ import (
 const (
 	tokenURL = "https://id.twitch.tv/oauth2/token"
 	apiURL   = "https://api.igdb.com/v4/games"
+
+	// requestInterval spaces out API requests: IGDB allows 4 a second.
+	requestInterval = 250 * time.Millisecond
+
+	// MaxGamesPerRequest is the most games IGDB returns for one request.
+	MaxGamesPerRequest = 500
+
+	coverURL = "https://images.igdb.com/igdb/image/upload/t_cover_big/%s.jpg"
+
+	// gameFields are the fields of a game GetGames requests, expanding the
+	// related entities it references.
+	gameFields = "name,url,summary,first_release_date,total_rating,total_rating_count," +
+		"cover.image_id,genres.name,themes.name,platforms.name,platforms.abbreviation," +
+		"involved_companies.company.name,involved_companies.developer,involved_companies.publisher," +
+		"alternative_names.name,alternative_names.comment"
 )
 
 // TokenResponse represents the OAuth2 token response from Twitch
@@ -29,6 +46,61 @@ type GameSearchResult struct {
 	URL  string `json:"url"`
 }
 
+// Game is a game from IGDB with its metadata. FirstReleaseDate is a Unix
+// timestamp, 0 if unknown; TotalRating runs from 0 to 100.
+type Game struct {
+	ID                int64             `json:"id"`
+	Name              string            `json:"name"`
+	URL               string            `json:"url"`
+	Summary           string            `json:"summary"`
+	FirstReleaseDate  int64             `json:"first_release_date"`
+	TotalRating       float64           `json:"total_rating"`
+	TotalRatingCount  int32             `json:"total_rating_count"`
+	Cover             *Cover            `json:"cover"`
+	Genres            []Entity          `json:"genres"`
+	Themes            []Entity          `json:"themes"`
+	Platforms         []Platform        `json:"platforms"`
+	InvolvedCompanies []InvolvedCompany `json:"involved_companies"`
+	AlternativeNames  []AlternativeName `json:"alternative_names"`
+}
+
+// Entity is a genre, theme or company.
+type Entity struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Platform is a platform a game was released on.
+type Platform struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+}
+
+// InvolvedCompany is a company that developed or published a game.
+type InvolvedCompany struct {
+	Company   Entity `json:"company"`
+	Developer bool   `json:"developer"`
+	Publisher bool   `json:"publisher"`
+}
+
+// AlternativeName is another name a game goes by; Comment says which,
+// such as "Acronym".
+type AlternativeName struct {
+	Name    string `json:"name"`
+	Comment string `json:"comment"`
+}
+
+// Cover is the cover art of a game.
+type Cover struct {
+	ImageID string `json:"image_id"`
+}
+
+// URL returns the address of the cover image, 264x374.
+func (c Cover) URL() string {
+	return fmt.Sprintf(coverURL, c.ImageID)
+}
+
 // Client handles IGDB API requests with automatic token refresh
 type Client struct {
 	clientID     string
@@ -37,6 +109,13 @@ type Client struct {
 	expiresAt    time.Time
 	mu           sync.RWMutex
 	httpClient   *http.Client
+	tokenURL     string
+	apiURL       string
+
+	// throttle is held while waiting for the next request slot.
+	throttle    sync.Mutex
+	interval    time.Duration
+	lastRequest time.Time
 }
 
 // NewClient creates a new IGDB client
@@ -47,13 +126,39 @@ func NewClient(clientID, clientSecret string) *Client {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		tokenURL: tokenURL,
+		apiURL:   apiURL,
+		interval: requestInterval,
 	}
 }
 
+// SetEndpoints points the client at a different token endpoint and games
+// API, such as a fake one in tests.
+func (c *Client) SetEndpoints(tokenURL, apiURL string) {
+	c.tokenURL = tokenURL
+	c.apiURL = apiURL
+}
+
+// SetRequestInterval changes how long the client waits between requests.
+func (c *Client) SetRequestInterval(interval time.Duration) {
+	c.interval = interval
+}
+
+// wait blocks until the next request can be made within IGDB's rate limit.
+func (c *Client) wait() {
+	c.throttle.Lock()
+	defer c.throttle.Unlock()
+
+	if next := c.lastRequest.Add(c.interval); time.Now().Before(next) {
+		time.Sleep(time.Until(next))
+	}
+	c.lastRequest = time.Now()
+}
+
 // getAccessToken requests a new access token from Twitch OAuth
 func (c *Client) getAccessToken() error {
 	url := fmt.Sprintf("%s?client_id=%s&client_secret=%s&grant_type=client_credentials",
-		tokenURL, c.clientID, c.clientSecret)
+		c.tokenURL, c.clientID, c.clientSecret)
 
 	resp, err := c.httpClient.Post(url, "application/json", nil)
 	if err != nil {
@@ -95,16 +200,52 @@ func (c *Client) ensureValidToken() error {
 
 // SearchGames searches for games by name using the IGDB API
 func (c *Client) SearchGames(query string) ([]GameSearchResult, error) {
-	if err := c.ensureValidToken(); err != nil {
-		return nil, fmt.Errorf("failed to ensure valid token: %w", err)
+	// Build IGDB query - search by name, return only main games (game_type = 0)
+	body := fmt.Sprintf(`search "%s"; fields name,url; where game_type = 0;`, strings.ReplaceAll(query, `"`, ""))
+
+	var results []GameSearchResult
+	if err := c.query(body, &results); err != nil {
+		return nil, fmt.Errorf("failed to search games: %w", err)
 	}
 
-	// Build IGDB query - search by name, return only main games (game_type = 0)
-	body := fmt.Sprintf(`search "%s"; fields name,url; where game_type = 0;`, query)
+	return results, nil
+}
+
+// GetGames returns the games with the given IDs, with their metadata. IDs
+// IGDB doesn't know are left out; at most MaxGamesPerRequest can be asked
+// for at once.
+func (c *Client) GetGames(ids []int64) ([]Game, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if len(ids) > MaxGamesPerRequest {
+		return nil, fmt.Errorf("can't get more than %d games at once, got %d", MaxGamesPerRequest, len(ids))
+	}
 
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBufferString(body))
+	list := make([]string, len(ids))
+	for i, id := range ids {
+		list[i] = strconv.FormatInt(id, 10)
+	}
+	body := fmt.Sprintf(`fields %s; where id = (%s); limit %d;`, gameFields, strings.Join(list, ","), len(ids))
+
+	var games []Game
+	if err := c.query(body, &games); err != nil {
+		return nil, fmt.Errorf("failed to get games: %w", err)
+	}
+
+	return games, nil
+}
+
+// query sends an Apicalypse query to the games endpoint, and decodes the
+// response into out.
+func (c *Client) query(body string, out any) error {
+	if err := c.ensureValidToken(); err != nil {
+		return fmt.Errorf("failed to ensure valid token: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.apiURL, bytes.NewBufferString(body))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	c.mu.RLock()
@@ -115,21 +256,21 @@ func (c *Client) SearchGames(query string) ([]GameSearchResult, error) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
 	req.Header.Set("Content-Type", "text/plain")
 
+	c.wait()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search games: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("search request failed with status %d: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	var results []GameSearchResult
-	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return results, nil
+	return nil
 }
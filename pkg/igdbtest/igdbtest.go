@@ -0,0 +1,206 @@
+// Package igdbtest provides an in-process stand-in for the IGDB API and the
+// Twitch token endpoint it authenticates with, for use in tests.
+//
+// Only the calls the app makes are implemented: the client credentials
+// token request, a search of the games endpoint, which returns the games
+// whose name contains every word of the search, and a lookup of games by
+// ID, which returns them with their metadata. Server.Fail injects API
+// errors.
+package igdbtest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/K0ng2/zeedzad/igdb"
+)
+
+// Server is a fake IGDB API.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	games    []Game
+	searches []string
+	lookups  [][]int64
+	faults   []int
+}
+
+// Game is a game as the fake API reports it. Searches only report its ID,
+// name and URL.
+type Game = igdb.Game
+
+// NewServer starts a fake IGDB API that is shut down when the test ends.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+
+	s := &Server{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth2/token", s.token)
+	mux.HandleFunc("POST /v4/games", s.queryGames)
+
+	s.Server = httptest.NewServer(mux)
+	tb.Cleanup(s.Close)
+
+	return s
+}
+
+// NewClient returns an IGDB client talking to the fake server, without
+// waiting between requests.
+func (s *Server) NewClient() *igdb.Client {
+	client := igdb.NewClient("igdbtest-client", "igdbtest-secret")
+	client.SetEndpoints(s.URL+"/oauth2/token", s.URL+"/v4/games")
+	client.SetRequestInterval(0)
+	return client
+}
+
+// AddGames adds games to the catalogue.
+func (s *Server) AddGames(games ...Game) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.games = append(s.games, games...)
+}
+
+// SetGame changes a game of the catalogue.
+func (s *Server) SetGame(id int64, change func(*Game)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.games {
+		if s.games[i].ID == id {
+			change(&s.games[i])
+		}
+	}
+}
+
+// Searches returns the searches made so far, in order.
+func (s *Server) Searches() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.searches...)
+}
+
+// Lookups returns the IDs of the games looked up so far, a list a request.
+func (s *Server) Lookups() [][]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([][]int64(nil), s.lookups...)
+}
+
+// Fail makes the next n requests for games fail with the given HTTP
+// status.
+func (s *Server) Fail(n, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for range n {
+		s.faults = append(s.faults, status)
+	}
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"access_token": "igdbtest-token",
+		"expires_in":   3600 * 24,
+		"token_type":   "bearer",
+	})
+}
+
+var (
+	searchPattern = regexp.MustCompile(`search "([^"]*)"`)
+	lookupPattern = regexp.MustCompile(`where id = \(([\d,]*)\)`)
+)
+
+func (s *Server) queryGames(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	search := searchPattern.FindSubmatch(body)
+	lookup := lookupPattern.FindSubmatch(body)
+	if search == nil && lookup == nil {
+		http.Error(w, "only searches and lookups by ID are supported", http.StatusBadRequest)
+		return
+	}
+
+	var ids []int64
+	if lookup != nil {
+		for _, id := range strings.Split(string(lookup[1]), ",") {
+			n, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ids = append(ids, n)
+		}
+	}
+
+	s.mu.Lock()
+	if search != nil {
+		s.searches = append(s.searches, string(search[1]))
+	} else {
+		s.lookups = append(s.lookups, ids)
+	}
+	var status int
+	if len(s.faults) > 0 {
+		status, s.faults = s.faults[0], s.faults[1:]
+	}
+	games := s.games
+	s.mu.Unlock()
+
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	if lookup != nil {
+		results := []Game{}
+		for _, game := range games {
+			if slices.Contains(ids, game.ID) {
+				results = append(results, game)
+			}
+		}
+		writeJSON(w, results)
+		return
+	}
+
+	words := strings.Fields(strings.ToLower(string(search[1])))
+	results := []map[string]any{}
+	for _, game := range games {
+		name := strings.ToLower(game.Name)
+		if !containsAll(name, words) {
+			continue
+		}
+		results = append(results, map[string]any{"id": game.ID, "name": game.Name, "url": game.URL})
+	}
+
+	writeJSON(w, results)
+}
+
+func containsAll(name string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(name, word) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -1,16 +1,20 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
-
-	"github.com/robfig/cron/v3"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/K0ng2/zeedzad/config"
 	"github.com/K0ng2/zeedzad/db"
 	"github.com/K0ng2/zeedzad/handler"
 	"github.com/K0ng2/zeedzad/igdb"
+	"github.com/K0ng2/zeedzad/metrics"
+	"github.com/K0ng2/zeedzad/migrations"
 	"github.com/K0ng2/zeedzad/server"
 )
 
@@ -18,55 +22,177 @@ var port string
 
 func main() {
 	flag.StringVar(&port, "port", ":8088", "Server port")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [migrate up|down|status]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	database, err := db.NewDatabase(config.D1_ACCOUNT_ID, config.D1_DATABASE_ID, config.CLOUDFLARE_API_TOKEN)
+	database, err := db.NewDatabase(db.Config{
+		Driver:     config.DB_DRIVER,
+		AccountID:  config.D1_ACCOUNT_ID,
+		DatabaseID: config.D1_DATABASE_ID,
+		APIToken:   config.CLOUDFLARE_API_TOKEN,
+		SQLitePath: config.SQLITE_PATH,
+	})
 	if err != nil {
-		log.Fatalf("Failed to connect to Cloudflare D1: %v", err)
+		log.Fatalf("Failed to open database: %v", err)
 	}
 	defer database.Close()
 
+	migrator, err := migrations.New(database)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(context.Background(), migrator, flag.Arg(1)); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	if pending, err := migrator.Pending(context.Background()); err != nil {
+		log.Printf("Failed to check migrations: %v", err)
+	} else if len(pending) > 0 {
+		log.Printf("Database has %d pending migration(s); run '%s migrate up'", len(pending), os.Args[0])
+	}
+
 	// Initialize IGDB client
 	if config.IGDB_CLIENT_ID == "" || config.IGDB_CLIENT_SECRET == "" {
 		log.Fatal("IGDB_CLIENT_ID and IGDB_CLIENT_SECRET environment variables are required")
 	}
 	igdbClient := igdb.NewClient(config.IGDB_CLIENT_ID, config.IGDB_CLIENT_SECRET)
 
+	m := metrics.New()
+	if d1, ok := database.(*db.D1Database); ok {
+		d1.AddQueryObserver(m)
+
+		if config.SLOW_QUERY_MS != "" {
+			ms, err := strconv.Atoi(config.SLOW_QUERY_MS)
+			if err != nil || ms <= 0 {
+				log.Fatalf("SLOW_QUERY_MS must be a positive number of milliseconds, got %q", config.SLOW_QUERY_MS)
+			}
+			d1.AddQueryObserver(db.SlowQueryLog{Threshold: time.Duration(ms) * time.Millisecond})
+		}
+	}
+
+	websub := handler.WebSubConfig{
+		HubURL:      config.WEBSUB_HUB_URL,
+		CallbackURL: config.WEBSUB_CALLBACK_URL,
+		Secret:      config.WEBSUB_SECRET,
+	}
+
 	handler := handler.NewHandler(database, igdbClient)
 
+	if config.YOUTUBE_QUOTA_BUDGET != "" {
+		budget, err := strconv.Atoi(config.YOUTUBE_QUOTA_BUDGET)
+		if err != nil || budget <= 0 {
+			log.Fatalf("YOUTUBE_QUOTA_BUDGET must be a positive number of quota units, got %q", config.YOUTUBE_QUOTA_BUDGET)
+		}
+		handler.SetQuotaBudget(budget)
+	}
+
 	// show config
 	fmt.Println("Using configuration:")
-	fmt.Printf("  D1_ACCOUNT_ID: %s\n", config.D1_ACCOUNT_ID)
-	fmt.Printf("  D1_DATABASE_ID: %s\n", config.D1_DATABASE_ID)
-	fmt.Printf("  CLOUDFLARE_API_TOKEN: %s\n", maskToken(config.CLOUDFLARE_API_TOKEN))
+	fmt.Printf("  DB_DRIVER: %s\n", config.DB_DRIVER)
+	if config.DB_DRIVER == db.DriverSQLite {
+		fmt.Printf("  SQLITE_PATH: %s\n", config.SQLITE_PATH)
+	} else {
+		fmt.Printf("  D1_ACCOUNT_ID: %s\n", config.D1_ACCOUNT_ID)
+		fmt.Printf("  D1_DATABASE_ID: %s\n", config.D1_DATABASE_ID)
+		fmt.Printf("  CLOUDFLARE_API_TOKEN: %s\n", maskToken(config.CLOUDFLARE_API_TOKEN))
+	}
 	fmt.Printf("  YOUTUBE_API_KEY: %s\n", config.YOUTUBE_API_KEY)
+	fmt.Printf("  YOUTUBE_QUOTA_BUDGET: %s\n", config.YOUTUBE_QUOTA_BUDGET)
 	fmt.Printf("  IGDB_CLIENT_ID: %s\n", config.IGDB_CLIENT_ID)
 	fmt.Printf("  SCHEDULE_CRON: %s\n", config.SCHEDULE_CRON)
+	fmt.Printf("  STATS_REFRESH_CRON: %s\n", config.STATS_REFRESH_CRON)
+	fmt.Printf("  WEBSUB_CALLBACK_URL: %s\n", config.WEBSUB_CALLBACK_URL)
+	fmt.Printf("  WEBSUB_SECRET: %s\n", maskToken(config.WEBSUB_SECRET))
+	fmt.Printf("  WEBSUB_HUB_URL: %s\n", config.WEBSUB_HUB_URL)
+	fmt.Printf("  SLOW_QUERY_MS: %s\n", config.SLOW_QUERY_MS)
 
-	// Setup cron scheduler for YouTube video sync
-	if config.SCHEDULE_CRON != "" {
-		cr := cron.New()
-		_, err := cr.AddFunc(config.SCHEDULE_CRON, func() {
-			fmt.Println("Running scheduled YouTube video sync...")
-			handler.SyncYouTubeVideosScheduled()
-		})
-		if err != nil {
-			log.Printf("Failed to add cron job: %v", err)
+	// Schedule the YouTube sync of each channel
+	if err := handler.StartScheduledSync(context.Background(), config.SCHEDULE_CRON); err != nil {
+		log.Printf("Failed to schedule YouTube sync: %v", err)
+	} else if config.SCHEDULE_CRON != "" {
+		fmt.Printf("YouTube sync scheduled by default: %s\n", config.SCHEDULE_CRON)
+	} else {
+		fmt.Println("SCHEDULE_CRON not set, only channels with their own schedule are synced on a schedule")
+	}
+
+	// Schedule the stats refresh of recent videos
+	if config.STATS_REFRESH_CRON != "" {
+		if err := handler.StartStatsRefresh(config.STATS_REFRESH_CRON); err != nil {
+			log.Printf("Failed to schedule stats refresh: %v", err)
 		} else {
-			cr.Start()
-			fmt.Printf("YouTube sync cron job scheduled: %s\n", config.SCHEDULE_CRON)
+			fmt.Printf("Stats refresh scheduled: %s\n", config.STATS_REFRESH_CRON)
 		}
 	} else {
-		fmt.Println("SCHEDULE_CRON not set, skipping scheduled YouTube sync")
+		fmt.Println("STATS_REFRESH_CRON not set, skipping scheduled stats refresh")
+	}
+
+	// Subscribe to push notifications of new uploads
+	if config.WEBSUB_CALLBACK_URL != "" {
+		if err := handler.StartWebSub(context.Background(), websub); err != nil {
+			log.Printf("Failed to start WebSub: %v", err)
+		} else {
+			fmt.Printf("WebSub subscriptions renewed hourly, callback: %s\n", config.WEBSUB_CALLBACK_URL)
+		}
+	} else {
+		fmt.Println("WEBSUB_CALLBACK_URL not set, skipping WebSub push notifications")
 	}
 
 	// Setup and start the router
-	r := server.NewRouter(handler)
+	r := server.NewRouter(handler, m)
 	if err := r.Listen(port); err != nil {
 		panic(err)
 	}
 }
 
+func runMigrate(ctx context.Context, migrator *migrations.Migrator, command string) error {
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+	case "down":
+		reverted, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if reverted == nil {
+			fmt.Println("No migrations to revert")
+			return nil
+		}
+		fmt.Printf("Reverted %04d_%s\n", reverted.Version, reverted.Name)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, state)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q (want up, down or status)", command)
+	}
+
+	return nil
+}
+
 func maskToken(token string) string {
 	if len(token) < 8 {
 		return "***"
@@ -0,0 +1,91 @@
+package matcher
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestParseTitle(t *testing.T) {
+	tests := []struct {
+		title   string
+		names   []string
+		episode int
+	}{
+		{"[Elden Ring] EP.3 บอสโหดมาก", []string{"Elden Ring"}, 3},
+		{"Hollow Knight Silksong EP.12 ไปต่อกันเลย", []string{"Hollow Knight Silksong"}, 12},
+		{"ผีบ้านสุดหลอน | Phasmophobia", []string{"Phasmophobia"}, 0},
+		{"Hades II ตอนที่ 5 (END)", []string{"Hades II"}, 5},
+		{"【Resident Evil 4】 #7 หนีตายในหมู่บ้าน", []string{"Resident Evil 4"}, 7},
+		{"Ep 2 - Stardew Valley Live", []string{"Stardew Valley"}, 2},
+		{"สุ่มเกม Among Us กับเพื่อน - Fall Guys", []string{"Fall Guys", "Among Us"}, 0},
+		{"เล่นเกมกับเพื่อน", nil, 0},
+	}
+
+	for _, tt := range tests {
+		got := ParseTitle(tt.title)
+
+		var names []string
+		for _, c := range got.Candidates {
+			names = append(names, c.Name)
+		}
+		if !slices.Equal(names, tt.names) || got.Episode != tt.episode {
+			t.Errorf("ParseTitle(%q) = %v, episode %d; want %v, episode %d", tt.title, names, got.Episode, tt.names, tt.episode)
+		}
+	}
+}
+
+func TestParseTitleSeries(t *testing.T) {
+	tests := []struct {
+		title  string
+		series string
+	}{
+		{"[Elden Ring] EP.3 บอสโหดมาก", "Elden Ring"},
+		{"Elden Ring EP.4", "Elden Ring"},
+		{"ผีบ้านสุดหลอน | Phasmophobia EP.2", "Phasmophobia"},
+		{"บอสโหด Hollow Knight EP.7 ไปต่อ", "Hollow Knight"},
+		{"Ep 2 - Stardew Valley Live", "Stardew Valley"},
+		{"ตะลุยดันเจี้ยน ตอนที่ 4", "ตะลุยดันเจี้ยน"},
+		{"Minecraft Hardcore วันที่ 12", "Minecraft Hardcore"},
+		{"Elden Ring บอสโหดมาก", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTitle(tt.title).Series; got != tt.series {
+			t.Errorf("ParseTitle(%q).Series = %q, want %q", tt.title, got, tt.series)
+		}
+	}
+}
+
+func TestParseTitleWeighsNames(t *testing.T) {
+	got := ParseTitle("เอาชีวิตรอด Minecraft Survival | [Terraria] | Stardew Valley")
+
+	want := []Candidate{
+		{"Terraria", bracketWeight},
+		{"Stardew Valley", segmentWeight},
+		{"Minecraft Survival", inlineWeight},
+	}
+	if !slices.Equal(got.Candidates, want) {
+		t.Fatalf("candidates = %+v, want %+v", got.Candidates, want)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		min, max float64
+	}{
+		{"Elden Ring", "ELDEN RING", 1, 1},
+		{"Hades 2", "Hades II", 1, 1},
+		{"Elden Ring", "Elden Ring: Nightreign", 0.6, 0.7},
+		{"Elden Ring", "Portal", 0, 0.1},
+		{"", "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		got := Similarity(tt.a, tt.b)
+		if got < tt.min || got > tt.max || math.IsNaN(got) {
+			t.Errorf("Similarity(%q, %q) = %.2f, want within [%.2f, %.2f]", tt.a, tt.b, got, tt.min, tt.max)
+		}
+	}
+}
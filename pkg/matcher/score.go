@@ -0,0 +1,83 @@
+package matcher
+
+import (
+	"strings"
+	"unicode"
+)
+
+// romanNumerals are the sequel numbers written as Roman numerals in game
+// names, so that "Hades II" and "Hades 2" are the same name.
+var romanNumerals = map[string]string{
+	"ii":   "2",
+	"iii":  "3",
+	"iv":   "4",
+	"v":    "5",
+	"vi":   "6",
+	"vii":  "7",
+	"viii": "8",
+	"ix":   "9",
+	"x":    "10",
+}
+
+// Normalize lowercases a name, drops its punctuation and writes its Roman
+// numerals as numbers. Thai vowel and tone marks are kept with their
+// letters.
+func Normalize(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r)
+	})
+	for i, word := range words {
+		if n, ok := romanNumerals[word]; ok {
+			words[i] = n
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Similarity scores how alike two names are, from 0 to 1: the Dice
+// coefficient of the character pairs of their normalized forms, so a name
+// missing a subtitle still scores well.
+func Similarity(a, b string) float64 {
+	a, b = Normalize(a), Normalize(b)
+	if a == b {
+		if a == "" {
+			return 0
+		}
+		return 1
+	}
+
+	pairsA, pairsB := bigrams(a), bigrams(b)
+	total := len(pairsA) + len(pairsB)
+	if total == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(pairsA))
+	for _, pair := range pairsA {
+		counts[pair]++
+	}
+	shared := 0
+	for _, pair := range pairsB {
+		if counts[pair] > 0 {
+			counts[pair]--
+			shared++
+		}
+	}
+
+	return 2 * float64(shared) / float64(total)
+}
+
+// bigrams returns the pairs of adjacent characters of a normalized name,
+// ignoring the spaces between words.
+func bigrams(name string) []string {
+	runes := []rune(strings.ReplaceAll(name, " ", ""))
+	if len(runes) < 2 {
+		return nil
+	}
+
+	pairs := make([]string, len(runes)-1)
+	for i := range pairs {
+		pairs[i] = string(runes[i : i+2])
+	}
+	return pairs
+}
@@ -0,0 +1,201 @@
+// Package matcher reads game names out of video titles and scores how well
+// they match the names of games.
+package matcher
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Weights of the parts of a title a candidate can come from.
+const (
+	// bracketWeight is for a bracketed name: "[Elden Ring] บอสโหดมาก".
+	bracketWeight = 1.0
+	// episodeWeight is for the name an episode number follows:
+	// "Elden Ring EP.3".
+	episodeWeight = 1.0
+	// segmentWeight is for a part of the title set apart by separators:
+	// "ผีบ้านสุดหลอน | Phasmophobia".
+	segmentWeight = 0.95
+	// inlineWeight is for Latin text amid Thai text, which may just as well
+	// describe the video.
+	inlineWeight = 0.8
+)
+
+var (
+	bracketPattern = regexp.MustCompile(`[\[【「『(（]([^\]】」』)）]*)[\]】」』)）]`)
+	// episodePattern matches "EP.3", "Ep 3", "Episode 3", "Part 3", "Day 3",
+	// "ตอนที่ 3", "วันที่ 3" and "#3".
+	episodePattern   = regexp.MustCompile(`(?i)(?:\b(?:ep|episode|part|pt|day)\.?|ตอนที่|ตอน|วันที่|#)\s*(\d{1,4})\b`)
+	separatorPattern = regexp.MustCompile(`\s[-–—]\s|[|｜/]`)
+)
+
+// noiseWords are words around a game name in a title that aren't part of
+// it.
+var noiseWords = map[string]bool{
+	"live":        true,
+	"full":        true,
+	"gameplay":    true,
+	"walkthrough": true,
+	"end":         true,
+	"ending":      true,
+	"highlight":   true,
+	"highlights":  true,
+	"stream":      true,
+	"shorts":      true,
+	"opztv":       true,
+}
+
+// Candidate is a name read from a video title that may be the game it is
+// about. Weight says how likely the part of the title it came from is to
+// name the game.
+type Candidate struct {
+	Name   string
+	Weight float64
+}
+
+// Title is what ParseTitle reads from a video title.
+type Title struct {
+	// Candidates are the possible game names, most likely first.
+	Candidates []Candidate
+	// Episode is the episode number, or 0 if the title has none.
+	Episode int
+	// Series is the name the episode number follows, which the other
+	// episodes of the playthrough share: "Elden Ring" for both "[Elden Ring]
+	// EP.3 บอสโหดมาก" and "Elden Ring EP.4". It is "" if Episode is 0.
+	Series string
+}
+
+// ParseTitle reads the possible game names and the episode number out of a
+// video title. OPZTV titles mix Thai descriptions with English game names,
+// so Thai text is never taken as a name: it only separates names from
+// each other, like brackets, "|" and " - " do.
+func ParseTitle(title string) Title {
+	var t Title
+
+	if loc := episodePattern.FindStringSubmatchIndex(title); loc != nil {
+		t.Episode, _ = strconv.Atoi(title[loc[2]:loc[3]])
+		t.Series = seriesName(title[:loc[0]])
+	}
+
+	add := func(text string, weight float64) {
+		name := cleanName(text)
+		if name == "" {
+			return
+		}
+
+		key := Normalize(name)
+		if i := slices.IndexFunc(t.Candidates, func(c Candidate) bool { return Normalize(c.Name) == key }); i >= 0 {
+			t.Candidates[i].Weight = max(t.Candidates[i].Weight, weight)
+			return
+		}
+		t.Candidates = append(t.Candidates, Candidate{Name: name, Weight: weight})
+	}
+
+	rest := bracketPattern.ReplaceAllStringFunc(title, func(bracketed string) string {
+		add(bracketPattern.FindStringSubmatch(bracketed)[1], bracketWeight)
+		return " | "
+	})
+
+	for _, segment := range separatorPattern.Split(rest, -1) {
+		if loc := episodePattern.FindStringIndex(segment); loc != nil {
+			runs := latinRuns(segment[:loc[0]])
+			if len(runs) > 0 {
+				add(runs[len(runs)-1], episodeWeight)
+			}
+		}
+
+		runs := latinRuns(episodePattern.ReplaceAllString(segment, " "))
+		weight := segmentWeight
+		if len(runs) != 1 || strings.TrimSpace(runs[0]) != strings.TrimSpace(segment) {
+			weight = inlineWeight
+		}
+		for _, run := range runs {
+			add(run, weight)
+		}
+	}
+
+	slices.SortStableFunc(t.Candidates, func(a, b Candidate) int {
+		return cmp.Compare(b.Weight, a.Weight)
+	})
+
+	// "EP.2 - Stardew Valley" names the game after the episode number.
+	if t.Episode > 0 && t.Series == "" && len(t.Candidates) > 0 {
+		t.Series = t.Candidates[0].Name
+	}
+
+	return t
+}
+
+// seriesName returns the name at the end of the part of a title before its
+// episode number: its last part set apart by brackets or separators, and
+// only the last Latin text of it if it has any, since a Thai description
+// before the name changes from episode to episode.
+func seriesName(prefix string) string {
+	prefix = bracketPattern.ReplaceAllString(prefix, " | ${1} | ")
+
+	segments := separatorPattern.Split(prefix, -1)
+	for i := len(segments) - 1; i >= 0; i-- {
+		runs := latinRuns(segments[i])
+		for j := len(runs) - 1; j >= 0; j-- {
+			if name := cleanName(runs[j]); name != "" {
+				return name
+			}
+		}
+
+		if name := strings.Join(strings.Fields(segments[i]), " "); name != "" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// latinRuns splits text on Thai characters.
+func latinRuns(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.Is(unicode.Thai, r)
+	})
+}
+
+// cleanName trims a candidate down to the characters a game name can have,
+// and drops the noise words around it. It returns "" if nothing that looks
+// like a name is left.
+func cleanName(text string) string {
+	text = episodePattern.ReplaceAllString(text, " ")
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.Is(unicode.Thai, r):
+			return ' '
+		case unicode.IsLetter(r), unicode.IsDigit(r), strings.ContainsRune(":'&!.+-", r):
+			return r
+		}
+		return ' '
+	}, text)
+
+	words := strings.Fields(text)
+	for len(words) > 0 && noiseWords[Normalize(words[0])] {
+		words = words[1:]
+	}
+	for len(words) > 0 && noiseWords[Normalize(words[len(words)-1])] {
+		words = words[:len(words)-1]
+	}
+
+	name := strings.Trim(strings.Join(words, " "), ":'&!.+- ")
+
+	letters := 0
+	for _, r := range name {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters < 2 {
+		return ""
+	}
+
+	return name
+}
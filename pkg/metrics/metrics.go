@@ -0,0 +1,199 @@
+// Package metrics exposes Prometheus metrics for the D1 cost of each query
+// and each API endpoint.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/K0ng2/zeedzad/db"
+)
+
+const namespace = "zeedzad"
+
+// maxFingerprintLabel caps the fingerprint label of the statement info
+// metric; long SELECT lists would otherwise bloat every scrape.
+const maxFingerprintLabel = 512
+
+// Metrics records D1 usage. It implements db.QueryObserver.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	queries       *prometheus.CounterVec
+	queryDuration *prometheus.HistogramVec
+	rowsRead      *prometheus.CounterVec
+	rowsWritten   *prometheus.CounterVec
+	statementInfo *prometheus.GaugeVec
+
+	endpointQueries     *prometheus.CounterVec
+	endpointRowsRead    *prometheus.CounterVec
+	endpointRowsWritten *prometheus.CounterVec
+	endpointDuration    *prometheus.CounterVec
+}
+
+// New creates the metrics on a fresh registry that also carries the Go
+// runtime and process collectors.
+func New() *Metrics {
+	statement := []string{"statement"}
+	endpoint := []string{"method", "route"}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		queries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "d1",
+			Name:      "queries_total",
+			Help:      "D1 statements executed, by statement fingerprint and outcome.",
+		}, []string{"statement", "outcome"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "d1",
+			Name:      "query_duration_seconds",
+			Help:      "Time D1 spent executing each statement, excluding the network.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, statement),
+		rowsRead: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "d1",
+			Name:      "rows_read_total",
+			Help:      "Rows read by D1 (billed), by statement fingerprint.",
+		}, statement),
+		rowsWritten: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "d1",
+			Name:      "rows_written_total",
+			Help:      "Rows written by D1 (billed), by statement fingerprint.",
+		}, statement),
+		statementInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "d1",
+			Name:      "statement_info",
+			Help:      "Maps statement IDs to their normalized SQL. Always 1.",
+		}, []string{"statement", "kind", "fingerprint"}),
+
+		endpointQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "endpoint",
+			Name:      "d1_queries_total",
+			Help:      "D1 statements executed while serving each API route.",
+		}, endpoint),
+		endpointRowsRead: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "endpoint",
+			Name:      "d1_rows_read_total",
+			Help:      "D1 rows read while serving each API route.",
+		}, endpoint),
+		endpointRowsWritten: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "endpoint",
+			Name:      "d1_rows_written_total",
+			Help:      "D1 rows written while serving each API route.",
+		}, endpoint),
+		endpointDuration: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "endpoint",
+			Name:      "d1_duration_seconds_total",
+			Help:      "D1 execution time spent while serving each API route.",
+		}, endpoint),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.queries, m.queryDuration, m.rowsRead, m.rowsWritten, m.statementInfo,
+		m.endpointQueries, m.endpointRowsRead, m.endpointRowsWritten, m.endpointDuration,
+	)
+
+	return m
+}
+
+// Registry returns the registry the metrics are registered on.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveQuery implements db.QueryObserver.
+func (m *Metrics) ObserveQuery(ctx context.Context, s db.QueryStats) {
+	id := statementID(s.Fingerprint)
+
+	kind := "read"
+	if s.Write {
+		kind = "write"
+	}
+	fingerprint := s.Fingerprint
+	if len(fingerprint) > maxFingerprintLabel {
+		fingerprint = fingerprint[:maxFingerprintLabel] + "..."
+	}
+	m.statementInfo.WithLabelValues(id, kind, fingerprint).Set(1)
+
+	m.queries.WithLabelValues(id, outcome(s.Err)).Inc()
+	if s.Err != nil {
+		return
+	}
+
+	m.queryDuration.WithLabelValues(id).Observe(s.Duration.Seconds())
+	m.rowsRead.WithLabelValues(id).Add(float64(s.RowsRead))
+	m.rowsWritten.WithLabelValues(id).Add(float64(s.RowsWritten))
+}
+
+// Middleware tallies the D1 usage of each request and records it against
+// the matched route once the handler returns.
+func (m *Metrics) Middleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		usage := &db.Usage{}
+		c.RequestCtx().SetUserValue(db.UsageKey, usage)
+
+		err := c.Next()
+
+		if usage.Queries.Load() > 0 {
+			labels := prometheus.Labels{"method": c.Method(), "route": c.Route().Path}
+			m.endpointQueries.With(labels).Add(float64(usage.Queries.Load()))
+			m.endpointRowsRead.With(labels).Add(float64(usage.RowsRead.Load()))
+			m.endpointRowsWritten.With(labels).Add(float64(usage.RowsWritten.Load()))
+			m.endpointDuration.With(labels).Add(time.Duration(usage.Duration.Load()).Seconds())
+		}
+
+		return err
+	}
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// statementID is a short, stable label value for a fingerprint.
+func statementID(fingerprint string) string {
+	h := fnv.New64a()
+	h.Write([]byte(fingerprint))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, db.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, db.ErrConstraint):
+		return "constraint"
+	case errors.Is(err, db.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, db.ErrTransient):
+		return "transient"
+	case errors.Is(err, db.ErrAuth):
+		return "auth"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,69 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/K0ng2/zeedzad/db/d1test"
+	"github.com/K0ng2/zeedzad/metrics"
+)
+
+func TestEndpointAndStatementMetrics(t *testing.T) {
+	srv := d1test.NewServer(t)
+	database := srv.NewDatabase(t)
+
+	m := metrics.New()
+	database.AddQueryObserver(m)
+
+	app := fiber.New()
+	app.Get("/metrics", m.Handler())
+	api := app.Group("api")
+	api.Use(m.Middleware())
+	api.Get("/games/:id", func(c fiber.Ctx) error {
+		var name string
+		err := database.Conn().QueryRowContext(c.RequestCtx(), "SELECT name FROM games WHERE id = ?", c.Params("id")).Scan(&name)
+		if err != nil {
+			return c.SendStatus(http.StatusNotFound)
+		}
+		return c.SendString(name)
+	})
+
+	if _, err := srv.SQLite.Conn().Exec(`INSERT INTO games (id, name, url) VALUES (1, 'Portal', 'u'), (2, 'Celeste', 'u')`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	for _, id := range []string{"1", "2"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/games/"+id, nil), fiber.TestConfig{Timeout: 5 * time.Second})
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET /api/games/%s: %v, %v", id, resp, err)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil), fiber.TestConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	out := string(body)
+
+	for _, want := range []string{
+		`zeedzad_endpoint_d1_queries_total{method="GET",route="/api/games/:id"} 2`,
+		`zeedzad_endpoint_d1_rows_read_total{method="GET",route="/api/games/:id"} 2`,
+		`fingerprint="SELECT name FROM games WHERE id = ?",kind="read"`,
+		`zeedzad_d1_query_duration_seconds_count{statement=`,
+		`outcome="ok"`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output is missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+-- Videos can cover several games (compilations, "top 5" videos). Each game
+-- on a video has a position and an optional segment of the video, in
+-- seconds. videos.game_id is kept as the primary game: the one at the lowest
+-- position.
+CREATE TABLE IF NOT EXISTS video_games (
+	video_id TEXT NOT NULL,
+	game_id INTEGER NOT NULL,
+	position INTEGER NOT NULL DEFAULT 0,
+	start_seconds INTEGER,
+	end_seconds INTEGER,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (video_id, game_id),
+	FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE,
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+	CHECK (start_seconds IS NULL OR start_seconds >= 0),
+	CHECK (end_seconds IS NULL OR start_seconds IS NULL OR end_seconds > start_seconds)
+);
+
+CREATE INDEX IF NOT EXISTS idx_video_games_game_id ON video_games(game_id);
+CREATE INDEX IF NOT EXISTS idx_video_games_position ON video_games(video_id, position);
+
+INSERT OR IGNORE INTO video_games (video_id, game_id, position)
+SELECT id, game_id, 0 FROM videos WHERE game_id IS NOT NULL;
@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS games_fts_update;
+DROP TRIGGER IF EXISTS games_fts_delete;
+DROP TRIGGER IF EXISTS games_fts_insert;
+DROP TABLE IF EXISTS games_fts;
+
+DROP TRIGGER IF EXISTS video_games_fts_delete;
+DROP TRIGGER IF EXISTS video_games_fts_update;
+DROP TRIGGER IF EXISTS video_games_fts_insert;
+DROP TRIGGER IF EXISTS videos_fts_delete;
+DROP TRIGGER IF EXISTS videos_fts_update;
+DROP TRIGGER IF EXISTS videos_fts_insert;
+DROP TABLE IF EXISTS videos_fts;
+
+ALTER TABLE videos DROP COLUMN description;
@@ -0,0 +1,103 @@
+-- Full-text search over videos and games.
+--
+-- Both indexes use the trigram tokenizer: Thai is written without spaces
+-- between words, so a word tokenizer would index whole runs of Thai text as
+-- one token. Trigrams match any substring of three or more characters,
+-- ignoring case and diacritics.
+
+ALTER TABLE videos ADD COLUMN description TEXT;
+
+-- videos_fts holds one row per video: its title, description and the names
+-- of its games in position order. Videos have no stable integer key (a
+-- VACUUM may renumber the rowids of a table with a TEXT primary key), so the
+-- video ID is stored alongside as an unindexed column.
+CREATE VIRTUAL TABLE IF NOT EXISTS videos_fts USING fts5(
+	video_id UNINDEXED,
+	title,
+	description,
+	games,
+	tokenize = 'trigram case_sensitive 0 remove_diacritics 1'
+);
+
+CREATE TRIGGER IF NOT EXISTS videos_fts_insert AFTER INSERT ON videos BEGIN
+	INSERT INTO videos_fts (video_id, title, description, games)
+	VALUES (new.id, new.title, new.description, '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS videos_fts_update AFTER UPDATE OF title, description ON videos BEGIN
+	UPDATE videos_fts SET title = new.title, description = new.description
+	WHERE video_id = new.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS videos_fts_delete AFTER DELETE ON videos BEGIN
+	DELETE FROM videos_fts WHERE video_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS video_games_fts_insert AFTER INSERT ON video_games BEGIN
+	UPDATE videos_fts SET games = (
+		SELECT group_concat(name, ' ') FROM (
+			SELECT g.name FROM video_games vg JOIN games g ON g.id = vg.game_id
+			WHERE vg.video_id = new.video_id ORDER BY vg.position, vg.created_at
+		)
+	)
+	WHERE video_id = new.video_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS video_games_fts_update AFTER UPDATE OF position ON video_games BEGIN
+	UPDATE videos_fts SET games = (
+		SELECT group_concat(name, ' ') FROM (
+			SELECT g.name FROM video_games vg JOIN games g ON g.id = vg.game_id
+			WHERE vg.video_id = new.video_id ORDER BY vg.position, vg.created_at
+		)
+	)
+	WHERE video_id = new.video_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS video_games_fts_delete AFTER DELETE ON video_games BEGIN
+	UPDATE videos_fts SET games = coalesce((
+		SELECT group_concat(name, ' ') FROM (
+			SELECT g.name FROM video_games vg JOIN games g ON g.id = vg.game_id
+			WHERE vg.video_id = old.video_id ORDER BY vg.position, vg.created_at
+		)
+	), '')
+	WHERE video_id = old.video_id;
+END;
+
+INSERT INTO videos_fts (video_id, title, description, games)
+SELECT v.id, v.title, v.description, coalesce((
+	SELECT group_concat(name, ' ') FROM (
+		SELECT g.name FROM video_games vg JOIN games g ON g.id = vg.game_id
+		WHERE vg.video_id = v.id ORDER BY vg.position, vg.created_at
+	)
+), '')
+FROM videos v;
+
+-- games_fts indexes game names, reading them back from games itself.
+CREATE VIRTUAL TABLE IF NOT EXISTS games_fts USING fts5(
+	name,
+	content = 'games',
+	content_rowid = 'id',
+	tokenize = 'trigram case_sensitive 0 remove_diacritics 1'
+);
+
+CREATE TRIGGER IF NOT EXISTS games_fts_insert AFTER INSERT ON games BEGIN
+	INSERT INTO games_fts (rowid, name) VALUES (new.id, new.name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS games_fts_delete AFTER DELETE ON games BEGIN
+	INSERT INTO games_fts (games_fts, rowid, name) VALUES ('delete', old.id, old.name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS games_fts_update AFTER UPDATE OF name ON games BEGIN
+	INSERT INTO games_fts (games_fts, rowid, name) VALUES ('delete', old.id, old.name);
+	INSERT INTO games_fts (rowid, name) VALUES (new.id, new.name);
+	UPDATE videos_fts SET games = (
+		SELECT group_concat(name, ' ') FROM (
+			SELECT g.name FROM video_games vg JOIN games g ON g.id = vg.game_id
+			WHERE vg.video_id = videos_fts.video_id ORDER BY vg.position, vg.created_at
+		)
+	)
+	WHERE video_id IN (SELECT video_id FROM video_games WHERE game_id = new.id);
+END;
+
+INSERT INTO games_fts (games_fts) VALUES ('rebuild');
@@ -0,0 +1,15 @@
+-- sync_state records how far the YouTube sync has got with each channel.
+-- Incremental syncs stop when they reach the newest_* checkpoint; a full
+-- backfill saves the page token it will continue from, so an interrupted
+-- backfill resumes where it stopped.
+CREATE TABLE IF NOT EXISTS sync_state (
+	channel_id TEXT PRIMARY KEY,
+	uploads_playlist_id TEXT,
+	newest_video_id TEXT,
+	newest_published_at DATETIME,
+	last_synced_at DATETIME,
+	backfill_page_token TEXT,
+	backfill_started_at DATETIME,
+	backfill_completed_at DATETIME,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
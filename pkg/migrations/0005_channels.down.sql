@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_videos_channel_id;
+
+ALTER TABLE videos DROP COLUMN channel_id;
+
+DROP TABLE IF EXISTS channels;
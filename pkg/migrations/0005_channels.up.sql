@@ -0,0 +1,26 @@
+-- Channels are the YouTube channels whose uploads are synced. Each can have
+-- its own cron schedule; channels without one use SCHEDULE_CRON.
+CREATE TABLE IF NOT EXISTS channels (
+	id TEXT PRIMARY KEY,
+	handle TEXT,
+	title TEXT NOT NULL,
+	thumbnail TEXT,
+	uploads_playlist_id TEXT NOT NULL,
+	sync_schedule TEXT,
+	sync_enabled BOOLEAN NOT NULL DEFAULT 1,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_channels_handle ON channels(handle);
+
+-- OPZTV was the only channel before channels could be registered, so every
+-- existing video is one of its uploads.
+INSERT OR IGNORE INTO channels (id, handle, title, uploads_playlist_id)
+VALUES ('UCsGx1qSnAS2P1YCJPYnYVUg', '@OPZTV', 'OPZTV', 'UUsGx1qSnAS2P1YCJPYnYVUg');
+
+ALTER TABLE videos ADD COLUMN channel_id TEXT REFERENCES channels(id) ON DELETE SET NULL;
+
+UPDATE videos SET channel_id = 'UCsGx1qSnAS2P1YCJPYnYVUg';
+
+CREATE INDEX IF NOT EXISTS idx_videos_channel_id ON videos(channel_id, published_at DESC);
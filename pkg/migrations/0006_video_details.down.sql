@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_videos_live_status;
+DROP INDEX IF EXISTS idx_videos_duration_seconds;
+
+ALTER TABLE videos DROP COLUMN details_updated_at;
+ALTER TABLE videos DROP COLUMN default_language;
+ALTER TABLE videos DROP COLUMN live_status;
+ALTER TABLE videos DROP COLUMN category_id;
+ALTER TABLE videos DROP COLUMN tags;
+ALTER TABLE videos DROP COLUMN comment_count;
+ALTER TABLE videos DROP COLUMN like_count;
+ALTER TABLE videos DROP COLUMN view_count;
+ALTER TABLE videos DROP COLUMN duration_seconds;
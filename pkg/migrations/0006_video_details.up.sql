@@ -0,0 +1,22 @@
+-- Details the sync reads from videos.list: the playlist items it pages
+-- through only carry the title, description, thumbnails and publish date.
+-- details_updated_at is NULL until a video has been looked up, so videos
+-- synced before this migration are filled in by the next sync that sees
+-- them.
+ALTER TABLE videos ADD COLUMN duration_seconds INTEGER;
+-- Counts are BIGINT so they are read as 64-bit: popular videos pass 2^31
+-- views.
+ALTER TABLE videos ADD COLUMN view_count BIGINT;
+ALTER TABLE videos ADD COLUMN like_count BIGINT;
+ALTER TABLE videos ADD COLUMN comment_count BIGINT;
+-- JSON array of strings.
+ALTER TABLE videos ADD COLUMN tags TEXT;
+ALTER TABLE videos ADD COLUMN category_id TEXT;
+-- 'none' for regular uploads; 'upcoming', 'live' or 'completed' for live
+-- streams.
+ALTER TABLE videos ADD COLUMN live_status TEXT;
+ALTER TABLE videos ADD COLUMN default_language TEXT;
+ALTER TABLE videos ADD COLUMN details_updated_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_videos_duration_seconds ON videos(duration_seconds);
+CREATE INDEX IF NOT EXISTS idx_videos_live_status ON videos(live_status);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_videos_stats_refresh;
+
+ALTER TABLE videos DROP COLUMN stats_updated_at;
+
+DROP TABLE IF EXISTS video_stats;
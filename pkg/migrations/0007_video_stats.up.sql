@@ -0,0 +1,25 @@
+-- video_stats keeps a snapshot of a video's counts every time they are
+-- read, so the growth of a video can be charted. The latest counts are
+-- also kept on videos; stats_updated_at is when they were last read and
+-- decides when the stats refresh looks the video up again.
+CREATE TABLE IF NOT EXISTS video_stats (
+	video_id TEXT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+	captured_at DATETIME NOT NULL,
+	view_count BIGINT,
+	like_count BIGINT,
+	comment_count BIGINT,
+	PRIMARY KEY (video_id, captured_at)
+);
+
+ALTER TABLE videos ADD COLUMN stats_updated_at DATETIME;
+
+-- The counts read by the sync so far are the first snapshot.
+UPDATE videos SET stats_updated_at = details_updated_at
+WHERE details_updated_at IS NOT NULL;
+
+INSERT INTO video_stats (video_id, captured_at, view_count, like_count, comment_count)
+SELECT id, details_updated_at, view_count, like_count, comment_count
+FROM videos
+WHERE details_updated_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_videos_stats_refresh ON videos(published_at, stats_updated_at);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_video_edits_video_id;
+DROP TABLE IF EXISTS video_edits;
+
+DROP INDEX IF EXISTS idx_videos_status;
+
+ALTER TABLE videos DROP COLUMN last_seen_at;
+ALTER TABLE videos DROP COLUMN status;
@@ -0,0 +1,24 @@
+-- The sync reconciles the videos it has stored with the channel: renamed
+-- videos are updated, and videos that were made private or removed are
+-- marked rather than deleted, so their game matches are kept.
+--
+-- status is 'available', 'private' (the playlist lists the video as
+-- private) or 'removed' (a full backfill didn't find it and videos.list
+-- doesn't return it). last_seen_at is when a backfill last found the video
+-- in the uploads playlist.
+ALTER TABLE videos ADD COLUMN status TEXT NOT NULL DEFAULT 'available';
+ALTER TABLE videos ADD COLUMN last_seen_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_videos_status ON videos(status);
+
+-- video_edits is the history of the changes the sync made to a video.
+CREATE TABLE IF NOT EXISTS video_edits (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	video_id TEXT NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+	field TEXT NOT NULL,
+	old_value TEXT,
+	new_value TEXT,
+	changed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_video_edits_video_id ON video_edits(video_id, changed_at);
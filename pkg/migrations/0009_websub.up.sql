@@ -0,0 +1,14 @@
+-- websub_subscriptions tracks the WebSub subscription of each channel to
+-- its upload feed on YouTube's hub. A subscription is pending from the
+-- subscribe request until the hub verifies it, then active until
+-- expires_at, and is renewed before then.
+CREATE TABLE IF NOT EXISTS websub_subscriptions (
+	channel_id TEXT PRIMARY KEY REFERENCES channels(id) ON DELETE CASCADE,
+	topic TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending',
+	lease_seconds INTEGER,
+	requested_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	verified_at DATETIME,
+	expires_at DATETIME,
+	last_notified_at DATETIME
+);
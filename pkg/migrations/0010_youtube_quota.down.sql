@@ -0,0 +1,3 @@
+ALTER TABLE sync_state DROP COLUMN playlist_item_count;
+
+DROP TABLE IF EXISTS youtube_quota;
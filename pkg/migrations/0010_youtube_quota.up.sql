@@ -0,0 +1,16 @@
+-- youtube_quota is the ledger of YouTube Data API quota: how many calls were
+-- made to each endpoint and how many units they cost, per day. Days are
+-- dates in Pacific time, when YouTube resets the quota.
+CREATE TABLE IF NOT EXISTS youtube_quota (
+	day TEXT NOT NULL,
+	endpoint TEXT NOT NULL,
+	calls INTEGER NOT NULL DEFAULT 0,
+	units INTEGER NOT NULL DEFAULT 0,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (day, endpoint)
+);
+
+-- playlist_item_count is the size of the channel's uploads playlist when a
+-- sync last read it, so a backfill's cost can be projected without calling
+-- the API.
+ALTER TABLE sync_state ADD COLUMN playlist_item_count INTEGER;
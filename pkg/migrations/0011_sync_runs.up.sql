@@ -0,0 +1,35 @@
+-- sync_runs records every sync of a channel: what started it, how it went
+-- and how much YouTube quota it spent. A run is running until it finishes,
+-- then succeeded or failed; a failed run's error says why. The counts are
+-- those of model.SyncResult, and are kept for failed runs too.
+CREATE TABLE IF NOT EXISTS sync_runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	channel_id TEXT NOT NULL,
+	trigger_source TEXT NOT NULL,
+	mode TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'running',
+	started_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	finished_at DATETIME,
+	added INTEGER NOT NULL DEFAULT 0,
+	updated INTEGER NOT NULL DEFAULT 0,
+	skipped INTEGER NOT NULL DEFAULT 0,
+	errors INTEGER NOT NULL DEFAULT 0,
+	total INTEGER NOT NULL DEFAULT 0,
+	complete BOOLEAN NOT NULL DEFAULT 0,
+	deferred BOOLEAN NOT NULL DEFAULT 0,
+	quota_units INTEGER NOT NULL DEFAULT 0,
+	error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_runs_started_at ON sync_runs(started_at);
+CREATE INDEX IF NOT EXISTS idx_sync_runs_channel_id ON sync_runs(channel_id, started_at);
+
+-- sync_run_errors holds the videos a run failed to store or update.
+CREATE TABLE IF NOT EXISTS sync_run_errors (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id INTEGER NOT NULL REFERENCES sync_runs(id) ON DELETE CASCADE,
+	video_id TEXT NOT NULL,
+	message TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_run_errors_run_id ON sync_run_errors(run_id);
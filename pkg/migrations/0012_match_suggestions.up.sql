@@ -0,0 +1,20 @@
+-- match_suggestions holds the games the matcher found for a video but
+-- wasn't sure enough of to assign, for someone to review. The score runs
+-- from 0 to 1; the source says how the game was found: by the title parser
+-- among the stored games, or by searching IGDB. A game found on IGDB isn't
+-- stored until it is assigned, so its name and URL are kept here.
+CREATE TABLE IF NOT EXISTS match_suggestions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	video_id TEXT NOT NULL,
+	game_id INTEGER NOT NULL,
+	game_name TEXT NOT NULL,
+	game_url TEXT NOT NULL,
+	score REAL NOT NULL,
+	source TEXT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (video_id, game_id),
+	FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE,
+	CHECK (score >= 0 AND score <= 1)
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_suggestions_score ON match_suggestions(score);
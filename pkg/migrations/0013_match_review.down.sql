@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_match_suggestions_status;
+
+ALTER TABLE match_suggestions DROP COLUMN reviewed_at;
+ALTER TABLE match_suggestions DROP COLUMN status;
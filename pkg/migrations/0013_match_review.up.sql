@@ -0,0 +1,7 @@
+-- A match suggestion is pending until someone reviews it. An accepted one
+-- has put its game on the video. A rejected one is kept, so that the
+-- matcher never suggests or assigns that game for the video again.
+ALTER TABLE match_suggestions ADD COLUMN status TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE match_suggestions ADD COLUMN reviewed_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_match_suggestions_status ON match_suggestions(status, score);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_videos_series_id;
+
+ALTER TABLE videos DROP COLUMN episode;
+ALTER TABLE videos DROP COLUMN series_id;
+
+DROP TABLE IF EXISTS series;
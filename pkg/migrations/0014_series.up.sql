@@ -0,0 +1,19 @@
+-- series groups the episodes of a playthrough: videos of a channel whose
+-- titles number them as episodes of the same name, such as "Elden Ring
+-- EP.1" and "[Elden Ring] EP.2 บอสโหดมาก". normalized_name is the name the
+-- matcher compares; name is as it appeared in the first episode found.
+-- Channel-less videos have the channel ''.
+CREATE TABLE IF NOT EXISTS series (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	channel_id TEXT NOT NULL DEFAULT '',
+	normalized_name TEXT NOT NULL,
+	name TEXT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (channel_id, normalized_name)
+);
+
+-- A video's series and its episode number in it.
+ALTER TABLE videos ADD COLUMN series_id INTEGER;
+ALTER TABLE videos ADD COLUMN episode INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_videos_series_id ON videos(series_id, episode);
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS game_alternative_names;
+DROP TABLE IF EXISTS game_companies;
+DROP TABLE IF EXISTS game_platforms;
+DROP TABLE IF EXISTS game_themes;
+DROP TABLE IF EXISTS game_genres;
+DROP TABLE IF EXISTS companies;
+DROP TABLE IF EXISTS platforms;
+DROP TABLE IF EXISTS themes;
+DROP TABLE IF EXISTS genres;
+
+ALTER TABLE games DROP COLUMN metadata_updated_at;
+ALTER TABLE games DROP COLUMN rating_count;
+ALTER TABLE games DROP COLUMN rating;
+ALTER TABLE games DROP COLUMN first_release_date;
+ALTER TABLE games DROP COLUMN cover_url;
+ALTER TABLE games DROP COLUMN summary;
@@ -0,0 +1,87 @@
+-- Metadata of games from IGDB. first_release_date is the earliest release
+-- on any platform; rating is IGDB's total rating, from 0 to 100, of
+-- rating_count critic and user ratings. metadata_updated_at is when the
+-- metadata was last fetched, NULL for games it never was.
+ALTER TABLE games ADD COLUMN summary TEXT;
+ALTER TABLE games ADD COLUMN cover_url TEXT;
+ALTER TABLE games ADD COLUMN first_release_date DATETIME;
+ALTER TABLE games ADD COLUMN rating REAL;
+ALTER TABLE games ADD COLUMN rating_count INTEGER;
+ALTER TABLE games ADD COLUMN metadata_updated_at DATETIME;
+
+-- Genres, themes, platforms and companies are shared by games, and keep
+-- their IGDB IDs like games do.
+CREATE TABLE IF NOT EXISTS genres (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS themes (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS platforms (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	abbreviation TEXT
+);
+
+CREATE TABLE IF NOT EXISTS companies (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS game_genres (
+	game_id INTEGER NOT NULL,
+	genre_id INTEGER NOT NULL,
+	PRIMARY KEY (game_id, genre_id),
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+	FOREIGN KEY (genre_id) REFERENCES genres(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_genres_genre_id ON game_genres(genre_id);
+
+CREATE TABLE IF NOT EXISTS game_themes (
+	game_id INTEGER NOT NULL,
+	theme_id INTEGER NOT NULL,
+	PRIMARY KEY (game_id, theme_id),
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+	FOREIGN KEY (theme_id) REFERENCES themes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_themes_theme_id ON game_themes(theme_id);
+
+CREATE TABLE IF NOT EXISTS game_platforms (
+	game_id INTEGER NOT NULL,
+	platform_id INTEGER NOT NULL,
+	PRIMARY KEY (game_id, platform_id),
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+	FOREIGN KEY (platform_id) REFERENCES platforms(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_platforms_platform_id ON game_platforms(platform_id);
+
+-- A company can have developed a game, published it, or both.
+CREATE TABLE IF NOT EXISTS game_companies (
+	game_id INTEGER NOT NULL,
+	company_id INTEGER NOT NULL,
+	developer BOOLEAN NOT NULL DEFAULT 0,
+	publisher BOOLEAN NOT NULL DEFAULT 0,
+	PRIMARY KEY (game_id, company_id),
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+	FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_companies_company_id ON game_companies(company_id);
+
+-- Other names a game goes by, such as abbreviations and regional titles.
+-- comment says which, as IGDB has it: "Acronym", "Japanese title".
+CREATE TABLE IF NOT EXISTS game_alternative_names (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	game_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	comment TEXT,
+	UNIQUE (game_id, name),
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
@@ -0,0 +1,155 @@
+package migrations_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/K0ng2/zeedzad/db"
+	"github.com/K0ng2/zeedzad/db/d1test"
+	"github.com/K0ng2/zeedzad/migrations"
+)
+
+func backends(t *testing.T) map[string]func() db.Database {
+	return map[string]func() db.Database{
+		"sqlite": func() db.Database {
+			database, err := db.NewSQLiteDatabase(":memory:")
+			if err != nil {
+				t.Fatalf("NewSQLiteDatabase: %v", err)
+			}
+			t.Cleanup(func() { database.Close() })
+			return database
+		},
+		"d1": func() db.Database {
+			srv := d1test.NewServer(t)
+			database := srv.NewDatabase(t)
+
+			// d1test starts fully migrated; start from scratch instead.
+			m, err := migrations.New(srv.SQLite)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			for {
+				reverted, err := m.Down(context.Background())
+				if err != nil {
+					t.Fatalf("Down: %v", err)
+				}
+				if reverted == nil {
+					break
+				}
+			}
+			return database
+		},
+	}
+}
+
+func TestUpDownStatus(t *testing.T) {
+	ctx := context.Background()
+
+	for name, open := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			database := open()
+
+			m, err := migrations.New(database)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			pending, err := m.Pending(ctx)
+			if err != nil {
+				t.Fatalf("Pending: %v", err)
+			}
+			if len(pending) == 0 {
+				t.Fatal("expected pending migrations on an empty database")
+			}
+
+			applied, err := m.Up(ctx)
+			if err != nil {
+				t.Fatalf("Up: %v", err)
+			}
+			if len(applied) != len(pending) {
+				t.Fatalf("applied %d migrations, want %d", len(applied), len(pending))
+			}
+
+			if _, err := database.Conn().ExecContext(ctx, "INSERT INTO games (id, name, url) VALUES (1, 'a', 'b')"); err != nil {
+				t.Fatalf("schema not usable after Up: %v", err)
+			}
+
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				t.Fatalf("Status: %v", err)
+			}
+			for _, s := range statuses {
+				if s.AppliedAt == nil {
+					t.Errorf("migration %04d_%s not applied", s.Version, s.Name)
+				}
+			}
+
+			again, err := m.Up(ctx)
+			if err != nil || len(again) != 0 {
+				t.Fatalf("second Up = %v, %v, want nothing to do", again, err)
+			}
+
+			last := statuses[len(statuses)-1]
+			reverted, err := m.Down(ctx)
+			if err != nil {
+				t.Fatalf("Down: %v", err)
+			}
+			if reverted == nil || reverted.Version != last.Version {
+				t.Fatalf("reverted %+v, want version %d", reverted, last.Version)
+			}
+
+			pending, err = m.Pending(ctx)
+			if err != nil {
+				t.Fatalf("Pending: %v", err)
+			}
+			if len(pending) != 1 || pending[0].Version != last.Version {
+				t.Fatalf("pending = %+v, want only version %d", pending, last.Version)
+			}
+		})
+	}
+}
+
+func TestVideoGamesBackfill(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := db.NewSQLiteDatabase(":memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteDatabase: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	m, err := migrations.New(database)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	for {
+		reverted, err := m.Down(ctx)
+		if err != nil {
+			t.Fatalf("Down: %v", err)
+		}
+		if reverted == nil || reverted.Version == 2 {
+			break
+		}
+	}
+
+	if _, err := database.Conn().ExecContext(ctx, `INSERT INTO games (id, name, url) VALUES (1, 'a', 'b');
+		INSERT INTO videos (id, title, published_at, game_id) VALUES ('v1', 't', '2024-01-01', 1), ('v2', 't', '2024-01-01', NULL)`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	var videoID string
+	var gameID, n int
+	row := database.Conn().QueryRowContext(ctx, "SELECT video_id, game_id, (SELECT COUNT(*) FROM video_games) FROM video_games")
+	if err := row.Scan(&videoID, &gameID, &n); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if videoID != "v1" || gameID != 1 || n != 1 {
+		t.Fatalf("video_games = %s/%d (%d rows), want only v1/1", videoID, gameID, n)
+	}
+}
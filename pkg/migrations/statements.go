@@ -0,0 +1,126 @@
+package migrations
+
+import (
+	"strings"
+	"unicode"
+)
+
+// splitStatements splits a migration script into individual statements,
+// since D1 batches take one statement per entry. Semicolons inside string
+// literals, quoted identifiers, comments and CREATE TRIGGER bodies do not
+// end a statement. Comment-only fragments are dropped.
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		start      int
+		words      []string // first words of the current statement
+		inTrigger  bool
+		depth      int // BEGIN/CASE nesting inside a trigger body
+	)
+
+	flush := func(end int) {
+		if stmt := strings.TrimSpace(script[start:end]); hasCode(stmt) {
+			statements = append(statements, stmt)
+		}
+		start = end + 1
+		words = words[:0]
+		inTrigger = false
+		depth = 0
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipUntil(script, i, c)
+		case c == '[':
+			i = skipUntil(script, i, ']')
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			i = skipLineComment(script, i)
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			i = skipBlockComment(script, i)
+		case c == ';':
+			if depth == 0 {
+				flush(i)
+			}
+		case isWordByte(c):
+			end := i
+			for end < len(script) && isWordByte(script[end]) {
+				end++
+			}
+			word := strings.ToUpper(script[i:end])
+			i = end - 1
+
+			if len(words) < 4 {
+				words = append(words, word)
+				if words[0] == "CREATE" && word == "TRIGGER" {
+					inTrigger = true
+				}
+			}
+
+			if inTrigger {
+				switch word {
+				case "BEGIN", "CASE":
+					depth++
+				case "END":
+					if depth > 0 {
+						depth--
+					}
+				}
+			}
+		}
+	}
+	flush(len(script))
+
+	return statements
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c < 0x80 && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)))
+}
+
+// hasCode reports whether stmt contains anything besides comments.
+func hasCode(stmt string) bool {
+	for i := 0; i < len(stmt); i++ {
+		switch {
+		case strings.HasPrefix(stmt[i:], "--"):
+			i = skipLineComment(stmt, i)
+		case strings.HasPrefix(stmt[i:], "/*"):
+			i = skipBlockComment(stmt, i)
+		case !unicode.IsSpace(rune(stmt[i])):
+			return true
+		}
+	}
+	return false
+}
+
+// skipUntil returns the offset of the quote closing the section opened at
+// start. A doubled closing quote is an escape.
+func skipUntil(s string, start int, closing byte) int {
+	for i := start + 1; i < len(s); i++ {
+		if s[i] != closing {
+			continue
+		}
+		if closing != ']' && i+1 < len(s) && s[i+1] == closing {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
+
+func skipLineComment(s string, start int) int {
+	if end := strings.IndexByte(s[start:], '\n'); end >= 0 {
+		return start + end
+	}
+	return len(s)
+}
+
+func skipBlockComment(s string, start int) int {
+	if end := strings.Index(s[start+2:], "*/"); end >= 0 {
+		return start + 2 + end + 1
+	}
+	return len(s)
+}
@@ -0,0 +1,110 @@
+package migrations
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "simple",
+			script: "CREATE TABLE a (id INTEGER);\nCREATE INDEX i ON a(id);\n",
+			want:   []string{"CREATE TABLE a (id INTEGER)", "CREATE INDEX i ON a(id)"},
+		},
+		{
+			name:   "no trailing semicolon",
+			script: "DROP TABLE a",
+			want:   []string{"DROP TABLE a"},
+		},
+		{
+			name:   "semicolons in literals and comments",
+			script: "-- drop; everything\nINSERT INTO a VALUES ('x;y', \"c;d\", [e;f]); /* ; */ SELECT 1;",
+			want:   []string{"-- drop; everything\nINSERT INTO a VALUES ('x;y', \"c;d\", [e;f])", "/* ; */ SELECT 1"},
+		},
+		{
+			name:   "comment only fragments are dropped",
+			script: "SELECT 1;\n-- trailing comment\n",
+			want:   []string{"SELECT 1"},
+		},
+		{
+			name: "trigger body",
+			script: `CREATE TRIGGER IF NOT EXISTS t AFTER INSERT ON a BEGIN
+	INSERT INTO b VALUES (CASE WHEN new.id > 0 THEN 1 ELSE 0 END);
+	DELETE FROM c;
+END;
+SELECT 2;`,
+			want: []string{`CREATE TRIGGER IF NOT EXISTS t AFTER INSERT ON a BEGIN
+	INSERT INTO b VALUES (CASE WHEN new.id > 0 THEN 1 ELSE 0 END);
+	DELETE FROM c;
+END`, "SELECT 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_second.up.sql":   {Data: []byte("up 2")},
+		"0002_second.down.sql": {Data: []byte("down 2")},
+		"0001_first.up.sql":    {Data: []byte("up 1")},
+		"0001_first.down.sql":  {Data: []byte("down 1")},
+	}
+
+	got, err := load(fsys)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	want := []Migration{
+		{Version: 1, Name: "first", Up: "up 1", Down: "down 1"},
+		{Version: 2, Name: "second", Up: "up 2", Down: "down 2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadRejectsInvalidSets(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing down": {
+			"0001_first.up.sql": {Data: []byte("up")},
+		},
+		"bad name": {
+			"first.up.sql": {Data: []byte("up")},
+		},
+		"conflicting names": {
+			"0001_first.up.sql":   {Data: []byte("up")},
+			"0001_other.down.sql": {Data: []byte("down")},
+		},
+	}
+
+	for name, fsys := range tests {
+		if _, err := load(fsys); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := load(files)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %s has version %d, want %d", m.Name, m.Version, i+1)
+		}
+	}
+}
@@ -10,6 +10,8 @@ type Meta struct {
 	Total  int64 `json:"total"`
 	Limit  int64 `json:"limit"`
 	Offset int64 `json:"offset"`
+	// Facets is only set on lists of videos.
+	Facets *VideoFacets `json:"facets,omitempty"`
 }
 
 type Offset struct {
@@ -17,6 +19,24 @@ type Offset struct {
 	Offset int64 `query:"offset,default:0"`
 }
 
+// Search is a full-text search query. Every term must match: words match
+// anywhere in a title or name, ignoring case and accents, and "quoted
+// phrases" must match as a whole. Ranked searches are ordered by relevance
+// and report how each result matched.
+type Search struct {
+	Query  string
+	Ranked bool
+}
+
+// SearchMatch describes how a result matched a ranked search. Rank is a
+// BM25 score where lower is more relevant. Highlights holds the matching
+// fields, HTML-escaped, with matches wrapped in <mark> tags; long fields are
+// cut down to a snippet around the match.
+type SearchMatch struct {
+	Rank       float64           `json:"rank"`
+	Highlights map[string]string `json:"highlights"`
+}
+
 type APIResponse[T any] struct {
 	Data T     `json:"data"`
 	Meta *Meta `json:"meta,omitempty"` // omitted if nil
@@ -34,14 +54,129 @@ type Error struct {
 }
 
 // Video related models
+
+// Live statuses of a video.
+const (
+	LiveStatusNone      = "none"
+	LiveStatusUpcoming  = "upcoming"
+	LiveStatusLive      = "live"
+	LiveStatusCompleted = "completed"
+)
+
+// Statuses of a video on YouTube. Videos that were made private or removed
+// are kept, but hidden from the video list by default.
+const (
+	VideoStatusAvailable = "available"
+	VideoStatusPrivate   = "private"
+	VideoStatusRemoved   = "removed"
+)
+
+// States of a channel's WebSub subscription. A subscription is pending until
+// the hub verifies it, and denied if the hub refused it.
+const (
+	PushStatusPending = "pending"
+	PushStatusActive  = "active"
+	PushStatusDenied  = "denied"
+)
+
 type VideoResponse struct {
 	ID          string    `json:"id"`
 	Title       string    `json:"title"`
+	Description *string   `json:"description"`
 	Thumbnail   *string   `json:"thumbnail"`
 	PublishedAt time.Time `json:"published_at"`
-	Game        *GameInfo `json:"game"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ChannelID   *string   `json:"channel_id"`
+	Status      string    `json:"status"`
+	// SeriesID and Episode place the video in a series; nil if its title
+	// numbers no episode.
+	SeriesID *int64 `json:"series_id"`
+	Episode  *int32 `json:"episode"`
+
+	// Details from videos.list; nil until the sync has looked the video up.
+	DurationSeconds *int32   `json:"duration_seconds"`
+	ViewCount       *int64   `json:"view_count"`
+	LikeCount       *int64   `json:"like_count"`
+	CommentCount    *int64   `json:"comment_count"`
+	Tags            []string `json:"tags"`
+	CategoryID      *string  `json:"category_id"`
+	// LiveStatus is "none" for regular uploads, or "upcoming", "live" or
+	// "completed" for live streams.
+	LiveStatus      *string `json:"live_status"`
+	DefaultLanguage *string `json:"default_language"`
+
+	// Game is the primary game, the first entry of Games.
+	Game      *GameInfo   `json:"game"`
+	Games     []VideoGame `json:"games"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+
+	// Match is set on the results of a ranked search.
+	Match *SearchMatch `json:"match,omitempty"`
+}
+
+// VideoFilter selects videos to list.
+type VideoFilter struct {
+	Search    Search
+	ChannelID string
+	// MinDuration and MaxDuration bound the duration in seconds; zero
+	// means no bound.
+	MinDuration int
+	MaxDuration int
+	// IsLive selects live streams (upcoming, live or completed) if true
+	// and regular uploads if false.
+	IsLive *bool
+	// IncludeUnavailable includes videos that were made private or removed.
+	IncludeUnavailable bool
+	// GenreID, PlatformID and DeveloperID select videos with a game of that
+	// IGDB genre, platform or developer; zero means any.
+	GenreID     int64
+	PlatformID  int64
+	DeveloperID int64
+	// MinReleaseYear and MaxReleaseYear bound the year a game of the video
+	// was first released in; zero means no bound.
+	MinReleaseYear int
+	MaxReleaseYear int
+	// Matched selects videos with a game if true and videos without one if
+	// false.
+	Matched *bool
+}
+
+// VideoFacets counts the videos a filter finds by each value of the game
+// filters. The counts for a filter leave that filter out, so they tell how
+// many videos each of its other values would find.
+type VideoFacets struct {
+	Genres       []Facet            `json:"genres"`
+	Platforms    []Facet            `json:"platforms"`
+	Developers   []Facet            `json:"developers"`
+	ReleaseYears []ReleaseYearFacet `json:"release_years"`
+	Matched      MatchedFacet       `json:"matched"`
+}
+
+// Facet is how many videos have a game of a genre, platform or developer,
+// by its IGDB ID.
+type Facet struct {
+	ID    int32  `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type ReleaseYearFacet struct {
+	Year  int   `json:"year"`
+	Count int64 `json:"count"`
+}
+
+type MatchedFacet struct {
+	Matched   int64 `json:"matched"`
+	Unmatched int64 `json:"unmatched"`
+}
+
+// VideoEdit is a change the sync made to a video.
+type VideoEdit struct {
+	// Field is "title", "thumbnail" or "status".
+	Field     string    `json:"field"`
+	OldValue  *string   `json:"old_value"`
+	NewValue  *string   `json:"new_value"`
+	ChangedAt time.Time `json:"changed_at"`
 }
 
 type GameInfo struct {
@@ -54,13 +189,81 @@ type UpdateVideoGameRequest struct {
 	GameID int64 `json:"game_id"`
 }
 
+// VideoGame is a game covered by a video, optionally limited to a segment of
+// it given in seconds from the start.
+type VideoGame struct {
+	GameInfo
+	Position     int32  `json:"position"`
+	StartSeconds *int32 `json:"start_seconds"`
+	EndSeconds   *int32 `json:"end_seconds"`
+}
+
+type AddVideoGameRequest struct {
+	GameID       int64  `json:"game_id"`
+	StartSeconds *int32 `json:"start_seconds"`
+	EndSeconds   *int32 `json:"end_seconds"`
+}
+
+type ReorderVideoGamesRequest struct {
+	GameIDs []int64 `json:"game_ids"`
+}
+
 // Game related models
 type GameResponse struct {
-	ID        int32     `json:"id"`
-	Name      string    `json:"name"`
-	URL       *string   `json:"url"`
+	ID   int32   `json:"id"`
+	Name string  `json:"name"`
+	URL  *string `json:"url"`
+
+	// Metadata from IGDB; nil until it has been fetched, at
+	// MetadataUpdatedAt. Rating is IGDB's total rating, from 0 to 100, of
+	// RatingCount critic and user ratings.
+	Summary           *string    `json:"summary"`
+	CoverURL          *string    `json:"cover_url"`
+	FirstReleaseDate  *time.Time `json:"first_release_date"`
+	Rating            *float64   `json:"rating"`
+	RatingCount       *int32     `json:"rating_count"`
+	MetadataUpdatedAt *time.Time `json:"metadata_updated_at"`
+
+	// The related metadata is set on a single game.
+	Genres           []GameTerm            `json:"genres,omitempty"`
+	Themes           []GameTerm            `json:"themes,omitempty"`
+	Platforms        []GamePlatform        `json:"platforms,omitempty"`
+	Developers       []GameTerm            `json:"developers,omitempty"`
+	Publishers       []GameTerm            `json:"publishers,omitempty"`
+	AlternativeNames []GameAlternativeName `json:"alternative_names,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Match is set on the results of a ranked search.
+	Match *SearchMatch `json:"match,omitempty"`
+}
+
+// GameTerm is a genre, theme or company of a game, by its IGDB ID.
+type GameTerm struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+// GamePlatform is a platform a game was released on, by its IGDB ID.
+type GamePlatform struct {
+	ID           int32   `json:"id"`
+	Name         string  `json:"name"`
+	Abbreviation *string `json:"abbreviation"`
+}
+
+// GameAlternativeName is another name a game goes by. Comment says which,
+// such as "Acronym" or "Japanese title".
+type GameAlternativeName struct {
+	Name    string  `json:"name"`
+	Comment *string `json:"comment"`
+}
+
+// GameEnrichResult counts the games whose metadata was fetched from IGDB,
+// and lists those IGDB doesn't know.
+type GameEnrichResult struct {
+	Enriched int     `json:"enriched"`
+	NotFound []int64 `json:"not_found"`
 }
 
 type CreateGameRequest struct {
@@ -69,6 +272,44 @@ type CreateGameRequest struct {
 	URL  *string `json:"url"`
 }
 
+// Channel related models
+type ChannelResponse struct {
+	ID                string  `json:"id"`
+	Handle            *string `json:"handle"`
+	Title             string  `json:"title"`
+	Thumbnail         *string `json:"thumbnail"`
+	UploadsPlaylistID string  `json:"uploads_playlist_id"`
+	// SyncSchedule is a cron expression; channels without one are synced on
+	// the default schedule.
+	SyncSchedule *string    `json:"sync_schedule"`
+	SyncEnabled  bool       `json:"sync_enabled"`
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+	// NextSyncAt is when the scheduled sync runs next, if it is scheduled.
+	NextSyncAt *time.Time `json:"next_sync_at"`
+	// PushStatus is the state of the channel's WebSub subscription, nil if
+	// it isn't subscribed.
+	PushStatus    *string    `json:"push_status"`
+	PushExpiresAt *time.Time `json:"push_expires_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// CreateChannelRequest registers a channel. Channel is a channel ID, an
+// @handle, or a channel URL.
+type CreateChannelRequest struct {
+	Channel      string  `json:"channel"`
+	SyncSchedule *string `json:"sync_schedule"`
+	SyncEnabled  *bool   `json:"sync_enabled"`
+}
+
+// UpdateChannelRequest changes how a channel is synced. Omitted fields are
+// left as they are; an empty sync_schedule returns the channel to the
+// default schedule.
+type UpdateChannelRequest struct {
+	SyncSchedule *string `json:"sync_schedule"`
+	SyncEnabled  *bool   `json:"sync_enabled"`
+}
+
 // IGDB API response
 type IGDBGameSearchResult struct {
 	ID   int64  `json:"id"`
@@ -76,10 +317,232 @@ type IGDBGameSearchResult struct {
 	URL  string `json:"url"`
 }
 
+// How the matcher found a game for a video: by reading a game name from
+// its title and finding it among the stored games, by searching IGDB for
+// that name, or from the games of the other episodes of its series.
+const (
+	MatchSourceTitle  = "title"
+	MatchSourceIGDB   = "igdb"
+	MatchSourceSeries = "series"
+)
+
+// States of a match suggestion. An accepted suggestion has put its game on
+// the video; a rejected one is kept so that the matcher never proposes that
+// game for the video again.
+const (
+	MatchSuggestionPending  = "pending"
+	MatchSuggestionAccepted = "accepted"
+	MatchSuggestionRejected = "rejected"
+)
+
+// MatchSuggestion is a game the matcher suggested for a video, scored from
+// 0 to 1. A suggested game may not be in the catalogue yet; accepting the
+// suggestion adds it.
+type MatchSuggestion struct {
+	ID         int64      `json:"id"`
+	VideoID    string     `json:"video_id"`
+	VideoTitle string     `json:"video_title"`
+	Game       GameInfo   `json:"game"`
+	Score      float64    `json:"score"`
+	Source     string     `json:"source"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+}
+
+// MatchSuggestionFilter narrows the match suggestions. Empty fields match
+// every suggestion.
+type MatchSuggestionFilter struct {
+	VideoID  string
+	Source   string
+	Status   string
+	MinScore float64
+}
+
+// ReviewMatchSuggestionsRequest accepts and rejects several match
+// suggestions at once.
+type ReviewMatchSuggestionsRequest struct {
+	Accept []int64 `json:"accept"`
+	Reject []int64 `json:"reject"`
+}
+
+// MatchReviewResult lists the suggestions a review accepted and rejected.
+// Suggestions that weren't pending are left out.
+type MatchReviewResult struct {
+	Accepted []int64 `json:"accepted"`
+	Rejected []int64 `json:"rejected"`
+}
+
+// Series is a playthrough: the videos of a channel whose titles number
+// them as episodes of the same name.
+type Series struct {
+	ID        int64  `json:"id"`
+	ChannelID string `json:"channel_id"`
+	Name      string `json:"name"`
+	// EpisodeCount counts its videos, and MatchedCount those with a game.
+	EpisodeCount int64     `json:"episode_count"`
+	MatchedCount int64     `json:"matched_count"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Episodes are set on a single series, in episode order.
+	Episodes []VideoResponse `json:"episodes,omitempty"`
+}
+
+// SeriesFilter narrows the series. Empty fields match every series.
+type SeriesFilter struct {
+	ChannelID string
+}
+
+// SeriesInferResult counts what inferring series did: the videos it put
+// in a series, and the games it assigned to and suggested for episodes
+// from the other episodes of their series.
+type SeriesInferResult struct {
+	Grouped   int `json:"grouped"`
+	Assigned  int `json:"assigned"`
+	Suggested int `json:"suggested"`
+}
+
 // Sync result
 type SyncResult struct {
-	Added   int `json:"added"`
+	Mode  string `json:"mode"`
+	Added int    `json:"added"`
+	// Updated counts stored videos that were renamed, given a new
+	// thumbnail, made private or removed, or became available again.
+	Updated int `json:"updated"`
 	Skipped int `json:"skipped"`
 	Errors  int `json:"errors"`
 	Total   int `json:"total"`
+	// Complete is set when an incremental sync caught up with the previous
+	// one, or a backfill reached the end of the playlist.
+	Complete bool `json:"complete"`
+	// Deferred is set when the sync stopped because the day's YouTube
+	// quota budget ran out. The next sync after the reset continues.
+	Deferred bool `json:"deferred"`
+	// RunIDs are the sync runs the sync was recorded as, one per channel.
+	RunIDs []int64 `json:"run_ids"`
+}
+
+// What started a sync run: a sync API call, a channel's sync schedule, or
+// a WebSub notification.
+const (
+	SyncTriggerManual   = "manual"
+	SyncTriggerSchedule = "schedule"
+	SyncTriggerPush     = "push"
+)
+
+// States of a sync run or sync job. A cancelled one was stopped by a
+// DELETE of its job.
+const (
+	SyncRunRunning   = "running"
+	SyncRunSucceeded = "succeeded"
+	SyncRunFailed    = "failed"
+	SyncRunCancelled = "cancelled"
+)
+
+// SyncRun is a recorded sync of a channel. The counts are those of its
+// SyncResult; a failed run keeps the counts it reached, and Error says why
+// it failed.
+type SyncRun struct {
+	ID         int64      `json:"id"`
+	ChannelID  string     `json:"channel_id"`
+	Trigger    string     `json:"trigger"`
+	Mode       string     `json:"mode"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Added      int        `json:"added"`
+	Updated    int        `json:"updated"`
+	Skipped    int        `json:"skipped"`
+	Errors     int        `json:"errors"`
+	Total      int        `json:"total"`
+	Complete   bool       `json:"complete"`
+	Deferred   bool       `json:"deferred"`
+	// QuotaUnits is the YouTube quota the run spent.
+	QuotaUnits int     `json:"quota_units"`
+	Error      *string `json:"error"`
+	// VideoErrors are the videos the run failed to store or update. They
+	// are only listed for a single run.
+	VideoErrors []SyncRunError `json:"video_errors,omitempty"`
+}
+
+type SyncRunError struct {
+	VideoID string `json:"video_id"`
+	Message string `json:"message"`
+}
+
+// SyncJob is a sync running in the background, or one that finished
+// recently. Progress counts the videos so far, and the runs recorded so
+// far; once the job is done it is the sync's result.
+type SyncJob struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	ChannelIDs []string   `json:"channel_ids"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Progress   SyncResult `json:"progress"`
+	Error      *string    `json:"error"`
+}
+
+// SyncRunFilter narrows the sync history. Empty fields match every run.
+type SyncRunFilter struct {
+	ChannelID string
+	Trigger   string
+	Status    string
+}
+
+// VideoStatsPoint is a snapshot of a video's counts. The gains are the
+// change since the previous snapshot; they are nil on the first one.
+type VideoStatsPoint struct {
+	CapturedAt   time.Time `json:"captured_at"`
+	ViewCount    *int64    `json:"view_count"`
+	LikeCount    *int64    `json:"like_count"`
+	CommentCount *int64    `json:"comment_count"`
+	ViewsGained  *int64    `json:"views_gained"`
+	LikesGained  *int64    `json:"likes_gained"`
+}
+
+// Stats refresh result
+type StatsRefreshResult struct {
+	// Checked is the number of videos that were due for a refresh.
+	Checked int `json:"checked"`
+	Updated int `json:"updated"`
+	// Missing is the number of videos YouTube returned no stats for. They
+	// are marked removed.
+	Missing int `json:"missing"`
+}
+
+// QuotaUsage is the YouTube Data API quota spent on a day, a date in
+// Pacific time.
+type QuotaUsage struct {
+	Day       string               `json:"day"`
+	Budget    int                  `json:"budget"`
+	Used      int                  `json:"used"`
+	Remaining int                  `json:"remaining"`
+	ResetsAt  time.Time            `json:"resets_at"`
+	Endpoints []QuotaEndpointUsage `json:"endpoints"`
+	// Backfill is the projected cost of a backfill, if one was asked for.
+	Backfill *BackfillProjection `json:"backfill,omitempty"`
+}
+
+type QuotaEndpointUsage struct {
+	Endpoint string `json:"endpoint"`
+	Calls    int    `json:"calls"`
+	Units    int    `json:"units"`
+}
+
+// BackfillProjection estimates the quota a backfill of a channel would
+// spend.
+type BackfillProjection struct {
+	ChannelID string `json:"channel_id"`
+	// Videos is the number of items in the channel's uploads playlist when
+	// a sync last read it, or of its stored videos if none has.
+	Videos int `json:"videos"`
+	// Remaining is the number of items left to read; a stopped backfill
+	// resumes after the ones it has seen.
+	Remaining int `json:"remaining"`
+	Pages     int `json:"pages"`
+	Units     int `json:"units"`
+	// FitsToday is set if the backfill fits in what is left of today's
+	// budget.
+	FitsToday bool `json:"fits_today"`
 }
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+type ChannelWithSyncState struct {
+	repoModel.Channels
+	SyncState    *repoModel.SyncState           `alias:"sync_state"`
+	Subscription *repoModel.WebsubSubscriptions `alias:"websub_subscriptions"`
+}
+
+func selectChannels() sqlite.SelectStatement {
+	return sqlite.SELECT(
+		Channels.AllColumns,
+		SyncState.ChannelID.AS("sync_state.channel_id"),
+		SyncState.LastSyncedAt.AS("sync_state.last_synced_at"),
+		WebsubSubscriptions.ChannelID.AS("websub_subscriptions.channel_id"),
+		WebsubSubscriptions.Status.AS("websub_subscriptions.status"),
+		WebsubSubscriptions.ExpiresAt.AS("websub_subscriptions.expires_at"),
+	).FROM(
+		Channels.
+			LEFT_JOIN(SyncState, SyncState.ChannelID.EQ(Channels.ID)).
+			LEFT_JOIN(WebsubSubscriptions, WebsubSubscriptions.ChannelID.EQ(Channels.ID)),
+	)
+}
+
+func (r *Repository) GetChannels(ctx context.Context) ([]model.ChannelResponse, error) {
+	var channels []ChannelWithSyncState
+
+	stmt := selectChannels().ORDER_BY(Channels.Title.ASC())
+
+	err := stmt.QueryContext(ctx, r.ex, &channels)
+	if err != nil {
+		return nil, FormatError("get channels", err)
+	}
+
+	return convertToChannelResponses(channels), nil
+}
+
+func (r *Repository) GetChannelByID(ctx context.Context, id string) (*model.ChannelResponse, error) {
+	var channel ChannelWithSyncState
+
+	stmt := selectChannels().WHERE(Channels.ID.EQ(sqlite.String(id)))
+
+	err := stmt.QueryContext(ctx, r.ex, &channel)
+	if err != nil {
+		return nil, FormatError("get channel by id", err)
+	}
+
+	responses := convertToChannelResponses([]ChannelWithSyncState{channel})
+	if len(responses) == 0 {
+		return nil, FormatError("get channel by id", err)
+	}
+
+	return &responses[0], nil
+}
+
+func (r *Repository) CreateChannel(ctx context.Context, channel repoModel.Channels) error {
+	stmt := Channels.INSERT(
+		Channels.ID,
+		Channels.Handle,
+		Channels.Title,
+		Channels.Thumbnail,
+		Channels.UploadsPlaylistID,
+		Channels.SyncSchedule,
+		Channels.SyncEnabled,
+		Channels.CreatedAt,
+		Channels.UpdatedAt,
+	).
+		VALUES(
+			channel.ID,
+			channel.Handle,
+			channel.Title,
+			channel.Thumbnail,
+			channel.UploadsPlaylistID,
+			channel.SyncSchedule,
+			channel.SyncEnabled,
+			time.Now(),
+			time.Now(),
+		)
+
+	_, err := stmt.ExecContext(ctx, r.ex)
+	if err != nil {
+		return FormatError("create channel", err)
+	}
+
+	return nil
+}
+
+// UpdateChannel changes the sync settings of a channel. Nil fields are left
+// as they are; an empty schedule is stored as NULL.
+func (r *Repository) UpdateChannel(ctx context.Context, id string, req model.UpdateChannelRequest) error {
+	columns := sqlite.ColumnList{Channels.UpdatedAt}
+	values := []any{time.Now()}
+
+	if req.SyncSchedule != nil {
+		columns = append(columns, Channels.SyncSchedule)
+		if *req.SyncSchedule == "" {
+			values = append(values, sqlite.NULL)
+		} else {
+			values = append(values, *req.SyncSchedule)
+		}
+	}
+	if req.SyncEnabled != nil {
+		columns = append(columns, Channels.SyncEnabled)
+		values = append(values, *req.SyncEnabled)
+	}
+
+	stmt := Channels.UPDATE(columns).
+		SET(values[0], values[1:]...).
+		WHERE(Channels.ID.EQ(sqlite.String(id)))
+
+	_, err := stmt.ExecContext(ctx, r.ex)
+	if err != nil {
+		return FormatError("update channel", err)
+	}
+
+	return nil
+}
+
+// DeleteChannel removes a channel and its sync progress. Its videos are
+// kept, no longer linked to a channel.
+func (r *Repository) DeleteChannel(ctx context.Context, id string) error {
+	return r.Transaction(ctx, func(tx *Repository) error {
+		_, err := SyncState.DELETE().
+			WHERE(SyncState.ChannelID.EQ(sqlite.String(id))).
+			ExecContext(ctx, tx.ex)
+		if err != nil {
+			return FormatError("delete channel", err)
+		}
+
+		_, err = Channels.DELETE().
+			WHERE(Channels.ID.EQ(sqlite.String(id))).
+			ExecContext(ctx, tx.ex)
+		if err != nil {
+			return FormatError("delete channel", err)
+		}
+
+		return nil
+	})
+}
+
+func convertToChannelResponses(channels []ChannelWithSyncState) []model.ChannelResponse {
+	responses := make([]model.ChannelResponse, 0, len(channels))
+
+	for _, c := range channels {
+		if c.ID == nil {
+			continue
+		}
+
+		response := model.ChannelResponse{
+			ID:                *c.ID,
+			Handle:            c.Handle,
+			Title:             c.Title,
+			Thumbnail:         c.Thumbnail,
+			UploadsPlaylistID: c.UploadsPlaylistID,
+			SyncSchedule:      c.SyncSchedule,
+			SyncEnabled:       c.SyncEnabled,
+			CreatedAt:         c.CreatedAt,
+			UpdatedAt:         c.UpdatedAt,
+		}
+
+		if c.SyncState != nil {
+			response.LastSyncedAt = c.SyncState.LastSyncedAt
+		}
+		if c.Subscription != nil {
+			response.PushStatus = &c.Subscription.Status
+			response.PushExpiresAt = c.Subscription.ExpiresAt
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses
+}
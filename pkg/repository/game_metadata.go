@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// Batch sizes keeping a statement about game metadata under D1's limit of
+// 100 bound parameters a statement.
+const (
+	gameTermBatchSize     = 45
+	gamePlatformBatchSize = 30
+	gameCompanyBatchSize  = 22
+	gameLinkBatchSize     = 45
+	gameNameBatchSize     = 30
+	gameIDBatchSize       = 90
+)
+
+// GameMetadata is the metadata of a game from IGDB. Game holds its ID and
+// the metadata columns of games; the rest replaces what was stored.
+type GameMetadata struct {
+	Game             repoModel.Games
+	Genres           []repoModel.Genres
+	Themes           []repoModel.Themes
+	Platforms        []repoModel.Platforms
+	Companies        []repoModel.Companies
+	GameCompanies    []repoModel.GameCompanies
+	AlternativeNames []repoModel.GameAlternativeNames
+}
+
+// GetGameIDsWithoutMetadata returns the IDs of the games whose metadata
+// was never fetched. If ids isn't nil, only those among them are returned.
+func (r *Repository) GetGameIDsWithoutMetadata(ctx context.Context, ids []int64) ([]int64, error) {
+	var conditions []sqlite.BoolExpression
+	if ids == nil {
+		conditions = append(conditions, Games.MetadataUpdatedAt.IS_NULL())
+	}
+	for batch := range slices.Chunk(ids, gameIDBatchSize) {
+		conditions = append(conditions, Games.MetadataUpdatedAt.IS_NULL().AND(Games.ID.IN(intList(batch)...)))
+	}
+
+	var missing []int64
+	for _, condition := range conditions {
+		var games []repoModel.Games
+
+		stmt := sqlite.SELECT(Games.ID).
+			FROM(Games).
+			WHERE(condition).
+			ORDER_BY(Games.ID.ASC())
+
+		if err := stmt.QueryContext(ctx, r.ex, &games); err != nil {
+			return nil, FormatError("get game ids without metadata", err)
+		}
+
+		for _, game := range games {
+			missing = append(missing, int64(*game.ID))
+		}
+	}
+
+	return missing, nil
+}
+
+// SaveGameMetadata stores the metadata of games in one transaction. The
+// genres, themes, platforms and companies they reference are added, or
+// renamed as IGDB now names them; the games' links to them and their
+// alternative names are replaced.
+func (r *Repository) SaveGameMetadata(ctx context.Context, metadata []GameMetadata) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	var genres []repoModel.Genres
+	var themes []repoModel.Themes
+	var platforms []repoModel.Platforms
+	var companies []repoModel.Companies
+	var gameGenres []repoModel.GameGenres
+	var gameThemes []repoModel.GameThemes
+	var gamePlatforms []repoModel.GamePlatforms
+	var gameCompanies []repoModel.GameCompanies
+	var alternativeNames []repoModel.GameAlternativeNames
+
+	gameIDs := make([]int64, len(metadata))
+	for i, m := range metadata {
+		gameID := *m.Game.ID
+		gameIDs[i] = int64(gameID)
+
+		for _, genre := range m.Genres {
+			if !slices.ContainsFunc(genres, func(g repoModel.Genres) bool { return *g.ID == *genre.ID }) {
+				genres = append(genres, genre)
+			}
+			gameGenres = append(gameGenres, repoModel.GameGenres{GameID: gameID, GenreID: *genre.ID})
+		}
+		for _, theme := range m.Themes {
+			if !slices.ContainsFunc(themes, func(t repoModel.Themes) bool { return *t.ID == *theme.ID }) {
+				themes = append(themes, theme)
+			}
+			gameThemes = append(gameThemes, repoModel.GameThemes{GameID: gameID, ThemeID: *theme.ID})
+		}
+		for _, platform := range m.Platforms {
+			if !slices.ContainsFunc(platforms, func(p repoModel.Platforms) bool { return *p.ID == *platform.ID }) {
+				platforms = append(platforms, platform)
+			}
+			gamePlatforms = append(gamePlatforms, repoModel.GamePlatforms{GameID: gameID, PlatformID: *platform.ID})
+		}
+		for _, company := range m.Companies {
+			if !slices.ContainsFunc(companies, func(c repoModel.Companies) bool { return *c.ID == *company.ID }) {
+				companies = append(companies, company)
+			}
+		}
+		gameCompanies = append(gameCompanies, m.GameCompanies...)
+		alternativeNames = append(alternativeNames, m.AlternativeNames...)
+	}
+
+	// The related entities go first, then the games' links to them are
+	// replaced.
+	var stmts []sqlite.Statement
+	for batch := range slices.Chunk(genres, gameTermBatchSize) {
+		stmts = append(stmts, Genres.INSERT(Genres.AllColumns).
+			MODELS(batch).
+			ON_CONFLICT(Genres.ID).
+			DO_UPDATE(sqlite.SET(Genres.Name.SET(Genres.EXCLUDED.Name))))
+	}
+	for batch := range slices.Chunk(themes, gameTermBatchSize) {
+		stmts = append(stmts, Themes.INSERT(Themes.AllColumns).
+			MODELS(batch).
+			ON_CONFLICT(Themes.ID).
+			DO_UPDATE(sqlite.SET(Themes.Name.SET(Themes.EXCLUDED.Name))))
+	}
+	for batch := range slices.Chunk(platforms, gamePlatformBatchSize) {
+		stmts = append(stmts, Platforms.INSERT(Platforms.AllColumns).
+			MODELS(batch).
+			ON_CONFLICT(Platforms.ID).
+			DO_UPDATE(sqlite.SET(
+				Platforms.Name.SET(Platforms.EXCLUDED.Name),
+				Platforms.Abbreviation.SET(Platforms.EXCLUDED.Abbreviation),
+			)))
+	}
+	for batch := range slices.Chunk(companies, gameTermBatchSize) {
+		stmts = append(stmts, Companies.INSERT(Companies.AllColumns).
+			MODELS(batch).
+			ON_CONFLICT(Companies.ID).
+			DO_UPDATE(sqlite.SET(Companies.Name.SET(Companies.EXCLUDED.Name))))
+	}
+	for batch := range slices.Chunk(gameIDs, gameIDBatchSize) {
+		ids := intList(batch)
+		stmts = append(stmts,
+			GameGenres.DELETE().WHERE(GameGenres.GameID.IN(ids...)),
+			GameThemes.DELETE().WHERE(GameThemes.GameID.IN(ids...)),
+			GamePlatforms.DELETE().WHERE(GamePlatforms.GameID.IN(ids...)),
+			GameCompanies.DELETE().WHERE(GameCompanies.GameID.IN(ids...)),
+			GameAlternativeNames.DELETE().WHERE(GameAlternativeNames.GameID.IN(ids...)),
+		)
+	}
+	for batch := range slices.Chunk(gameGenres, gameLinkBatchSize) {
+		stmts = append(stmts, GameGenres.INSERT(GameGenres.AllColumns).
+			MODELS(batch).
+			ON_CONFLICT(GameGenres.GameID, GameGenres.GenreID).
+			DO_NOTHING())
+	}
+	for batch := range slices.Chunk(gameThemes, gameLinkBatchSize) {
+		stmts = append(stmts, GameThemes.INSERT(GameThemes.AllColumns).
+			MODELS(batch).
+			ON_CONFLICT(GameThemes.GameID, GameThemes.ThemeID).
+			DO_NOTHING())
+	}
+	for batch := range slices.Chunk(gamePlatforms, gameLinkBatchSize) {
+		stmts = append(stmts, GamePlatforms.INSERT(GamePlatforms.AllColumns).
+			MODELS(batch).
+			ON_CONFLICT(GamePlatforms.GameID, GamePlatforms.PlatformID).
+			DO_NOTHING())
+	}
+	for batch := range slices.Chunk(gameCompanies, gameCompanyBatchSize) {
+		stmts = append(stmts, GameCompanies.INSERT(GameCompanies.AllColumns).
+			MODELS(batch).
+			ON_CONFLICT(GameCompanies.GameID, GameCompanies.CompanyID).
+			DO_NOTHING())
+	}
+	for batch := range slices.Chunk(alternativeNames, gameNameBatchSize) {
+		stmts = append(stmts, GameAlternativeNames.INSERT(GameAlternativeNames.GameID, GameAlternativeNames.Name, GameAlternativeNames.Comment).
+			MODELS(batch).
+			ON_CONFLICT(GameAlternativeNames.GameID, GameAlternativeNames.Name).
+			DO_NOTHING())
+	}
+
+	return r.Transaction(ctx, func(tx *Repository) error {
+		now := time.Now()
+
+		for _, m := range metadata {
+			stmt := Games.UPDATE(Games.Summary, Games.CoverURL, Games.FirstReleaseDate, Games.Rating, Games.RatingCount, Games.MetadataUpdatedAt, Games.UpdatedAt).
+				SET(m.Game.Summary, m.Game.CoverURL, m.Game.FirstReleaseDate, m.Game.Rating, m.Game.RatingCount, now, now).
+				WHERE(Games.ID.EQ(sqlite.Int32(*m.Game.ID)))
+
+			if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("save game metadata", err)
+			}
+		}
+
+		for _, stmt := range stmts {
+			if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("save game metadata", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Kinds of the rows selectGameTerms returns.
+const (
+	gameTermGenre           = "genre"
+	gameTermTheme           = "theme"
+	gameTermPlatform        = "platform"
+	gameTermCompany         = "company"
+	gameTermAlternativeName = "alternative_name"
+)
+
+// gameTerm is a genre, theme, platform, company or alternative name of a
+// game. Detail is the abbreviation of a platform, or the comment on an
+// alternative name.
+type gameTerm struct {
+	Kind      string  `alias:"game_term.kind"`
+	ID        int32   `alias:"game_term.id"`
+	Name      string  `alias:"game_term.name"`
+	Detail    *string `alias:"game_term.detail"`
+	Developer bool    `alias:"game_term.developer"`
+	Publisher bool    `alias:"game_term.publisher"`
+}
+
+// selectGameTerms selects the related metadata of a game in one query,
+// rather than a round trip a table.
+func selectGameTerms(gameID int32) sqlite.Statement {
+	id := sqlite.Int32(gameID)
+	noDetail := sqlite.StringExp(sqlite.NULL).AS("game_term.detail")
+	no := sqlite.Bool(false)
+
+	return sqlite.UNION_ALL(
+		sqlite.SELECT(
+			sqlite.String(gameTermGenre).AS("game_term.kind"),
+			Genres.ID.AS("game_term.id"),
+			Genres.Name.AS("game_term.name"),
+			noDetail,
+			no.AS("game_term.developer"),
+			no.AS("game_term.publisher"),
+		).
+			FROM(Genres.INNER_JOIN(GameGenres, GameGenres.GenreID.EQ(Genres.ID))).
+			WHERE(GameGenres.GameID.EQ(id)),
+		sqlite.SELECT(
+			sqlite.String(gameTermTheme),
+			Themes.ID,
+			Themes.Name,
+			sqlite.NULL,
+			no,
+			no,
+		).
+			FROM(Themes.INNER_JOIN(GameThemes, GameThemes.ThemeID.EQ(Themes.ID))).
+			WHERE(GameThemes.GameID.EQ(id)),
+		sqlite.SELECT(
+			sqlite.String(gameTermPlatform),
+			Platforms.ID,
+			Platforms.Name,
+			Platforms.Abbreviation,
+			no,
+			no,
+		).
+			FROM(Platforms.INNER_JOIN(GamePlatforms, GamePlatforms.PlatformID.EQ(Platforms.ID))).
+			WHERE(GamePlatforms.GameID.EQ(id)),
+		sqlite.SELECT(
+			sqlite.String(gameTermCompany),
+			Companies.ID,
+			Companies.Name,
+			sqlite.NULL,
+			GameCompanies.Developer,
+			GameCompanies.Publisher,
+		).
+			FROM(Companies.INNER_JOIN(GameCompanies, GameCompanies.CompanyID.EQ(Companies.ID))).
+			WHERE(GameCompanies.GameID.EQ(id)),
+		sqlite.SELECT(
+			sqlite.String(gameTermAlternativeName),
+			GameAlternativeNames.ID,
+			GameAlternativeNames.Name,
+			GameAlternativeNames.Comment,
+			no,
+			no,
+		).
+			FROM(GameAlternativeNames).
+			WHERE(GameAlternativeNames.GameID.EQ(id)),
+	)
+}
+
+// attachGameMetadata sets the genres, themes, platforms, companies and
+// alternative names of a game, each by name.
+func (r *Repository) attachGameMetadata(ctx context.Context, game *model.GameResponse) error {
+	var terms []gameTerm
+
+	if err := selectGameTerms(game.ID).QueryContext(ctx, r.ex, &terms); err != nil {
+		return FormatError("get game metadata", err)
+	}
+
+	slices.SortStableFunc(terms, func(a, b gameTerm) int { return strings.Compare(a.Name, b.Name) })
+
+	for _, term := range terms {
+		switch term.Kind {
+		case gameTermGenre:
+			game.Genres = append(game.Genres, model.GameTerm{ID: term.ID, Name: term.Name})
+		case gameTermTheme:
+			game.Themes = append(game.Themes, model.GameTerm{ID: term.ID, Name: term.Name})
+		case gameTermPlatform:
+			game.Platforms = append(game.Platforms, model.GamePlatform{ID: term.ID, Name: term.Name, Abbreviation: term.Detail})
+		case gameTermCompany:
+			if term.Developer {
+				game.Developers = append(game.Developers, model.GameTerm{ID: term.ID, Name: term.Name})
+			}
+			if term.Publisher {
+				game.Publishers = append(game.Publishers, model.GameTerm{ID: term.ID, Name: term.Name})
+			}
+		case gameTermAlternativeName:
+			game.AlternativeNames = append(game.AlternativeNames, model.GameAlternativeName{Name: term.Name, Comment: term.Detail})
+		}
+	}
+
+	return nil
+}
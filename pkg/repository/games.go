@@ -11,22 +11,63 @@ import (
 	. "github.com/K0ng2/zeedzad/repository/table"
 )
 
-func selectGames() sqlite.SelectStatement {
-	return sqlite.SELECT(Games.AllColumns).FROM(Games)
+type GameWithMatch struct {
+	repoModel.Games
+	Match searchMatch `alias:"match"`
 }
 
-func (r *Repository) GetGames(ctx context.Context, query model.Offset, search string) ([]model.GameResponse, error) {
-	var games []repoModel.Games
+var gameRank = sqlite.RawFloat("bm25(games_fts)")
 
-	stmt := selectGames()
+func gameSearchTable(terms searchTerms) sqlite.ReadableTable {
+	if terms.empty() {
+		return Games
+	}
+	return Games.INNER_JOIN(GamesFts, sqlite.RawBool("games_fts.rowid = games.id"))
+}
+
+func selectGames(terms searchTerms, ranked bool) sqlite.SelectStatement {
+	var columns []sqlite.Projection
 
-	if search != "" {
-		searchPattern := sqlite.String("%" + search + "%")
-		stmt = stmt.WHERE(Games.Name.LIKE(searchPattern))
+	if ranked && terms.match != "" {
+		columns = append(columns,
+			gameRank.AS("match.rank"),
+			ftsHighlight("games_fts", 0, false).AS("match.name"),
+		)
+	} else if ranked {
+		columns = append(columns, sqlite.Float(0).AS("match.rank"))
+	}
+
+	return sqlite.SELECT(Games.AllColumns, columns...).FROM(gameSearchTable(terms))
+}
+
+func gameSearchExpression(terms searchTerms) *sqlite.BoolExpression {
+	if terms.empty() {
+		return nil
+	}
+
+	exp := ftsExpression("games_fts", terms, GamesFts.Name)
+	return &exp
+}
+
+// GetGames lists games by name. Ranked searches are ordered by relevance
+// instead.
+func (r *Repository) GetGames(ctx context.Context, query model.Offset, search model.Search) ([]model.GameResponse, error) {
+	var games []GameWithMatch
+
+	terms := parseSearch(search.Query)
+	stmt := selectGames(terms, search.Ranked)
+
+	if exp := gameSearchExpression(terms); exp != nil {
+		stmt = stmt.WHERE(*exp)
+	}
+
+	if search.Ranked && terms.match != "" {
+		stmt = stmt.ORDER_BY(gameRank.ASC(), Games.Name.ASC())
+	} else {
+		stmt = stmt.ORDER_BY(Games.Name.ASC())
 	}
 
 	stmt = stmt.
-		ORDER_BY(Games.Name.ASC()).
 		LIMIT(query.Limit).
 		OFFSET(query.Offset)
 
@@ -39,33 +80,34 @@ func (r *Repository) GetGames(ctx context.Context, query model.Offset, search st
 }
 
 func (r *Repository) GetGameByID(ctx context.Context, id int64) (*model.GameResponse, error) {
-	var game repoModel.Games
+	var game GameWithMatch
 
-	stmt := selectGames().WHERE(Games.ID.EQ(sqlite.Int(id)))
+	stmt := selectGames(searchTerms{}, false).WHERE(Games.ID.EQ(sqlite.Int(id)))
 
 	err := stmt.QueryContext(ctx, r.ex, &game)
 	if err != nil {
 		return nil, FormatError("get game by id", err)
 	}
 
-	responses := convertToGameResponses([]repoModel.Games{game})
+	responses := convertToGameResponses([]GameWithMatch{game})
 	if len(responses) == 0 {
 		return nil, FormatError("get game by id", err)
 	}
 
+	// A game that was never enriched has no genres, companies and such.
+	if responses[0].MetadataUpdatedAt != nil {
+		if err := r.attachGameMetadata(ctx, &responses[0]); err != nil {
+			return nil, err
+		}
+	}
+
 	return &responses[0], nil
 }
 
-func (r *Repository) GetGameTotalItems(ctx context.Context, search string) (int64, error) {
-	var expression *sqlite.BoolExpression
-
-	if search != "" {
-		searchPattern := sqlite.String("%" + search + "%")
-		exp := Games.Name.LIKE(searchPattern)
-		expression = &exp
-	}
+func (r *Repository) GetGameTotalItems(ctx context.Context, search model.Search) (int64, error) {
+	terms := parseSearch(search.Query)
 
-	return TotalItems(ctx, r.ex, Games.ID, Games, expression)
+	return TotalItems(ctx, r.ex, Games.ID, gameSearchTable(terms), gameSearchExpression(terms))
 }
 
 func (r *Repository) CreateGame(ctx context.Context, req model.CreateGameRequest) (int64, error) {
@@ -91,7 +133,7 @@ func (r *Repository) CreateGame(ctx context.Context, req model.CreateGameRequest
 	return req.ID, nil
 }
 
-func convertToGameResponses(games []repoModel.Games) []model.GameResponse {
+func convertToGameResponses(games []GameWithMatch) []model.GameResponse {
 	responses := make([]model.GameResponse, 0, len(games))
 
 	for _, g := range games {
@@ -100,11 +142,18 @@ func convertToGameResponses(games []repoModel.Games) []model.GameResponse {
 		}
 
 		responses = append(responses, model.GameResponse{
-			ID:        *g.ID,
-			Name:      g.Name,
-			URL:       &g.URL,
-			CreatedAt: g.CreatedAt,
-			UpdatedAt: g.UpdatedAt,
+			ID:                *g.ID,
+			Name:              g.Name,
+			URL:               &g.URL,
+			Summary:           g.Summary,
+			CoverURL:          g.CoverURL,
+			FirstReleaseDate:  g.FirstReleaseDate,
+			Rating:            g.Rating,
+			RatingCount:       g.RatingCount,
+			MetadataUpdatedAt: g.MetadataUpdatedAt,
+			CreatedAt:         g.CreatedAt,
+			UpdatedAt:         g.UpdatedAt,
+			Match:             g.Match.response(),
 		})
 	}
 
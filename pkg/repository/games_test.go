@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+func TestCreateGameAndGetByID(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 1020, "grand-theft-auto-v")
+
+	game, err := r.GetGameByID(ctx, 1020)
+	if err != nil {
+		t.Fatalf("GetGameByID: %v", err)
+	}
+	if game.ID != 1020 || game.Name != "grand-theft-auto-v" {
+		t.Fatalf("game = %d %q, want 1020 \"grand-theft-auto-v\"", game.ID, game.Name)
+	}
+	if game.CreatedAt.IsZero() {
+		t.Fatal("created_at was not scanned")
+	}
+}
+
+func TestGetGamesSearchAndPagination(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 1, "dark-souls")
+	createTestGame(t, r, 2, "dark-souls-ii")
+	createTestGame(t, r, 3, "hollow-knight")
+
+	games, err := r.GetGames(ctx, model.Offset{Limit: 1, Offset: 1}, model.Search{Query: "dark"})
+	if err != nil {
+		t.Fatalf("GetGames: %v", err)
+	}
+	if len(games) != 1 || games[0].Name != "dark-souls-ii" {
+		t.Fatalf("games = %+v, want only dark-souls-ii", games)
+	}
+
+	total, err := r.GetGameTotalItems(ctx, model.Search{Query: "dark"})
+	if err != nil {
+		t.Fatalf("GetGameTotalItems: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("total = %d, want 2", total)
+	}
+}
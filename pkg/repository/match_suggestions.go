@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// Batch sizes keeping a statement about games or match suggestions under
+// D1's limit of 100 bound parameters a statement.
+const (
+	gameBatchSize            = 20
+	matchSuggestionBatchSize = 14
+	matchReviewBatchSize     = 90
+)
+
+// matchSuggestionColumns are the columns the matcher fills in.
+var matchSuggestionColumns = sqlite.ColumnList{
+	MatchSuggestions.VideoID,
+	MatchSuggestions.GameID,
+	MatchSuggestions.GameName,
+	MatchSuggestions.GameURL,
+	MatchSuggestions.Score,
+	MatchSuggestions.Source,
+}
+
+// GetAllGames returns the ID, name and URL of every game, for the matcher
+// to compare video titles with.
+func (r *Repository) GetAllGames(ctx context.Context) ([]repoModel.Games, error) {
+	var games []repoModel.Games
+
+	stmt := sqlite.SELECT(Games.ID, Games.Name, Games.URL).
+		FROM(Games).
+		ORDER_BY(Games.ID.ASC())
+
+	if err := stmt.QueryContext(ctx, r.ex, &games); err != nil {
+		return nil, FormatError("get all games", err)
+	}
+
+	return games, nil
+}
+
+// SaveMatches stores what the matcher found for a page of videos, in one
+// transaction: the games it assigned, which are added to the catalogue
+// first if they aren't in it yet, and the suggestions it made. A video
+// already suggested a game keeps its first suggestion of it.
+func (r *Repository) SaveMatches(ctx context.Context, games []repoModel.Games, assigned []repoModel.VideoGames, suggestions []repoModel.MatchSuggestions) error {
+	if len(assigned) == 0 && len(suggestions) == 0 {
+		return nil
+	}
+
+	return r.Transaction(ctx, func(tx *Repository) error {
+		now := time.Now()
+
+		for batch := range slices.Chunk(games, gameBatchSize) {
+			insert := Games.INSERT(Games.ID, Games.Name, Games.URL, Games.CreatedAt, Games.UpdatedAt)
+			for _, game := range batch {
+				insert = insert.VALUES(game.ID, game.Name, game.URL, now, now)
+			}
+			insert = insert.ON_CONFLICT(Games.ID).DO_NOTHING()
+
+			if _, err := insert.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("save matches", err)
+			}
+		}
+
+		for _, match := range assigned {
+			if err := tx.UpdateVideoGame(ctx, match.VideoID, int64(match.GameID)); err != nil {
+				return err
+			}
+		}
+
+		for batch := range slices.Chunk(suggestions, matchSuggestionBatchSize) {
+			insert := MatchSuggestions.INSERT(matchSuggestionColumns).
+				MODELS(batch).
+				ON_CONFLICT(MatchSuggestions.VideoID, MatchSuggestions.GameID).
+				DO_NOTHING()
+
+			if _, err := insert.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("save matches", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+type matchSuggestionWithVideo struct {
+	repoModel.MatchSuggestions
+	Video repoModel.Videos `alias:"video"`
+}
+
+func matchSuggestionFilterExpression(filter model.MatchSuggestionFilter) *sqlite.BoolExpression {
+	var conditions []sqlite.BoolExpression
+
+	if filter.VideoID != "" {
+		conditions = append(conditions, MatchSuggestions.VideoID.EQ(sqlite.String(filter.VideoID)))
+	}
+	if filter.Source != "" {
+		conditions = append(conditions, MatchSuggestions.Source.EQ(sqlite.String(filter.Source)))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, MatchSuggestions.Status.EQ(sqlite.String(filter.Status)))
+	}
+	if filter.MinScore > 0 {
+		conditions = append(conditions, MatchSuggestions.Score.GT_EQ(sqlite.Float(filter.MinScore)))
+	}
+
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	exp := sqlite.AND(conditions...)
+	return &exp
+}
+
+func selectMatchSuggestions() sqlite.SelectStatement {
+	return sqlite.SELECT(
+		MatchSuggestions.AllColumns,
+		Videos.ID.AS("video.id"),
+		Videos.Title.AS("video.title"),
+	).FROM(
+		MatchSuggestions.INNER_JOIN(Videos, Videos.ID.EQ(MatchSuggestions.VideoID)),
+	)
+}
+
+// GetMatchSuggestions lists match suggestions, best first.
+func (r *Repository) GetMatchSuggestions(ctx context.Context, query model.Offset, filter model.MatchSuggestionFilter) ([]model.MatchSuggestion, error) {
+	var rows []matchSuggestionWithVideo
+
+	stmt := selectMatchSuggestions()
+
+	if exp := matchSuggestionFilterExpression(filter); exp != nil {
+		stmt = stmt.WHERE(*exp)
+	}
+
+	stmt = stmt.
+		ORDER_BY(MatchSuggestions.Score.DESC(), MatchSuggestions.ID.ASC()).
+		LIMIT(query.Limit).
+		OFFSET(query.Offset)
+
+	err := stmt.QueryContext(ctx, r.ex, &rows)
+	if err != nil {
+		return nil, FormatError("get match suggestions", err)
+	}
+
+	suggestions := make([]model.MatchSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = convertToMatchSuggestion(row)
+	}
+
+	return suggestions, nil
+}
+
+func (r *Repository) GetMatchSuggestionTotalItems(ctx context.Context, filter model.MatchSuggestionFilter) (int64, error) {
+	return TotalItems(ctx, r.ex, MatchSuggestions.ID, MatchSuggestions, matchSuggestionFilterExpression(filter))
+}
+
+func (r *Repository) GetMatchSuggestionByID(ctx context.Context, id int64) (*model.MatchSuggestion, error) {
+	var row matchSuggestionWithVideo
+
+	stmt := selectMatchSuggestions().WHERE(MatchSuggestions.ID.EQ(sqlite.Int(id)))
+
+	err := stmt.QueryContext(ctx, r.ex, &row)
+	if err != nil {
+		return nil, FormatError("get match suggestion by id", err)
+	}
+
+	suggestion := convertToMatchSuggestion(row)
+	return &suggestion, nil
+}
+
+// ReviewMatchSuggestions accepts and rejects match suggestions in one
+// transaction. Accepting a suggestion adds its game to the end of the
+// video's games, adding it to the catalogue first if it isn't in it yet.
+// Only pending suggestions are reviewed; a suggestion in both lists is
+// accepted.
+func (r *Repository) ReviewMatchSuggestions(ctx context.Context, accept, reject []int64) (model.MatchReviewResult, error) {
+	result := model.MatchReviewResult{Accepted: []int64{}, Rejected: []int64{}}
+
+	pending, err := r.getPendingMatchSuggestions(ctx, slices.Concat(accept, reject))
+	if err != nil {
+		return result, err
+	}
+
+	var accepted []repoModel.MatchSuggestions
+	for _, id := range accept {
+		if suggestion, ok := pending[id]; ok {
+			accepted = append(accepted, suggestion)
+			result.Accepted = append(result.Accepted, id)
+			delete(pending, id)
+		}
+	}
+	for _, id := range reject {
+		if _, ok := pending[id]; ok {
+			result.Rejected = append(result.Rejected, id)
+			delete(pending, id)
+		}
+	}
+
+	if len(result.Accepted) == 0 && len(result.Rejected) == 0 {
+		return result, nil
+	}
+
+	err = r.Transaction(ctx, func(tx *Repository) error {
+		now := time.Now()
+
+		for _, suggestion := range accepted {
+			game := Games.INSERT(Games.ID, Games.Name, Games.URL, Games.CreatedAt, Games.UpdatedAt).
+				VALUES(suggestion.GameID, suggestion.GameName, suggestion.GameURL, now, now).
+				ON_CONFLICT(Games.ID).
+				DO_NOTHING()
+
+			if _, err := game.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("review match suggestions", err)
+			}
+
+			nextPosition := sqlite.IntExp(
+				sqlite.SELECT(
+					sqlite.COALESCE(sqlite.MAXi(VideoGames.Position).ADD(sqlite.Int(1)), sqlite.Int(0)),
+				).FROM(VideoGames).WHERE(VideoGames.VideoID.EQ(sqlite.String(suggestion.VideoID))),
+			)
+
+			videoGame := VideoGames.INSERT(VideoGames.VideoID, VideoGames.GameID, VideoGames.Position).
+				VALUES(suggestion.VideoID, suggestion.GameID, nextPosition).
+				ON_CONFLICT(VideoGames.VideoID, VideoGames.GameID).
+				DO_NOTHING()
+
+			if _, err := videoGame.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("review match suggestions", err)
+			}
+
+			if err := tx.syncPrimaryGame(ctx, suggestion.VideoID); err != nil {
+				return err
+			}
+		}
+
+		reviews := []struct {
+			status string
+			ids    []int64
+		}{
+			{model.MatchSuggestionAccepted, result.Accepted},
+			{model.MatchSuggestionRejected, result.Rejected},
+		}
+		for _, review := range reviews {
+			for batch := range slices.Chunk(review.ids, matchReviewBatchSize) {
+				stmt := MatchSuggestions.UPDATE(MatchSuggestions.Status, MatchSuggestions.ReviewedAt).
+					SET(review.status, now).
+					WHERE(
+						MatchSuggestions.ID.IN(intList(batch)...).
+							AND(MatchSuggestions.Status.EQ(sqlite.String(model.MatchSuggestionPending))),
+					)
+
+				if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+					return FormatError("review match suggestions", err)
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.MatchReviewResult{Accepted: []int64{}, Rejected: []int64{}}, err
+	}
+
+	return result, nil
+}
+
+// getPendingMatchSuggestions returns the pending suggestions among ids, by
+// ID.
+func (r *Repository) getPendingMatchSuggestions(ctx context.Context, ids []int64) (map[int64]repoModel.MatchSuggestions, error) {
+	pending := make(map[int64]repoModel.MatchSuggestions, len(ids))
+
+	for batch := range slices.Chunk(ids, matchReviewBatchSize) {
+		var suggestions []repoModel.MatchSuggestions
+
+		stmt := sqlite.SELECT(MatchSuggestions.AllColumns).
+			FROM(MatchSuggestions).
+			WHERE(
+				MatchSuggestions.ID.IN(intList(batch)...).
+					AND(MatchSuggestions.Status.EQ(sqlite.String(model.MatchSuggestionPending))),
+			)
+
+		if err := stmt.QueryContext(ctx, r.ex, &suggestions); err != nil {
+			return nil, FormatError("get pending match suggestions", err)
+		}
+
+		for _, suggestion := range suggestions {
+			pending[int64(*suggestion.ID)] = suggestion
+		}
+	}
+
+	return pending, nil
+}
+
+// GetMatchSuggestionsByIDs returns the suggestions in ids.
+func (r *Repository) GetMatchSuggestionsByIDs(ctx context.Context, ids []int64) ([]repoModel.MatchSuggestions, error) {
+	var suggestions []repoModel.MatchSuggestions
+
+	for batch := range slices.Chunk(ids, matchReviewBatchSize) {
+		var found []repoModel.MatchSuggestions
+
+		stmt := sqlite.SELECT(MatchSuggestions.AllColumns).
+			FROM(MatchSuggestions).
+			WHERE(MatchSuggestions.ID.IN(intList(batch)...))
+
+		if err := stmt.QueryContext(ctx, r.ex, &found); err != nil {
+			return nil, FormatError("get match suggestions by ids", err)
+		}
+
+		suggestions = append(suggestions, found...)
+	}
+
+	return suggestions, nil
+}
+
+// GetRejectedMatches returns the games rejected for each video in
+// videoIDs.
+func (r *Repository) GetRejectedMatches(ctx context.Context, videoIDs []string) (map[string][]int32, error) {
+	return r.getSuggestedGames(ctx, videoIDs, model.MatchSuggestionRejected)
+}
+
+// GetPendingMatches returns the games pending review for each video in
+// videoIDs.
+func (r *Repository) GetPendingMatches(ctx context.Context, videoIDs []string) (map[string][]int32, error) {
+	return r.getSuggestedGames(ctx, videoIDs, model.MatchSuggestionPending)
+}
+
+// getSuggestedGames returns the games suggested for each video in
+// videoIDs whose suggestions are in the given state.
+func (r *Repository) getSuggestedGames(ctx context.Context, videoIDs []string, status string) (map[string][]int32, error) {
+	games := make(map[string][]int32)
+
+	for batch := range slices.Chunk(videoIDs, matchReviewBatchSize) {
+		var suggestions []repoModel.MatchSuggestions
+
+		stmt := sqlite.SELECT(MatchSuggestions.ID, MatchSuggestions.VideoID, MatchSuggestions.GameID).
+			FROM(MatchSuggestions).
+			WHERE(
+				MatchSuggestions.VideoID.IN(stringList(batch)...).
+					AND(MatchSuggestions.Status.EQ(sqlite.String(status))),
+			)
+
+		if err := stmt.QueryContext(ctx, r.ex, &suggestions); err != nil {
+			return nil, FormatError("get "+status+" matches", err)
+		}
+
+		for _, suggestion := range suggestions {
+			games[suggestion.VideoID] = append(games[suggestion.VideoID], suggestion.GameID)
+		}
+	}
+
+	return games, nil
+}
+
+func intList(values []int64) []sqlite.Expression {
+	list := make([]sqlite.Expression, len(values))
+	for i, v := range values {
+		list[i] = sqlite.Int(v)
+	}
+	return list
+}
+
+func convertToMatchSuggestion(row matchSuggestionWithVideo) model.MatchSuggestion {
+	return model.MatchSuggestion{
+		ID:         int64(*row.ID),
+		VideoID:    row.VideoID,
+		VideoTitle: row.Video.Title,
+		Game: model.GameInfo{
+			ID:   row.GameID,
+			Name: row.GameName,
+			URL:  &row.GameURL,
+		},
+		Score:      row.Score,
+		Source:     row.Source,
+		Status:     row.Status,
+		CreatedAt:  row.CreatedAt,
+		ReviewedAt: row.ReviewedAt,
+	}
+}
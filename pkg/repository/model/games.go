@@ -12,9 +12,15 @@ import (
 )
 
 type Games struct {
-	ID        *int32    `sql:"primary_key" json:"id"`
-	Name      string    `json:"name"`
-	URL       string    `json:"url"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                *int32     `sql:"primary_key" json:"id"`
+	Name              string     `json:"name"`
+	URL               string     `json:"url"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	Summary           *string    `json:"summary"`
+	CoverURL          *string    `json:"cover_url"`
+	FirstReleaseDate  *time.Time `json:"first_release_date"`
+	Rating            *float64   `json:"rating"`
+	RatingCount       *int32     `json:"rating_count"`
+	MetadataUpdatedAt *time.Time `json:"metadata_updated_at"`
 }
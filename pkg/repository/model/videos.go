@@ -12,11 +12,27 @@ import (
 )
 
 type Videos struct {
-	ID          *string   `sql:"primary_key" json:"id"`
-	Title       string    `json:"title"`
-	Thumbnail   *string   `json:"thumbnail"`
-	PublishedAt time.Time `json:"published_at"`
-	GameID      *int32    `json:"game_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID               *string    `sql:"primary_key" json:"id"`
+	Title            string     `json:"title"`
+	Thumbnail        *string    `json:"thumbnail"`
+	PublishedAt      time.Time  `json:"published_at"`
+	GameID           *int32     `json:"game_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	Description      *string    `json:"description"`
+	ChannelID        *string    `json:"channel_id"`
+	DurationSeconds  *int32     `json:"duration_seconds"`
+	ViewCount        *int64     `json:"view_count"`
+	LikeCount        *int64     `json:"like_count"`
+	CommentCount     *int64     `json:"comment_count"`
+	Tags             *string    `json:"tags"`
+	CategoryID       *string    `json:"category_id"`
+	LiveStatus       *string    `json:"live_status"`
+	DefaultLanguage  *string    `json:"default_language"`
+	DetailsUpdatedAt *time.Time `json:"details_updated_at"`
+	StatsUpdatedAt   *time.Time `json:"stats_updated_at"`
+	Status           string     `json:"status"`
+	LastSeenAt       *time.Time `json:"last_seen_at"`
+	SeriesID         *int32     `json:"series_id"`
+	Episode          *int32     `json:"episode"`
 }
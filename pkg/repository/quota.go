@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// RecordQuotaUsage adds a call to endpoint, costing units, to the ledger of
+// day.
+func (r *Repository) RecordQuotaUsage(ctx context.Context, day, endpoint string, units int32) error {
+	stmt := YoutubeQuota.INSERT(YoutubeQuota.Day, YoutubeQuota.Endpoint, YoutubeQuota.Calls, YoutubeQuota.Units, YoutubeQuota.UpdatedAt).
+		VALUES(day, endpoint, 1, units, time.Now()).
+		ON_CONFLICT(YoutubeQuota.Day, YoutubeQuota.Endpoint).
+		DO_UPDATE(sqlite.SET(
+			YoutubeQuota.Calls.SET(YoutubeQuota.Calls.ADD(sqlite.Int(1))),
+			YoutubeQuota.Units.SET(YoutubeQuota.Units.ADD(YoutubeQuota.EXCLUDED.Units)),
+			YoutubeQuota.UpdatedAt.SET(YoutubeQuota.EXCLUDED.UpdatedAt),
+		))
+
+	if _, err := stmt.ExecContext(ctx, r.ex); err != nil {
+		return FormatError("record quota usage", err)
+	}
+
+	return nil
+}
+
+// GetQuotaUsage returns the ledger of day, one row per endpoint called.
+func (r *Repository) GetQuotaUsage(ctx context.Context, day string) ([]repoModel.YoutubeQuota, error) {
+	usage := []repoModel.YoutubeQuota{}
+
+	stmt := sqlite.SELECT(YoutubeQuota.AllColumns).
+		FROM(YoutubeQuota).
+		WHERE(YoutubeQuota.Day.EQ(sqlite.String(day))).
+		ORDER_BY(YoutubeQuota.Endpoint.ASC())
+
+	err := stmt.QueryContext(ctx, r.ex, &usage)
+	if err != nil {
+		return nil, FormatError("get quota usage", err)
+	}
+
+	return usage, nil
+}
+
+// ChannelVideoCounts counts a channel's stored videos.
+type ChannelVideoCounts struct {
+	Stored      int64
+	WithDetails int64
+	// SeenSince counts the videos a backfill has found since the given
+	// time.
+	SeenSince int64
+}
+
+// CountChannelVideos counts a channel's stored videos, those with details,
+// and those seen by a backfill since the given time, if any.
+func (r *Repository) CountChannelVideos(ctx context.Context, channelID string, since *time.Time) (ChannelVideoCounts, error) {
+	var seen sqlite.Expression = sqlite.Int(0)
+	if since != nil {
+		seen = sqlite.SUM(sqlite.CASE().
+			WHEN(Videos.LastSeenAt.GT_EQ(dateTime(*since))).THEN(sqlite.Int(1)).
+			ELSE(sqlite.Int(0)))
+	}
+
+	var counts struct {
+		Stored      int64  `alias:"counts.stored"`
+		WithDetails int64  `alias:"counts.with_details"`
+		SeenSince   *int64 `alias:"counts.seen_since"`
+	}
+
+	stmt := sqlite.SELECT(
+		sqlite.COUNT(sqlite.STAR).AS("counts.stored"),
+		sqlite.COUNT(Videos.DetailsUpdatedAt).AS("counts.with_details"),
+		seen.AS("counts.seen_since"),
+	).
+		FROM(Videos).
+		WHERE(Videos.ChannelID.EQ(sqlite.String(channelID)))
+
+	err := stmt.QueryContext(ctx, r.ex, &counts)
+	if err != nil {
+		return ChannelVideoCounts{}, FormatError("count channel videos", err)
+	}
+
+	result := ChannelVideoCounts{Stored: counts.Stored, WithDetails: counts.WithDetails}
+	if counts.SeenSince != nil {
+		result.SeenSince = *counts.SeenSince
+	}
+	return result, nil
+}
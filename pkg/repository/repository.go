@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/go-jet/jet/v2/qrm"
 	"github.com/go-jet/jet/v2/sqlite"
 
 	"github.com/K0ng2/zeedzad/db"
@@ -13,11 +15,11 @@ import (
 )
 
 type Repository struct {
-	db *db.Database
+	db db.Database
 	ex db.Executor
 }
 
-func NewRepository(db *db.Database) *Repository {
+func NewRepository(db db.Database) *Repository {
 	return &Repository{db: db, ex: db.Conn()}
 }
 
@@ -28,12 +30,43 @@ func (r *Repository) WithTx(tx *sql.Tx) *Repository {
 	}
 }
 
+// Transaction runs fn against a transactional copy of the repository,
+// committing when fn returns nil and rolling back otherwise. If r is already
+// in a transaction, fn joins it.
+func (r *Repository) Transaction(ctx context.Context, fn func(tx *Repository) error) error {
+	if _, ok := r.ex.(*sql.Tx); ok {
+		return fn(r)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError("begin transaction", err)
+	}
+
+	if err := fn(r.WithTx(tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormatError("commit transaction", err)
+	}
+
+	return nil
+}
+
 func (r *Repository) Ping(ctx context.Context) error {
 	return r.db.PingContext(ctx)
 }
 
+// FormatError prefixes err with the failed operation. The result wraps err,
+// so the db error kinds can be matched with errors.Is; an empty query result
+// is reported as db.ErrNotFound.
 func FormatError(prefix string, err error) error {
-	return fmt.Errorf("%s: %v", prefix, err)
+	if errors.Is(err, qrm.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		err = db.ErrNotFound
+	}
+	return fmt.Errorf("%s: %w", prefix, err)
 }
 
 func NullString(s string) sqlite.StringExpression {
@@ -52,6 +85,14 @@ func NullInt16(i *int) sqlite.IntegerExpression {
 	return sqlite.Int16(int16(*i))
 }
 
+func NullInt32(i *int32) sqlite.IntegerExpression {
+	if i == nil {
+		return sqlite.IntExp(sqlite.NULL)
+	}
+
+	return sqlite.Int32(*i)
+}
+
 func TotalItems(ctx context.Context, exec db.Executor, countColumn sqlite.Expression, table sqlite.ReadableTable, expression *sqlite.BoolExpression) (int64, error) {
 	var count model.INT64
 
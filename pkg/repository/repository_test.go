@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/db/d1test"
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+// newTestRepository returns a repository talking to a fake D1 server.
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	srv := d1test.NewServer(t)
+	return NewRepository(srv.NewDatabase(t))
+}
+
+func createTestGame(t *testing.T, r *Repository, id int64, name string) {
+	t.Helper()
+
+	url := "https://www.igdb.com/games/" + name
+	if _, err := r.CreateGame(context.Background(), model.CreateGameRequest{ID: id, Name: name, URL: &url}); err != nil {
+		t.Fatalf("CreateGame: %v", err)
+	}
+}
+
+func createTestVideo(t *testing.T, r *Repository, id, title string, publishedAt time.Time) {
+	t.Helper()
+
+	if err := r.CreateVideo(context.Background(), repoModel.Videos{ID: &id, Title: title, PublishedAt: publishedAt}); err != nil {
+		t.Fatalf("CreateVideo: %v", err)
+	}
+}
+
+var (
+	hadesURL   = "https://www.igdb.com/games/hades--1"
+	celesteURL = "https://www.igdb.com/games/celeste"
+)
+
+func TestTransactionCommitsAllStatements(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	createTestVideo(t, r, "vid1", "Hades EP.1", time.Now())
+
+	err := r.Transaction(ctx, func(tx *Repository) error {
+		// Attach before the game exists; foreign keys are checked on commit.
+		if err := tx.UpdateVideoGame(ctx, "vid1", 113112); err != nil {
+			return err
+		}
+		_, err := tx.CreateGame(ctx, model.CreateGameRequest{ID: 113112, Name: "Hades", URL: &hadesURL})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+
+	video, err := r.GetVideoByID(ctx, "vid1")
+	if err != nil {
+		t.Fatalf("GetVideoByID: %v", err)
+	}
+	if video.Game == nil || video.Game.ID != 113112 {
+		t.Fatalf("game = %+v, want 113112", video.Game)
+	}
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	createTestVideo(t, r, "vid1", "Hades EP.1", time.Now())
+
+	errAbort := errors.New("abort")
+	err := r.Transaction(ctx, func(tx *Repository) error {
+		if _, err := tx.CreateGame(ctx, model.CreateGameRequest{ID: 113112, Name: "Hades", URL: &hadesURL}); err != nil {
+			return err
+		}
+		if err := tx.UpdateVideoGame(ctx, "vid1", 113112); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Transaction error = %v, want %v", err, errAbort)
+	}
+
+	if _, err := r.GetGameByID(ctx, 113112); err == nil {
+		t.Fatal("game was created despite rollback")
+	}
+}
+
+func TestTransactionFailedCommitAppliesNothing(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	err := r.Transaction(ctx, func(tx *Repository) error {
+		if _, err := tx.CreateGame(ctx, model.CreateGameRequest{ID: 1, Name: "Celeste", URL: &celesteURL}); err != nil {
+			return err
+		}
+		// Duplicate primary key fails the whole batch.
+		_, err := tx.CreateGame(ctx, model.CreateGameRequest{ID: 1, Name: "Celeste", URL: &celesteURL})
+		return err
+	})
+	if err == nil {
+		t.Fatal("expected commit to fail")
+	}
+
+	total, err := r.GetGameTotalItems(ctx, model.Search{})
+	if err != nil {
+		t.Fatalf("GetGameTotalItems: %v", err)
+	}
+	if total != 0 {
+		t.Fatalf("total = %d, want 0", total)
+	}
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+// The full-text indexes use the trigram tokenizer, which can't match
+// anything shorter than three characters.
+const minTrigramTerm = 3
+
+// Highlight markers. These are control characters that can't appear in
+// titles or names, so matches can be marked up after the rest of the text
+// is HTML-escaped.
+const (
+	markStart = "\x02"
+	markEnd   = "\x03"
+)
+
+// searchTerms is a search query split for the full-text index: match is an
+// FTS5 query for the terms the index can handle, short holds the terms it
+// can't, which are matched with LIKE instead.
+type searchTerms struct {
+	match string
+	short []string
+}
+
+func (t searchTerms) empty() bool {
+	return t.match == "" && len(t.short) == 0
+}
+
+// parseSearch splits a search query into terms. "Quoted phrases" are kept
+// together and words are split on whitespace; every term must match. A
+// trailing * marks a prefix, though with trigrams any term already matches
+// anywhere inside a word.
+func parseSearch(search string) searchTerms {
+	var (
+		terms   searchTerms
+		matches []string
+	)
+
+	add := func(term string, prefix bool) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return
+		}
+		if utf8.RuneCountInString(term) < minTrigramTerm {
+			terms.short = append(terms.short, term)
+			return
+		}
+
+		phrase := `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+		if prefix {
+			phrase += "*"
+		}
+		matches = append(matches, phrase)
+	}
+
+	for rest := strings.TrimSpace(search); rest != ""; rest = strings.TrimSpace(rest) {
+		if rest[0] == '"' {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				add(rest[1:], false)
+				break
+			}
+			add(rest[1:end+1], false)
+			rest = rest[end+2:]
+			continue
+		}
+
+		end := strings.IndexFunc(rest, func(r rune) bool { return r == '"' || unicode.IsSpace(r) })
+		if end < 0 {
+			end = len(rest)
+		}
+		word := rest[:end]
+		add(strings.TrimRight(word, "*"), strings.HasSuffix(word, "*"))
+		rest = rest[end:]
+	}
+
+	terms.match = strings.Join(matches, " ")
+	return terms
+}
+
+// ftsExpression filters rows of an FTS5 table by terms. Short terms must
+// appear in at least one of columns.
+func ftsExpression(table string, terms searchTerms, columns ...sqlite.StringExpression) sqlite.BoolExpression {
+	var conditions []sqlite.BoolExpression
+
+	if terms.match != "" {
+		conditions = append(conditions, sqlite.RawBool(table+" MATCH #query", sqlite.RawArgs{"#query": terms.match}))
+	}
+
+	for _, term := range terms.short {
+		pattern := sqlite.String("%" + term + "%")
+
+		matches := make([]sqlite.BoolExpression, len(columns))
+		for i, column := range columns {
+			matches[i] = column.LIKE(pattern)
+		}
+		conditions = append(conditions, sqlite.OR(matches...))
+	}
+
+	return sqlite.AND(conditions...)
+}
+
+// ftsHighlight returns column of an FTS5 table with matches marked. Long
+// columns are cut down to a snippet around the best match.
+func ftsHighlight(table string, column int, snippet bool) sqlite.StringExpression {
+	args := sqlite.RawArgs{"#start": markStart, "#end": markEnd}
+	if snippet {
+		return sqlite.RawString("snippet("+table+", "+strconv.Itoa(column)+", #start, #end, '…', 48)", args)
+	}
+	return sqlite.RawString("highlight("+table+", "+strconv.Itoa(column)+", #start, #end)", args)
+}
+
+// searchMatch is how a row matched a ranked search, as returned by the
+// database. Highlighted columns are nil or unmarked when they didn't match.
+type searchMatch struct {
+	Rank        *float64
+	Title       *string
+	Description *string
+	Games       *string
+	Name        *string
+}
+
+func (m searchMatch) response() *model.SearchMatch {
+	if m.Rank == nil {
+		return nil
+	}
+
+	match := &model.SearchMatch{Rank: *m.Rank, Highlights: map[string]string{}}
+	for field, text := range map[string]*string{
+		"title":       m.Title,
+		"description": m.Description,
+		"games":       m.Games,
+		"name":        m.Name,
+	} {
+		if text != nil && strings.Contains(*text, markStart) {
+			match.Highlights[field] = markHighlights(*text)
+		}
+	}
+
+	return match
+}
+
+// markHighlights HTML-escapes text and wraps its matches in <mark> tags.
+func markHighlights(text string) string {
+	text = html.EscapeString(text)
+	text = strings.ReplaceAll(text, markStart, "<mark>")
+	return strings.ReplaceAll(text, markEnd, "</mark>")
+}
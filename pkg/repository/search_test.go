@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+func TestParseSearch(t *testing.T) {
+	tests := []struct {
+		search string
+		want   searchTerms
+	}{
+		{"", searchTerms{}},
+		{"  elden   ring ", searchTerms{match: `"elden" "ring"`}},
+		{`"elden ring" ep.3`, searchTerms{match: `"elden ring" "ep.3"`}},
+		{"poke* EP 1", searchTerms{match: `"poke"*`, short: []string{"EP", "1"}}},
+		{`say "hi`, searchTerms{match: `"say"`, short: []string{"hi"}}},
+		{`a"b"c`, searchTerms{short: []string{"a", "b", "c"}}},
+		{`"it""s"`, searchTerms{short: []string{"it", "s"}}},
+		{"ชีวิต", searchTerms{match: `"ชีวิต"`}},
+	}
+
+	for _, tt := range tests {
+		if got := parseSearch(tt.search); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSearch(%q) = %+v, want %+v", tt.search, got, tt.want)
+		}
+	}
+}
+
+func TestRankedVideoSearch(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 1, "Pokémon Café ReMix")
+	createTestGame(t, r, 2, "Minecraft")
+
+	description := "เล่นกับเพื่อน <3 Minecraft"
+	videos := []repoModel.Videos{
+		{ID: stringPtr("vid1"), Title: "มายคราฟ เอาชีวิตรอด EP.1", Description: &description},
+		{ID: stringPtr("vid2"), Title: "Minecraft Hardcore EP.1"},
+		{ID: stringPtr("vid3"), Title: "ร้านกาแฟโปเกมอน"},
+	}
+	for i, v := range videos {
+		v.PublishedAt = time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC)
+		if err := r.CreateVideo(ctx, v); err != nil {
+			t.Fatalf("CreateVideo: %v", err)
+		}
+	}
+	if err := r.AddVideoGame(ctx, "vid3", model.AddVideoGameRequest{GameID: 1}); err != nil {
+		t.Fatalf("AddVideoGame: %v", err)
+	}
+
+	search := func(query string) []model.VideoResponse {
+		t.Helper()
+		videos, err := r.GetVideos(ctx, model.Offset{Limit: 24}, model.VideoFilter{Search: model.Search{Query: query, Ranked: true}})
+		if err != nil {
+			t.Fatalf("GetVideos(%q): %v", query, err)
+		}
+		return videos
+	}
+
+	// Thai words are matched inside runs of text with no spaces.
+	got := search("ชีวิต")
+	if len(got) != 1 || got[0].ID != "vid1" {
+		t.Fatalf("ชีวิต: %+v, want vid1", got)
+	}
+	if h := got[0].Match.Highlights["title"]; h != "มายคราฟ เอา<mark>ชีวิต</mark>รอด EP.1" {
+		t.Fatalf("title highlight = %q", h)
+	}
+
+	// Titles outrank descriptions; descriptions are escaped.
+	got = search("minecraft")
+	if len(got) != 2 || got[0].ID != "vid2" || got[1].ID != "vid1" {
+		t.Fatalf("minecraft: %+v, want vid2 then vid1", got)
+	}
+	if h := got[1].Match.Highlights["description"]; h != "เล่นกับเพื่อน &lt;3 <mark>Minecraft</mark>" {
+		t.Fatalf("description highlight = %q", h)
+	}
+	if got[0].Match.Rank >= got[1].Match.Rank {
+		t.Fatalf("ranks %v, %v are not in order", got[0].Match.Rank, got[1].Match.Rank)
+	}
+
+	// Game names match ignoring accents.
+	got = search("pokemon cafe")
+	if len(got) != 1 || got[0].ID != "vid3" || got[0].Match.Highlights["games"] != "<mark>Pokémon</mark> <mark>Café</mark> ReMix" {
+		t.Fatalf("pokemon cafe: %+v", got)
+	}
+
+	// Phrases match as a whole; short terms still filter.
+	if got = search(`"hardcore ep"`); len(got) != 1 || got[0].ID != "vid2" {
+		t.Fatalf(`"hardcore ep": %+v, want vid2`, got)
+	}
+	if got = search(`"ep hardcore"`); len(got) != 0 {
+		t.Fatalf(`"ep hardcore": %+v, want nothing`, got)
+	}
+	if got = search("mine* EP"); len(got) != 2 {
+		t.Fatalf("mine* EP: %+v, want two videos", got)
+	}
+	if got = search("EP"); len(got) != 2 || got[0].ID != "vid2" || got[0].Match == nil {
+		t.Fatalf("EP: %+v, want vid2 then vid1", got)
+	}
+}
+
+func TestSearchIndexFollowsChanges(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 1, "hollow-knight")
+	createTestVideo(t, r, "vid1", "Bug game EP.1", time.Now())
+	if err := r.UpdateVideoGame(ctx, "vid1", 1); err != nil {
+		t.Fatalf("UpdateVideoGame: %v", err)
+	}
+
+	count := func(query string) int64 {
+		t.Helper()
+		total, err := r.GetVideoTotalItems(ctx, model.VideoFilter{Search: model.Search{Query: query}})
+		if err != nil {
+			t.Fatalf("GetVideoTotalItems(%q): %v", query, err)
+		}
+		return total
+	}
+
+	if n := count("hollow"); n != 1 {
+		t.Fatalf("hollow: %d videos, want 1", n)
+	}
+
+	if _, err := r.ex.ExecContext(ctx, "UPDATE games SET name = 'silksong' WHERE id = 1"); err != nil {
+		t.Fatalf("rename game: %v", err)
+	}
+	if n := count("hollow"); n != 0 {
+		t.Fatalf("hollow after rename: %d videos, want 0", n)
+	}
+	if n := count("silksong"); n != 1 {
+		t.Fatalf("silksong: %d videos, want 1", n)
+	}
+
+	games, err := r.GetGames(ctx, model.Offset{Limit: 10}, model.Search{Query: "silk", Ranked: true})
+	if err != nil {
+		t.Fatalf("GetGames: %v", err)
+	}
+	if len(games) != 1 || games[0].Match == nil || games[0].Match.Highlights["name"] != "<mark>silk</mark>song" {
+		t.Fatalf("games = %+v", games)
+	}
+
+	if err := r.DeleteVideoGame(ctx, "vid1"); err != nil {
+		t.Fatalf("DeleteVideoGame: %v", err)
+	}
+	if n := count("silksong"); n != 0 {
+		t.Fatalf("silksong after removing the game: %d videos, want 0", n)
+	}
+
+	if _, err := r.ex.ExecContext(ctx, "UPDATE videos SET title = 'Moth game' WHERE id = 'vid1'"); err != nil {
+		t.Fatalf("rename video: %v", err)
+	}
+	if n := count("moth"); n != 1 {
+		t.Fatalf("moth: %d videos, want 1", n)
+	}
+}
+
+func stringPtr(s string) *string { return &s }
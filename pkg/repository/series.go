@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// Batch sizes keeping a statement about series under D1's limit of 100
+// bound parameters a statement.
+const (
+	seriesBatchSize      = 25
+	seriesQueryBatchSize = 90
+)
+
+// SeriesEpisode places a video in a series of its channel: Key is the
+// normalized name of the series, and Name the name as the title has it.
+type SeriesEpisode struct {
+	VideoID string
+	Key     string
+	Name    string
+	Episode int32
+}
+
+type seriesWithCounts struct {
+	repoModel.Series
+	EpisodeCount int64 `alias:"counts.episode_count"`
+	MatchedCount int64 `alias:"counts.matched_count"`
+}
+
+// AssignSeries puts videos of a channel in their series, in one
+// transaction, creating the series that don't exist yet. It returns the
+// IDs of the series the videos were put in.
+func (r *Repository) AssignSeries(ctx context.Context, channelID string, episodes []SeriesEpisode) ([]int64, error) {
+	if len(episodes) == 0 {
+		return nil, nil
+	}
+
+	var series []repoModel.Series
+	for _, episode := range episodes {
+		if !slices.ContainsFunc(series, func(s repoModel.Series) bool { return s.NormalizedName == episode.Key }) {
+			series = append(series, repoModel.Series{ChannelID: channelID, NormalizedName: episode.Key, Name: episode.Name})
+		}
+	}
+
+	err := r.Transaction(ctx, func(tx *Repository) error {
+		now := time.Now()
+
+		for batch := range slices.Chunk(series, seriesBatchSize) {
+			insert := Series.INSERT(Series.ChannelID, Series.NormalizedName, Series.Name, Series.CreatedAt)
+			for _, s := range batch {
+				insert = insert.VALUES(s.ChannelID, s.NormalizedName, s.Name, now)
+			}
+			insert = insert.ON_CONFLICT(Series.ChannelID, Series.NormalizedName).DO_NOTHING()
+
+			if _, err := insert.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("assign series", err)
+			}
+		}
+
+		for _, episode := range episodes {
+			seriesID := sqlite.IntExp(
+				sqlite.SELECT(Series.ID).
+					FROM(Series).
+					WHERE(
+						Series.ChannelID.EQ(sqlite.String(channelID)).
+							AND(Series.NormalizedName.EQ(sqlite.String(episode.Key))),
+					),
+			)
+
+			stmt := Videos.UPDATE(Videos.SeriesID, Videos.Episode).
+				SET(seriesID, episode.Episode).
+				WHERE(Videos.ID.EQ(sqlite.String(episode.VideoID)))
+
+			if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("assign series", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(series))
+	for i, s := range series {
+		keys[i] = s.NormalizedName
+	}
+
+	var ids []int64
+	for batch := range slices.Chunk(keys, seriesQueryBatchSize) {
+		var rows []repoModel.Series
+
+		stmt := sqlite.SELECT(Series.ID).
+			FROM(Series).
+			WHERE(
+				Series.ChannelID.EQ(sqlite.String(channelID)).
+					AND(Series.NormalizedName.IN(stringList(batch)...)),
+			)
+
+		if err := stmt.QueryContext(ctx, r.ex, &rows); err != nil {
+			return nil, FormatError("assign series", err)
+		}
+
+		for _, row := range rows {
+			ids = append(ids, int64(*row.ID))
+		}
+	}
+
+	return ids, nil
+}
+
+// GetVideosWithoutSeries returns the ID, channel, title and primary game of
+// every video that isn't in a series.
+func (r *Repository) GetVideosWithoutSeries(ctx context.Context) ([]repoModel.Videos, error) {
+	var videos []repoModel.Videos
+
+	stmt := sqlite.SELECT(Videos.ID, Videos.ChannelID, Videos.Title, Videos.GameID).
+		FROM(Videos).
+		WHERE(Videos.SeriesID.IS_NULL()).
+		ORDER_BY(Videos.PublishedAt.ASC())
+
+	if err := stmt.QueryContext(ctx, r.ex, &videos); err != nil {
+		return nil, FormatError("get videos without series", err)
+	}
+
+	return videos, nil
+}
+
+// GetAllSeriesIDs returns the ID of every series.
+func (r *Repository) GetAllSeriesIDs(ctx context.Context) ([]int64, error) {
+	var series []repoModel.Series
+
+	stmt := sqlite.SELECT(Series.ID).
+		FROM(Series).
+		ORDER_BY(Series.ID.ASC())
+
+	if err := stmt.QueryContext(ctx, r.ex, &series); err != nil {
+		return nil, FormatError("get all series ids", err)
+	}
+
+	ids := make([]int64, len(series))
+	for i, s := range series {
+		ids[i] = int64(*s.ID)
+	}
+	return ids, nil
+}
+
+// GetVideoSeriesIDs returns the series the videos in videoIDs are in.
+func (r *Repository) GetVideoSeriesIDs(ctx context.Context, videoIDs []string) ([]int64, error) {
+	var ids []int64
+
+	for batch := range slices.Chunk(videoIDs, seriesQueryBatchSize) {
+		var videos []repoModel.Videos
+
+		stmt := sqlite.SELECT(Videos.ID, Videos.SeriesID).
+			FROM(Videos).
+			WHERE(Videos.ID.IN(stringList(batch)...).AND(Videos.SeriesID.IS_NOT_NULL()))
+
+		if err := stmt.QueryContext(ctx, r.ex, &videos); err != nil {
+			return nil, FormatError("get video series ids", err)
+		}
+
+		for _, video := range videos {
+			if id := int64(*video.SeriesID); !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// GetSeriesEpisodes returns the episodes of the series in seriesIDs, with
+// their primary games, in episode order.
+func (r *Repository) GetSeriesEpisodes(ctx context.Context, seriesIDs []int64) ([]VideoWithGame, error) {
+	var episodes []VideoWithGame
+
+	for batch := range slices.Chunk(seriesIDs, seriesQueryBatchSize) {
+		var videos []VideoWithGame
+
+		stmt := selectVideos(searchTerms{}, false).
+			WHERE(Videos.SeriesID.IN(intList(batch)...)).
+			ORDER_BY(Videos.SeriesID.ASC(), Videos.Episode.ASC(), Videos.PublishedAt.ASC())
+
+		if err := stmt.QueryContext(ctx, r.ex, &videos); err != nil {
+			return nil, FormatError("get series episodes", err)
+		}
+
+		episodes = append(episodes, videos...)
+	}
+
+	return episodes, nil
+}
+
+func seriesFilterExpression(filter model.SeriesFilter) *sqlite.BoolExpression {
+	if filter.ChannelID == "" {
+		return nil
+	}
+
+	exp := Series.ChannelID.EQ(sqlite.String(filter.ChannelID))
+	return &exp
+}
+
+func selectSeries() sqlite.SelectStatement {
+	return sqlite.SELECT(
+		Series.AllColumns,
+		sqlite.COUNT(Videos.ID).AS("counts.episode_count"),
+		sqlite.COUNT(Videos.GameID).AS("counts.matched_count"),
+	).FROM(
+		Series.LEFT_JOIN(Videos, Videos.SeriesID.EQ(Series.ID)),
+	)
+}
+
+// GetSeries lists series, the most recently continued first.
+func (r *Repository) GetSeries(ctx context.Context, query model.Offset, filter model.SeriesFilter) ([]model.Series, error) {
+	var rows []seriesWithCounts
+
+	stmt := selectSeries()
+
+	if exp := seriesFilterExpression(filter); exp != nil {
+		stmt = stmt.WHERE(*exp)
+	}
+
+	stmt = stmt.
+		GROUP_BY(Series.ID).
+		ORDER_BY(sqlite.MAX(Videos.PublishedAt).DESC(), Series.ID.DESC()).
+		LIMIT(query.Limit).
+		OFFSET(query.Offset)
+
+	if err := stmt.QueryContext(ctx, r.ex, &rows); err != nil {
+		return nil, FormatError("get series", err)
+	}
+
+	series := make([]model.Series, len(rows))
+	for i, row := range rows {
+		series[i] = convertToSeries(row)
+	}
+
+	return series, nil
+}
+
+func (r *Repository) GetSeriesTotalItems(ctx context.Context, filter model.SeriesFilter) (int64, error) {
+	return TotalItems(ctx, r.ex, Series.ID, Series, seriesFilterExpression(filter))
+}
+
+// GetSeriesByID returns a series with its episodes.
+func (r *Repository) GetSeriesByID(ctx context.Context, id int64) (*model.Series, error) {
+	var row seriesWithCounts
+
+	stmt := selectSeries().
+		WHERE(Series.ID.EQ(sqlite.Int(id))).
+		GROUP_BY(Series.ID)
+
+	if err := stmt.QueryContext(ctx, r.ex, &row); err != nil {
+		return nil, FormatError("get series by id", err)
+	}
+
+	episodes, err := r.GetSeriesEpisodes(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+
+	series := convertToSeries(row)
+	series.Episodes = convertToVideoResponses(episodes)
+	if err := r.attachVideoGames(ctx, series.Episodes); err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func convertToSeries(row seriesWithCounts) model.Series {
+	return model.Series{
+		ID:           int64(*row.ID),
+		ChannelID:    row.ChannelID,
+		Name:         row.Name,
+		EpisodeCount: row.EpisodeCount,
+		MatchedCount: row.MatchedCount,
+		CreatedAt:    row.CreatedAt,
+	}
+}
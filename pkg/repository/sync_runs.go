@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// syncRunErrorBatchSize keeps an insert of run errors under D1's limit of
+// 100 bound parameters a statement.
+const syncRunErrorBatchSize = 30
+
+// CreateSyncRun records the start of a sync run and returns its ID.
+func (r *Repository) CreateSyncRun(ctx context.Context, run repoModel.SyncRuns) (int64, error) {
+	stmt := SyncRuns.INSERT(SyncRuns.ChannelID, SyncRuns.TriggerSource, SyncRuns.Mode, SyncRuns.Status, SyncRuns.StartedAt).
+		VALUES(run.ChannelID, run.TriggerSource, run.Mode, run.Status, run.StartedAt)
+
+	result, err := stmt.ExecContext(ctx, r.ex)
+	if err != nil {
+		return 0, FormatError("create sync run", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, FormatError("create sync run", err)
+	}
+
+	return id, nil
+}
+
+// FinishSyncRun saves the outcome of a sync run, with the videos it failed
+// on.
+func (r *Repository) FinishSyncRun(ctx context.Context, run repoModel.SyncRuns, videoErrors []repoModel.SyncRunErrors) error {
+	return r.Transaction(ctx, func(tx *Repository) error {
+		stmt := SyncRuns.UPDATE(
+			SyncRuns.Status,
+			SyncRuns.FinishedAt,
+			SyncRuns.Added,
+			SyncRuns.Updated,
+			SyncRuns.Skipped,
+			SyncRuns.Errors,
+			SyncRuns.Total,
+			SyncRuns.Complete,
+			SyncRuns.Deferred,
+			SyncRuns.QuotaUnits,
+			SyncRuns.Error,
+		).
+			SET(
+				run.Status,
+				run.FinishedAt,
+				run.Added,
+				run.Updated,
+				run.Skipped,
+				run.Errors,
+				run.Total,
+				run.Complete,
+				run.Deferred,
+				run.QuotaUnits,
+				run.Error,
+			).
+			WHERE(SyncRuns.ID.EQ(sqlite.Int32(*run.ID)))
+
+		if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+			return FormatError("finish sync run", err)
+		}
+
+		for batch := range slices.Chunk(videoErrors, syncRunErrorBatchSize) {
+			insert := SyncRunErrors.INSERT(SyncRunErrors.RunID, SyncRunErrors.VideoID, SyncRunErrors.Message)
+			for _, e := range batch {
+				insert = insert.VALUES(*run.ID, e.VideoID, e.Message)
+			}
+
+			if _, err := insert.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("finish sync run", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func syncRunFilterExpression(filter model.SyncRunFilter) *sqlite.BoolExpression {
+	var conditions []sqlite.BoolExpression
+
+	if filter.ChannelID != "" {
+		conditions = append(conditions, SyncRuns.ChannelID.EQ(sqlite.String(filter.ChannelID)))
+	}
+	if filter.Trigger != "" {
+		conditions = append(conditions, SyncRuns.TriggerSource.EQ(sqlite.String(filter.Trigger)))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, SyncRuns.Status.EQ(sqlite.String(filter.Status)))
+	}
+
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	exp := sqlite.AND(conditions...)
+	return &exp
+}
+
+// GetSyncRuns returns the sync history, newest first, without the videos
+// each run failed on.
+func (r *Repository) GetSyncRuns(ctx context.Context, query model.Offset, filter model.SyncRunFilter) ([]model.SyncRun, error) {
+	runs := []repoModel.SyncRuns{}
+
+	stmt := sqlite.SELECT(SyncRuns.AllColumns).FROM(SyncRuns)
+
+	if exp := syncRunFilterExpression(filter); exp != nil {
+		stmt = stmt.WHERE(*exp)
+	}
+
+	stmt = stmt.
+		ORDER_BY(SyncRuns.StartedAt.DESC(), SyncRuns.ID.DESC()).
+		LIMIT(query.Limit).
+		OFFSET(query.Offset)
+
+	err := stmt.QueryContext(ctx, r.ex, &runs)
+	if err != nil {
+		return nil, FormatError("get sync runs", err)
+	}
+
+	responses := make([]model.SyncRun, len(runs))
+	for i, run := range runs {
+		responses[i] = convertToSyncRun(run)
+	}
+
+	return responses, nil
+}
+
+func (r *Repository) GetSyncRunTotalItems(ctx context.Context, filter model.SyncRunFilter) (int64, error) {
+	return TotalItems(ctx, r.ex, SyncRuns.ID, SyncRuns, syncRunFilterExpression(filter))
+}
+
+// GetSyncRunByID returns a sync run with the videos it failed on.
+func (r *Repository) GetSyncRunByID(ctx context.Context, id int64) (*model.SyncRun, error) {
+	var run repoModel.SyncRuns
+
+	stmt := sqlite.SELECT(SyncRuns.AllColumns).
+		FROM(SyncRuns).
+		WHERE(SyncRuns.ID.EQ(sqlite.Int(id)))
+
+	err := stmt.QueryContext(ctx, r.ex, &run)
+	if err != nil {
+		return nil, FormatError("get sync run by id", err)
+	}
+
+	videoErrors := []repoModel.SyncRunErrors{}
+
+	errStmt := sqlite.SELECT(SyncRunErrors.AllColumns).
+		FROM(SyncRunErrors).
+		WHERE(SyncRunErrors.RunID.EQ(sqlite.Int(id))).
+		ORDER_BY(SyncRunErrors.ID.ASC())
+
+	err = errStmt.QueryContext(ctx, r.ex, &videoErrors)
+	if err != nil {
+		return nil, FormatError("get sync run by id", err)
+	}
+
+	response := convertToSyncRun(run)
+	response.VideoErrors = make([]model.SyncRunError, len(videoErrors))
+	for i, e := range videoErrors {
+		response.VideoErrors[i] = model.SyncRunError{VideoID: e.VideoID, Message: e.Message}
+	}
+
+	return &response, nil
+}
+
+func convertToSyncRun(run repoModel.SyncRuns) model.SyncRun {
+	return model.SyncRun{
+		ID:         int64(*run.ID),
+		ChannelID:  run.ChannelID,
+		Trigger:    run.TriggerSource,
+		Mode:       run.Mode,
+		Status:     run.Status,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		Added:      int(run.Added),
+		Updated:    int(run.Updated),
+		Skipped:    int(run.Skipped),
+		Errors:     int(run.Errors),
+		Total:      int(run.Total),
+		Complete:   run.Complete,
+		Deferred:   run.Deferred,
+		QuotaUnits: int(run.QuotaUnits),
+		Error:      run.Error,
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// GetSyncState returns the sync progress for a channel.
+func (r *Repository) GetSyncState(ctx context.Context, channelID string) (*repoModel.SyncState, error) {
+	var state repoModel.SyncState
+
+	stmt := sqlite.SELECT(SyncState.AllColumns).
+		FROM(SyncState).
+		WHERE(SyncState.ChannelID.EQ(sqlite.String(channelID)))
+
+	err := stmt.QueryContext(ctx, r.ex, &state)
+	if err != nil {
+		return nil, FormatError("get sync state", err)
+	}
+
+	return &state, nil
+}
+
+// SaveSyncState creates or replaces the sync progress for a channel.
+func (r *Repository) SaveSyncState(ctx context.Context, state repoModel.SyncState) error {
+	stmt := SyncState.INSERT(
+		SyncState.ChannelID,
+		SyncState.UploadsPlaylistID,
+		SyncState.NewestVideoID,
+		SyncState.NewestPublishedAt,
+		SyncState.LastSyncedAt,
+		SyncState.BackfillPageToken,
+		SyncState.BackfillStartedAt,
+		SyncState.BackfillCompletedAt,
+		SyncState.PlaylistItemCount,
+		SyncState.UpdatedAt,
+	).
+		VALUES(
+			state.ChannelID,
+			state.UploadsPlaylistID,
+			state.NewestVideoID,
+			state.NewestPublishedAt,
+			state.LastSyncedAt,
+			state.BackfillPageToken,
+			state.BackfillStartedAt,
+			state.BackfillCompletedAt,
+			state.PlaylistItemCount,
+			time.Now(),
+		).
+		ON_CONFLICT(SyncState.ChannelID).
+		DO_UPDATE(sqlite.SET(
+			SyncState.UploadsPlaylistID.SET(SyncState.EXCLUDED.UploadsPlaylistID),
+			SyncState.NewestVideoID.SET(SyncState.EXCLUDED.NewestVideoID),
+			SyncState.NewestPublishedAt.SET(SyncState.EXCLUDED.NewestPublishedAt),
+			SyncState.LastSyncedAt.SET(SyncState.EXCLUDED.LastSyncedAt),
+			SyncState.BackfillPageToken.SET(SyncState.EXCLUDED.BackfillPageToken),
+			SyncState.BackfillStartedAt.SET(SyncState.EXCLUDED.BackfillStartedAt),
+			SyncState.BackfillCompletedAt.SET(SyncState.EXCLUDED.BackfillCompletedAt),
+			SyncState.PlaylistItemCount.SET(SyncState.EXCLUDED.PlaylistItemCount),
+			SyncState.UpdatedAt.SET(SyncState.EXCLUDED.UpdatedAt),
+		))
+
+	_, err := stmt.ExecContext(ctx, r.ex)
+	if err != nil {
+		return FormatError("save sync state", err)
+	}
+
+	return nil
+}
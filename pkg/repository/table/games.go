@@ -17,11 +17,17 @@ type gamesTable struct {
 	sqlite.Table
 
 	// Columns
-	ID        sqlite.ColumnInteger
-	Name      sqlite.ColumnString
-	URL       sqlite.ColumnString
-	CreatedAt sqlite.ColumnTimestamp
-	UpdatedAt sqlite.ColumnTimestamp
+	ID                sqlite.ColumnInteger
+	Name              sqlite.ColumnString
+	URL               sqlite.ColumnString
+	CreatedAt         sqlite.ColumnTimestamp
+	UpdatedAt         sqlite.ColumnTimestamp
+	Summary           sqlite.ColumnString
+	CoverURL          sqlite.ColumnString
+	FirstReleaseDate  sqlite.ColumnTimestamp
+	Rating            sqlite.ColumnFloat
+	RatingCount       sqlite.ColumnInteger
+	MetadataUpdatedAt sqlite.ColumnTimestamp
 
 	AllColumns     sqlite.ColumnList
 	MutableColumns sqlite.ColumnList
@@ -63,25 +69,37 @@ func newGamesTable(schemaName, tableName, alias string) *GamesTable {
 
 func newGamesTableImpl(schemaName, tableName, alias string) gamesTable {
 	var (
-		IDColumn        = sqlite.IntegerColumn("id")
-		NameColumn      = sqlite.StringColumn("name")
-		URLColumn       = sqlite.StringColumn("url")
-		CreatedAtColumn = sqlite.TimestampColumn("created_at")
-		UpdatedAtColumn = sqlite.TimestampColumn("updated_at")
-		allColumns      = sqlite.ColumnList{IDColumn, NameColumn, URLColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns  = sqlite.ColumnList{NameColumn, URLColumn, CreatedAtColumn, UpdatedAtColumn}
-		defaultColumns  = sqlite.ColumnList{CreatedAtColumn, UpdatedAtColumn}
+		IDColumn                = sqlite.IntegerColumn("id")
+		NameColumn              = sqlite.StringColumn("name")
+		URLColumn               = sqlite.StringColumn("url")
+		CreatedAtColumn         = sqlite.TimestampColumn("created_at")
+		UpdatedAtColumn         = sqlite.TimestampColumn("updated_at")
+		SummaryColumn           = sqlite.StringColumn("summary")
+		CoverURLColumn          = sqlite.StringColumn("cover_url")
+		FirstReleaseDateColumn  = sqlite.TimestampColumn("first_release_date")
+		RatingColumn            = sqlite.FloatColumn("rating")
+		RatingCountColumn       = sqlite.IntegerColumn("rating_count")
+		MetadataUpdatedAtColumn = sqlite.TimestampColumn("metadata_updated_at")
+		allColumns              = sqlite.ColumnList{IDColumn, NameColumn, URLColumn, CreatedAtColumn, UpdatedAtColumn, SummaryColumn, CoverURLColumn, FirstReleaseDateColumn, RatingColumn, RatingCountColumn, MetadataUpdatedAtColumn}
+		mutableColumns          = sqlite.ColumnList{NameColumn, URLColumn, CreatedAtColumn, UpdatedAtColumn, SummaryColumn, CoverURLColumn, FirstReleaseDateColumn, RatingColumn, RatingCountColumn, MetadataUpdatedAtColumn}
+		defaultColumns          = sqlite.ColumnList{CreatedAtColumn, UpdatedAtColumn}
 	)
 
 	return gamesTable{
 		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:        IDColumn,
-		Name:      NameColumn,
-		URL:       URLColumn,
-		CreatedAt: CreatedAtColumn,
-		UpdatedAt: UpdatedAtColumn,
+		ID:                IDColumn,
+		Name:              NameColumn,
+		URL:               URLColumn,
+		CreatedAt:         CreatedAtColumn,
+		UpdatedAt:         UpdatedAtColumn,
+		Summary:           SummaryColumn,
+		CoverURL:          CoverURLColumn,
+		FirstReleaseDate:  FirstReleaseDateColumn,
+		Rating:            RatingColumn,
+		RatingCount:       RatingCountColumn,
+		MetadataUpdatedAt: MetadataUpdatedAtColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
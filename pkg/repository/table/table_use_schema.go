@@ -10,6 +10,28 @@ package table
 // UseSchema sets a new schema name for all generated table SQL builder types. It is recommended to invoke
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
+	Channels = Channels.FromSchema(schema)
+	Companies = Companies.FromSchema(schema)
+	GameAlternativeNames = GameAlternativeNames.FromSchema(schema)
+	GameCompanies = GameCompanies.FromSchema(schema)
+	GameGenres = GameGenres.FromSchema(schema)
+	GamePlatforms = GamePlatforms.FromSchema(schema)
+	GameThemes = GameThemes.FromSchema(schema)
 	Games = Games.FromSchema(schema)
+	GamesFts = GamesFts.FromSchema(schema)
+	Genres = Genres.FromSchema(schema)
+	MatchSuggestions = MatchSuggestions.FromSchema(schema)
+	Platforms = Platforms.FromSchema(schema)
+	Series = Series.FromSchema(schema)
+	SyncRunErrors = SyncRunErrors.FromSchema(schema)
+	SyncRuns = SyncRuns.FromSchema(schema)
+	SyncState = SyncState.FromSchema(schema)
+	Themes = Themes.FromSchema(schema)
+	VideoEdits = VideoEdits.FromSchema(schema)
+	VideoGames = VideoGames.FromSchema(schema)
+	VideoStats = VideoStats.FromSchema(schema)
 	Videos = Videos.FromSchema(schema)
+	VideosFts = VideosFts.FromSchema(schema)
+	WebsubSubscriptions = WebsubSubscriptions.FromSchema(schema)
+	YoutubeQuota = YoutubeQuota.FromSchema(schema)
 }
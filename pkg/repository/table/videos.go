@@ -17,13 +17,29 @@ type videosTable struct {
 	sqlite.Table
 
 	// Columns
-	ID          sqlite.ColumnString
-	Title       sqlite.ColumnString
-	Thumbnail   sqlite.ColumnString
-	PublishedAt sqlite.ColumnTimestamp
-	GameID      sqlite.ColumnInteger
-	CreatedAt   sqlite.ColumnTimestamp
-	UpdatedAt   sqlite.ColumnTimestamp
+	ID               sqlite.ColumnString
+	Title            sqlite.ColumnString
+	Thumbnail        sqlite.ColumnString
+	PublishedAt      sqlite.ColumnTimestamp
+	GameID           sqlite.ColumnInteger
+	CreatedAt        sqlite.ColumnTimestamp
+	UpdatedAt        sqlite.ColumnTimestamp
+	Description      sqlite.ColumnString
+	ChannelID        sqlite.ColumnString
+	DurationSeconds  sqlite.ColumnInteger
+	ViewCount        sqlite.ColumnInteger
+	LikeCount        sqlite.ColumnInteger
+	CommentCount     sqlite.ColumnInteger
+	Tags             sqlite.ColumnString
+	CategoryID       sqlite.ColumnString
+	LiveStatus       sqlite.ColumnString
+	DefaultLanguage  sqlite.ColumnString
+	DetailsUpdatedAt sqlite.ColumnTimestamp
+	StatsUpdatedAt   sqlite.ColumnTimestamp
+	Status           sqlite.ColumnString
+	LastSeenAt       sqlite.ColumnTimestamp
+	SeriesID         sqlite.ColumnInteger
+	Episode          sqlite.ColumnInteger
 
 	AllColumns     sqlite.ColumnList
 	MutableColumns sqlite.ColumnList
@@ -65,29 +81,61 @@ func newVideosTable(schemaName, tableName, alias string) *VideosTable {
 
 func newVideosTableImpl(schemaName, tableName, alias string) videosTable {
 	var (
-		IDColumn          = sqlite.StringColumn("id")
-		TitleColumn       = sqlite.StringColumn("title")
-		ThumbnailColumn   = sqlite.StringColumn("thumbnail")
-		PublishedAtColumn = sqlite.TimestampColumn("published_at")
-		GameIDColumn      = sqlite.IntegerColumn("game_id")
-		CreatedAtColumn   = sqlite.TimestampColumn("created_at")
-		UpdatedAtColumn   = sqlite.TimestampColumn("updated_at")
-		allColumns        = sqlite.ColumnList{IDColumn, TitleColumn, ThumbnailColumn, PublishedAtColumn, GameIDColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns    = sqlite.ColumnList{TitleColumn, ThumbnailColumn, PublishedAtColumn, GameIDColumn, CreatedAtColumn, UpdatedAtColumn}
-		defaultColumns    = sqlite.ColumnList{CreatedAtColumn, UpdatedAtColumn}
+		IDColumn               = sqlite.StringColumn("id")
+		TitleColumn            = sqlite.StringColumn("title")
+		ThumbnailColumn        = sqlite.StringColumn("thumbnail")
+		PublishedAtColumn      = sqlite.TimestampColumn("published_at")
+		GameIDColumn           = sqlite.IntegerColumn("game_id")
+		CreatedAtColumn        = sqlite.TimestampColumn("created_at")
+		UpdatedAtColumn        = sqlite.TimestampColumn("updated_at")
+		DescriptionColumn      = sqlite.StringColumn("description")
+		ChannelIDColumn        = sqlite.StringColumn("channel_id")
+		DurationSecondsColumn  = sqlite.IntegerColumn("duration_seconds")
+		ViewCountColumn        = sqlite.IntegerColumn("view_count")
+		LikeCountColumn        = sqlite.IntegerColumn("like_count")
+		CommentCountColumn     = sqlite.IntegerColumn("comment_count")
+		TagsColumn             = sqlite.StringColumn("tags")
+		CategoryIDColumn       = sqlite.StringColumn("category_id")
+		LiveStatusColumn       = sqlite.StringColumn("live_status")
+		DefaultLanguageColumn  = sqlite.StringColumn("default_language")
+		DetailsUpdatedAtColumn = sqlite.TimestampColumn("details_updated_at")
+		StatsUpdatedAtColumn   = sqlite.TimestampColumn("stats_updated_at")
+		StatusColumn           = sqlite.StringColumn("status")
+		LastSeenAtColumn       = sqlite.TimestampColumn("last_seen_at")
+		SeriesIDColumn         = sqlite.IntegerColumn("series_id")
+		EpisodeColumn          = sqlite.IntegerColumn("episode")
+		allColumns             = sqlite.ColumnList{IDColumn, TitleColumn, ThumbnailColumn, PublishedAtColumn, GameIDColumn, CreatedAtColumn, UpdatedAtColumn, DescriptionColumn, ChannelIDColumn, DurationSecondsColumn, ViewCountColumn, LikeCountColumn, CommentCountColumn, TagsColumn, CategoryIDColumn, LiveStatusColumn, DefaultLanguageColumn, DetailsUpdatedAtColumn, StatsUpdatedAtColumn, StatusColumn, LastSeenAtColumn, SeriesIDColumn, EpisodeColumn}
+		mutableColumns         = sqlite.ColumnList{TitleColumn, ThumbnailColumn, PublishedAtColumn, GameIDColumn, CreatedAtColumn, UpdatedAtColumn, DescriptionColumn, ChannelIDColumn, DurationSecondsColumn, ViewCountColumn, LikeCountColumn, CommentCountColumn, TagsColumn, CategoryIDColumn, LiveStatusColumn, DefaultLanguageColumn, DetailsUpdatedAtColumn, StatsUpdatedAtColumn, StatusColumn, LastSeenAtColumn, SeriesIDColumn, EpisodeColumn}
+		defaultColumns         = sqlite.ColumnList{CreatedAtColumn, UpdatedAtColumn, StatusColumn}
 	)
 
 	return videosTable{
 		Table: sqlite.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:          IDColumn,
-		Title:       TitleColumn,
-		Thumbnail:   ThumbnailColumn,
-		PublishedAt: PublishedAtColumn,
-		GameID:      GameIDColumn,
-		CreatedAt:   CreatedAtColumn,
-		UpdatedAt:   UpdatedAtColumn,
+		ID:               IDColumn,
+		Title:            TitleColumn,
+		Thumbnail:        ThumbnailColumn,
+		PublishedAt:      PublishedAtColumn,
+		GameID:           GameIDColumn,
+		CreatedAt:        CreatedAtColumn,
+		UpdatedAt:        UpdatedAtColumn,
+		Description:      DescriptionColumn,
+		ChannelID:        ChannelIDColumn,
+		DurationSeconds:  DurationSecondsColumn,
+		ViewCount:        ViewCountColumn,
+		LikeCount:        LikeCountColumn,
+		CommentCount:     CommentCountColumn,
+		Tags:             TagsColumn,
+		CategoryID:       CategoryIDColumn,
+		LiveStatus:       LiveStatusColumn,
+		DefaultLanguage:  DefaultLanguageColumn,
+		DetailsUpdatedAt: DetailsUpdatedAtColumn,
+		StatsUpdatedAt:   StatsUpdatedAtColumn,
+		Status:           StatusColumn,
+		LastSeenAt:       LastSeenAtColumn,
+		SeriesID:         SeriesIDColumn,
+		Episode:          EpisodeColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// videoHistoryBatchSize keeps an insert of edits or stats snapshots under
+// D1's limit of 100 bound parameters a statement.
+const videoHistoryBatchSize = 20
+
+// ReconcileVideo saves the title, thumbnail and status of video and records
+// edits in its history. It does nothing if there are no edits.
+func (r *Repository) ReconcileVideo(ctx context.Context, video repoModel.Videos, edits []repoModel.VideoEdits) error {
+	if len(edits) == 0 {
+		return nil
+	}
+
+	return r.Transaction(ctx, func(tx *Repository) error {
+		stmt := Videos.UPDATE(Videos.Title, Videos.Thumbnail, Videos.Status, Videos.UpdatedAt).
+			SET(video.Title, video.Thumbnail, video.Status, time.Now()).
+			WHERE(Videos.ID.EQ(sqlite.String(*video.ID)))
+
+		if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+			return FormatError("reconcile video", err)
+		}
+
+		insert := VideoEdits.INSERT(VideoEdits.VideoID, VideoEdits.Field, VideoEdits.OldValue, VideoEdits.NewValue, VideoEdits.ChangedAt)
+		for _, edit := range edits {
+			insert = insert.VALUES(*video.ID, edit.Field, edit.OldValue, edit.NewValue, edit.ChangedAt)
+		}
+
+		if _, err := insert.ExecContext(ctx, tx.ex); err != nil {
+			return FormatError("reconcile video", err)
+		}
+
+		return nil
+	})
+}
+
+// RecordVideoHistory saves the edits and first stats snapshots of a page of
+// videos stored by UpsertVideos, in one transaction.
+func (r *Repository) RecordVideoHistory(ctx context.Context, edits []repoModel.VideoEdits, snapshots []repoModel.VideoStats) error {
+	if len(edits) == 0 && len(snapshots) == 0 {
+		return nil
+	}
+
+	return r.Transaction(ctx, func(tx *Repository) error {
+		for batch := range slices.Chunk(edits, videoHistoryBatchSize) {
+			insert := VideoEdits.INSERT(VideoEdits.VideoID, VideoEdits.Field, VideoEdits.OldValue, VideoEdits.NewValue, VideoEdits.ChangedAt)
+			for _, edit := range batch {
+				insert = insert.VALUES(edit.VideoID, edit.Field, edit.OldValue, edit.NewValue, edit.ChangedAt)
+			}
+
+			if _, err := insert.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("record video history", err)
+			}
+		}
+
+		for batch := range slices.Chunk(snapshots, videoHistoryBatchSize) {
+			insert := VideoStats.INSERT(VideoStats.AllColumns).
+				MODELS(batch).
+				ON_CONFLICT(VideoStats.VideoID, VideoStats.CapturedAt).
+				DO_NOTHING()
+
+			if _, err := insert.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("record video history", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// MarkVideosSeen records that a backfill found ids in the uploads playlist.
+func (r *Repository) MarkVideosSeen(ctx context.Context, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	stmt := Videos.UPDATE(Videos.LastSeenAt).
+		SET(at).
+		WHERE(Videos.ID.IN(stringList(ids)...))
+
+	if _, err := stmt.ExecContext(ctx, r.ex); err != nil {
+		return FormatError("mark videos seen", err)
+	}
+
+	return nil
+}
+
+// GetVideosNotSeenSince returns the videos of a channel, other than removed
+// ones, that no backfill has found in the uploads playlist since the given
+// time.
+func (r *Repository) GetVideosNotSeenSince(ctx context.Context, channelID string, since time.Time) ([]repoModel.Videos, error) {
+	videos := []repoModel.Videos{}
+
+	stmt := sqlite.SELECT(Videos.AllColumns).
+		FROM(Videos).
+		WHERE(
+			Videos.ChannelID.EQ(sqlite.String(channelID)).
+				AND(Videos.Status.NOT_EQ(sqlite.String(model.VideoStatusRemoved))).
+				AND(Videos.LastSeenAt.IS_NULL().OR(Videos.LastSeenAt.LT(dateTime(since)))),
+		)
+
+	err := stmt.QueryContext(ctx, r.ex, &videos)
+	if err != nil {
+		return nil, FormatError("get videos not seen since", err)
+	}
+
+	return videos, nil
+}
+
+// GetVideoEdits returns the edit history of a video, oldest first.
+func (r *Repository) GetVideoEdits(ctx context.Context, videoID string) ([]model.VideoEdit, error) {
+	var edits []repoModel.VideoEdits
+
+	stmt := sqlite.SELECT(VideoEdits.AllColumns).
+		FROM(VideoEdits).
+		WHERE(VideoEdits.VideoID.EQ(sqlite.String(videoID))).
+		ORDER_BY(VideoEdits.ChangedAt.ASC(), VideoEdits.ID.ASC())
+
+	err := stmt.QueryContext(ctx, r.ex, &edits)
+	if err != nil {
+		return nil, FormatError("get video edits", err)
+	}
+
+	responses := make([]model.VideoEdit, len(edits))
+	for i, edit := range edits {
+		responses[i] = model.VideoEdit{
+			Field:     edit.Field,
+			OldValue:  edit.OldValue,
+			NewValue:  edit.NewValue,
+			ChangedAt: edit.ChangedAt,
+		}
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// The facets selectVideoFacets counts videos by.
+const (
+	facetGenre       = "genre"
+	facetPlatform    = "platform"
+	facetDeveloper   = "developer"
+	facetReleaseYear = "release_year"
+	facetMatched     = "matched"
+)
+
+// facetCount is how many videos have a value of a facet. Value is the IGDB
+// ID of a genre, platform or developer, a release year, or 1 for matched
+// videos and 0 for unmatched ones.
+type facetCount struct {
+	Facet string  `alias:"facet.facet"`
+	Value int64   `alias:"facet.value"`
+	Name  *string `alias:"facet.name"`
+	Count int64   `alias:"facet.count"`
+}
+
+// selectFacet counts the videos in found the game filters find by value,
+// joining the table value is read from.
+func selectFacet(facet string, filter model.VideoFilter, table sqlite.ReadableTable, value, name sqlite.Expression, conditions ...sqlite.BoolExpression) sqlite.SelectStatement {
+	conditions = append(conditions, gameConditions(filter)...)
+
+	stmt := sqlite.SELECT(
+		sqlite.String(facet).AS("facet.facet"),
+		value.AS("facet.value"),
+		name.AS("facet.name"),
+		sqlite.COUNT(sqlite.DISTINCT(Videos.ID)).AS("facet.count"),
+	).FROM(table)
+
+	if len(conditions) > 0 {
+		stmt = stmt.WHERE(sqlite.AND(conditions...))
+	}
+
+	return stmt.GROUP_BY(value)
+}
+
+// selectVideoFacets counts the videos the filter finds by each facet in one
+// query. Each facet leaves out its own filter. The videos the search and
+// the other filters find are selected once, so that their parameters are
+// bound once rather than for every facet.
+func selectVideoFacets(filter model.VideoFilter) sqlite.Statement {
+	terms := parseSearch(filter.Search.Query)
+
+	found := sqlite.CTE("found")
+	selectFound := sqlite.SELECT(Videos.ID).FROM(searchTable(terms))
+	if conditions := videoConditions(terms, filter); len(conditions) > 0 {
+		selectFound = selectFound.WHERE(sqlite.AND(conditions...))
+	}
+
+	videos := Videos.INNER_JOIN(found, Videos.ID.From(found).EQ(Videos.ID))
+	games := videos.INNER_JOIN(VideoGames, VideoGames.VideoID.EQ(Videos.ID))
+
+	byGenre, byPlatform, byDeveloper, byYear, byMatched := filter, filter, filter, filter, filter
+	byGenre.GenreID = 0
+	byPlatform.PlatformID = 0
+	byDeveloper.DeveloperID = 0
+	byYear.MinReleaseYear, byYear.MaxReleaseYear = 0, 0
+	byMatched.Matched = nil
+
+	year := sqlite.CAST(sqlite.STRFTIME(sqlite.String("%Y"), Games.FirstReleaseDate)).AS_INTEGER()
+
+	return sqlite.WITH(found.AS(selectFound))(
+		sqlite.UNION_ALL(
+			selectFacet(facetGenre, byGenre,
+				games.
+					INNER_JOIN(GameGenres, GameGenres.GameID.EQ(VideoGames.GameID)).
+					INNER_JOIN(Genres, Genres.ID.EQ(GameGenres.GenreID)),
+				Genres.ID, Genres.Name),
+			selectFacet(facetPlatform, byPlatform,
+				games.
+					INNER_JOIN(GamePlatforms, GamePlatforms.GameID.EQ(VideoGames.GameID)).
+					INNER_JOIN(Platforms, Platforms.ID.EQ(GamePlatforms.PlatformID)),
+				Platforms.ID, Platforms.Name),
+			selectFacet(facetDeveloper, byDeveloper,
+				games.
+					INNER_JOIN(GameCompanies, GameCompanies.GameID.EQ(VideoGames.GameID)).
+					INNER_JOIN(Companies, Companies.ID.EQ(GameCompanies.CompanyID)),
+				Companies.ID, Companies.Name,
+				GameCompanies.Developer.IS_TRUE()),
+			selectFacet(facetReleaseYear, byYear,
+				games.INNER_JOIN(Games, Games.ID.EQ(VideoGames.GameID)),
+				year, sqlite.NULL,
+				Games.FirstReleaseDate.IS_NOT_NULL()),
+			selectFacet(facetMatched, byMatched,
+				videos,
+				Videos.GameID.IS_NOT_NULL(), sqlite.NULL),
+		),
+	)
+}
+
+// GetVideoFacets counts the videos the filter finds by genre, platform,
+// developer, release year and whether they have a game. Values are ordered
+// by count, and years newest first.
+func (r *Repository) GetVideoFacets(ctx context.Context, filter model.VideoFilter) (*model.VideoFacets, error) {
+	var counts []facetCount
+
+	if err := selectVideoFacets(filter).QueryContext(ctx, r.ex, &counts); err != nil {
+		return nil, FormatError("get video facets", err)
+	}
+
+	facets := model.VideoFacets{
+		Genres:       []model.Facet{},
+		Platforms:    []model.Facet{},
+		Developers:   []model.Facet{},
+		ReleaseYears: []model.ReleaseYearFacet{},
+	}
+
+	for _, count := range counts {
+		facet := model.Facet{ID: int32(count.Value), Count: count.Count}
+		if count.Name != nil {
+			facet.Name = *count.Name
+		}
+
+		switch count.Facet {
+		case facetGenre:
+			facets.Genres = append(facets.Genres, facet)
+		case facetPlatform:
+			facets.Platforms = append(facets.Platforms, facet)
+		case facetDeveloper:
+			facets.Developers = append(facets.Developers, facet)
+		case facetReleaseYear:
+			facets.ReleaseYears = append(facets.ReleaseYears, model.ReleaseYearFacet{Year: int(count.Value), Count: count.Count})
+		case facetMatched:
+			if count.Value == 1 {
+				facets.Matched.Matched = count.Count
+			} else {
+				facets.Matched.Unmatched = count.Count
+			}
+		}
+	}
+
+	byCount := func(a, b model.Facet) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Name, b.Name))
+	}
+	slices.SortFunc(facets.Genres, byCount)
+	slices.SortFunc(facets.Platforms, byCount)
+	slices.SortFunc(facets.Developers, byCount)
+	slices.SortFunc(facets.ReleaseYears, func(a, b model.ReleaseYearFacet) int { return cmp.Compare(b.Year, a.Year) })
+
+	return &facets, nil
+}
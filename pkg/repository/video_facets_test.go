@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+func TestVideoFacetsBindTheSearchOnce(t *testing.T) {
+	matched, isLive := true, false
+	filter := model.VideoFilter{
+		ChannelID:      "UCsGx1qSnAS2P1YCJPYnYVUg",
+		MinDuration:    60,
+		MaxDuration:    7200,
+		IsLive:         &isLive,
+		GenreID:        12,
+		PlatformID:     6,
+		DeveloperID:    908,
+		MinReleaseYear: 2010,
+		MaxReleaseYear: 2020,
+		Matched:        &matched,
+	}
+
+	params := func(search string) int {
+		filter.Search.Query = search
+		_, args := selectVideoFacets(filter).Sql()
+		return len(args)
+	}
+
+	// Terms too short for the index are looked for in each of the three
+	// searched columns.
+	one, six := params("ep"), params("ep 1 mc ไป v2 hd")
+	if six-one != 5*3 {
+		t.Fatalf("five more short terms bind %d more parameters, want %d", six-one, 5*3)
+	}
+	if six > 100 {
+		t.Fatalf("facets bind %d parameters, more than D1's 100", six)
+	}
+}
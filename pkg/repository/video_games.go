@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+type VideoGameWithGame struct {
+	repoModel.VideoGames
+	Game repoModel.Games `alias:"game"`
+}
+
+// videoGamesBatchSize keeps a lookup of the games of videos under D1's limit
+// of 100 bound parameters a statement.
+const videoGamesBatchSize = 90
+
+// GetVideoGames returns the games of each video in videoIDs, in position
+// order.
+func (r *Repository) GetVideoGames(ctx context.Context, videoIDs []string) (map[string][]model.VideoGame, error) {
+	games := make(map[string][]model.VideoGame, len(videoIDs))
+	if len(videoIDs) == 0 {
+		return games, nil
+	}
+
+	for batch := range slices.Chunk(videoIDs, videoGamesBatchSize) {
+		var rows []VideoGameWithGame
+
+		stmt := sqlite.SELECT(
+			VideoGames.AllColumns,
+			Games.ID.AS("game.id"),
+			Games.Name.AS("game.name"),
+			Games.URL.AS("game.url"),
+		).FROM(
+			VideoGames.INNER_JOIN(Games, Games.ID.EQ(VideoGames.GameID)),
+		).WHERE(
+			VideoGames.VideoID.IN(stringList(batch)...),
+		).ORDER_BY(
+			VideoGames.VideoID.ASC(),
+			VideoGames.Position.ASC(),
+			VideoGames.CreatedAt.ASC(),
+		)
+
+		err := stmt.QueryContext(ctx, r.ex, &rows)
+		if err != nil {
+			return nil, FormatError("get video games", err)
+		}
+
+		for _, row := range rows {
+			games[row.VideoID] = append(games[row.VideoID], model.VideoGame{
+				GameInfo: model.GameInfo{
+					ID:   row.GameID,
+					Name: row.Game.Name,
+					URL:  &row.Game.URL,
+				},
+				Position:     row.Position,
+				StartSeconds: row.StartSeconds,
+				EndSeconds:   row.EndSeconds,
+			})
+		}
+	}
+
+	return games, nil
+}
+
+// attachVideoGames fills in the Games list of each video.
+func (r *Repository) attachVideoGames(ctx context.Context, videos []model.VideoResponse) error {
+	ids := make([]string, len(videos))
+	for i, v := range videos {
+		ids[i] = v.ID
+	}
+
+	games, err := r.GetVideoGames(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range videos {
+		videos[i].Games = games[videos[i].ID]
+		if videos[i].Games == nil {
+			videos[i].Games = []model.VideoGame{}
+		}
+	}
+
+	return nil
+}
+
+// AddVideoGame adds a game to the end of a video's game list. If the game is
+// already on the video, only its segment is updated.
+func (r *Repository) AddVideoGame(ctx context.Context, videoID string, req model.AddVideoGameRequest) error {
+	return r.Transaction(ctx, func(tx *Repository) error {
+		nextPosition := sqlite.IntExp(
+			sqlite.SELECT(
+				sqlite.COALESCE(sqlite.MAXi(VideoGames.Position).ADD(sqlite.Int(1)), sqlite.Int(0)),
+			).FROM(VideoGames).WHERE(VideoGames.VideoID.EQ(sqlite.String(videoID))),
+		)
+
+		stmt := VideoGames.INSERT(VideoGames.VideoID, VideoGames.GameID, VideoGames.Position, VideoGames.StartSeconds, VideoGames.EndSeconds).
+			VALUES(
+				videoID,
+				req.GameID,
+				nextPosition,
+				NullInt32(req.StartSeconds),
+				NullInt32(req.EndSeconds),
+			).
+			ON_CONFLICT(VideoGames.VideoID, VideoGames.GameID).
+			DO_UPDATE(sqlite.SET(
+				VideoGames.StartSeconds.SET(VideoGames.EXCLUDED.StartSeconds),
+				VideoGames.EndSeconds.SET(VideoGames.EXCLUDED.EndSeconds),
+			))
+
+		if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+			return FormatError("add video game", err)
+		}
+
+		return tx.syncPrimaryGame(ctx, videoID)
+	})
+}
+
+// RemoveVideoGame removes a game from a video. If it was the primary game,
+// the next one takes its place.
+func (r *Repository) RemoveVideoGame(ctx context.Context, videoID string, gameID int64) error {
+	return r.Transaction(ctx, func(tx *Repository) error {
+		stmt := VideoGames.DELETE().WHERE(
+			VideoGames.VideoID.EQ(sqlite.String(videoID)).
+				AND(VideoGames.GameID.EQ(sqlite.Int(gameID))),
+		)
+
+		if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+			return FormatError("remove video game", err)
+		}
+
+		return tx.syncPrimaryGame(ctx, videoID)
+	})
+}
+
+// ReorderVideoGames moves the games of a video into the order of gameIDs,
+// which should list every game on the video exactly once. The first becomes
+// the primary game.
+func (r *Repository) ReorderVideoGames(ctx context.Context, videoID string, gameIDs []int64) error {
+	return r.Transaction(ctx, func(tx *Repository) error {
+		for i, gameID := range gameIDs {
+			stmt := VideoGames.UPDATE(VideoGames.Position).
+				SET(sqlite.Int(int64(i))).
+				WHERE(
+					VideoGames.VideoID.EQ(sqlite.String(videoID)).
+						AND(VideoGames.GameID.EQ(sqlite.Int(gameID))),
+				)
+
+			if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("reorder video games", err)
+			}
+		}
+
+		return tx.syncPrimaryGame(ctx, videoID)
+	})
+}
+
+// syncPrimaryGame points videos.game_id at the video's first game.
+func (r *Repository) syncPrimaryGame(ctx context.Context, videoID string) error {
+	primary := sqlite.IntExp(
+		sqlite.SELECT(VideoGames.GameID).
+			FROM(VideoGames).
+			WHERE(VideoGames.VideoID.EQ(sqlite.String(videoID))).
+			ORDER_BY(VideoGames.Position.ASC(), VideoGames.CreatedAt.ASC()).
+			LIMIT(1),
+	)
+
+	stmt := Videos.UPDATE(Videos.GameID, Videos.UpdatedAt).
+		SET(
+			primary,
+			sqlite.CURRENT_TIMESTAMP(),
+		).
+		WHERE(Videos.ID.EQ(sqlite.String(videoID)))
+
+	_, err := stmt.ExecContext(ctx, r.ex)
+	if err != nil {
+		return FormatError("sync primary game", err)
+	}
+
+	return nil
+}
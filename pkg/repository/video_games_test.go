@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/model"
+)
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func videoGameIDs(t *testing.T, r *Repository, videoID string) (primary int32, ids []int32) {
+	t.Helper()
+
+	video, err := r.GetVideoByID(context.Background(), videoID)
+	if err != nil {
+		t.Fatalf("GetVideoByID: %v", err)
+	}
+	if video.Game != nil {
+		primary = video.Game.ID
+	}
+	for _, g := range video.Games {
+		ids = append(ids, g.ID)
+	}
+	return primary, ids
+}
+
+func TestVideoGamesAddReorderRemove(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 1, "hades")
+	createTestGame(t, r, 2, "celeste")
+	createTestGame(t, r, 3, "tetris")
+	createTestVideo(t, r, "vid1", "Indie marathon", time.Now())
+
+	for _, id := range []int64{1, 2, 3} {
+		if err := r.AddVideoGame(ctx, "vid1", model.AddVideoGameRequest{GameID: id}); err != nil {
+			t.Fatalf("AddVideoGame(%d): %v", id, err)
+		}
+	}
+	if primary, ids := videoGameIDs(t, r, "vid1"); primary != 1 || len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Fatalf("primary %d games %v, want 1 [1 2 3]", primary, ids)
+	}
+
+	if err := r.ReorderVideoGames(ctx, "vid1", []int64{3, 1, 2}); err != nil {
+		t.Fatalf("ReorderVideoGames: %v", err)
+	}
+	if primary, ids := videoGameIDs(t, r, "vid1"); primary != 3 || ids[0] != 3 || ids[1] != 1 || ids[2] != 2 {
+		t.Fatalf("primary %d games %v, want 3 [3 1 2]", primary, ids)
+	}
+
+	if err := r.RemoveVideoGame(ctx, "vid1", 3); err != nil {
+		t.Fatalf("RemoveVideoGame: %v", err)
+	}
+	if primary, ids := videoGameIDs(t, r, "vid1"); primary != 1 || len(ids) != 2 {
+		t.Fatalf("primary %d games %v, want 1 [1 2]", primary, ids)
+	}
+
+	if err := r.UpdateVideoGame(ctx, "vid1", 2); err != nil {
+		t.Fatalf("UpdateVideoGame: %v", err)
+	}
+	if primary, ids := videoGameIDs(t, r, "vid1"); primary != 2 || len(ids) != 2 || ids[1] != 1 {
+		t.Fatalf("primary %d games %v, want 2 [2 1]", primary, ids)
+	}
+
+	if err := r.DeleteVideoGame(ctx, "vid1"); err != nil {
+		t.Fatalf("DeleteVideoGame: %v", err)
+	}
+	if primary, ids := videoGameIDs(t, r, "vid1"); primary != 1 || len(ids) != 1 {
+		t.Fatalf("primary %d games %v, want 1 [1]", primary, ids)
+	}
+}
+
+func TestAddVideoGameUpdatesSegment(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 1, "hades")
+	createTestVideo(t, r, "vid1", "Hades EP.1", time.Now())
+
+	if err := r.AddVideoGame(ctx, "vid1", model.AddVideoGameRequest{GameID: 1, StartSeconds: int32Ptr(0), EndSeconds: int32Ptr(600)}); err != nil {
+		t.Fatalf("AddVideoGame: %v", err)
+	}
+	if err := r.AddVideoGame(ctx, "vid1", model.AddVideoGameRequest{GameID: 1, StartSeconds: int32Ptr(120), EndSeconds: int32Ptr(900)}); err != nil {
+		t.Fatalf("AddVideoGame again: %v", err)
+	}
+
+	video, err := r.GetVideoByID(ctx, "vid1")
+	if err != nil {
+		t.Fatalf("GetVideoByID: %v", err)
+	}
+	if len(video.Games) != 1 {
+		t.Fatalf("games = %+v, want one", video.Games)
+	}
+	g := video.Games[0]
+	if g.StartSeconds == nil || *g.StartSeconds != 120 || g.EndSeconds == nil || *g.EndSeconds != 900 {
+		t.Fatalf("segment = %v-%v, want 120-900", g.StartSeconds, g.EndSeconds)
+	}
+}
+
+func TestGetVideosSearchMatchesSecondaryGames(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 1, "hades")
+	createTestGame(t, r, 2, "celeste")
+	createTestVideo(t, r, "vid1", "Indie marathon", time.Now())
+	for _, id := range []int64{1, 2} {
+		if err := r.AddVideoGame(ctx, "vid1", model.AddVideoGameRequest{GameID: id}); err != nil {
+			t.Fatalf("AddVideoGame(%d): %v", id, err)
+		}
+	}
+
+	videos, err := r.GetVideos(ctx, model.Offset{Limit: 24}, model.VideoFilter{Search: model.Search{Query: "celeste"}})
+	if err != nil {
+		t.Fatalf("GetVideos: %v", err)
+	}
+	if len(videos) != 1 || videos[0].ID != "vid1" || len(videos[0].Games) != 2 {
+		t.Fatalf("videos = %+v, want vid1 with two games", videos)
+	}
+}
+
+func TestGetVideosAttachesGamesOfALongPage(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 1, "hades")
+	createTestGame(t, r, 2, "celeste")
+
+	// More videos than D1 binds parameters to one statement.
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 150 {
+		createTestVideo(t, r, fmt.Sprintf("vid%03d", i), "Video", start.Add(time.Duration(i)*time.Hour))
+	}
+	if err := r.UpdateVideoGame(ctx, "vid000", 1); err != nil {
+		t.Fatalf("UpdateVideoGame: %v", err)
+	}
+	if err := r.UpdateVideoGame(ctx, "vid149", 2); err != nil {
+		t.Fatalf("UpdateVideoGame: %v", err)
+	}
+
+	videos, err := r.GetVideos(ctx, model.Offset{Limit: 150}, model.VideoFilter{})
+	if err != nil {
+		t.Fatalf("GetVideos: %v", err)
+	}
+	if len(videos) != 150 {
+		t.Fatalf("videos = %d, want 150", len(videos))
+	}
+	if first := videos[0]; first.ID != "vid149" || len(first.Games) != 1 || first.Games[0].ID != 2 {
+		t.Fatalf("newest video = %s with %+v, want vid149 with celeste", first.ID, first.Games)
+	}
+	if last := videos[149]; last.ID != "vid000" || len(last.Games) != 1 || last.Games[0].ID != 1 {
+		t.Fatalf("oldest video = %s with %+v, want vid000 with hades", last.ID, last.Games)
+	}
+}
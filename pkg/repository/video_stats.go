@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// statsRefreshTier says how often the stats of videos published within
+// maxAge are read again.
+type statsRefreshTier struct {
+	maxAge   time.Duration
+	interval time.Duration
+}
+
+// statsRefreshTiers is the decaying schedule of the stats refresh: the
+// counts of new videos change fastest, so they are read most often. Videos
+// older than the last tier are no longer refreshed.
+var statsRefreshTiers = []statsRefreshTier{
+	{maxAge: 2 * 24 * time.Hour, interval: time.Hour},
+	{maxAge: 7 * 24 * time.Hour, interval: 6 * time.Hour},
+	{maxAge: 30 * 24 * time.Hour, interval: 24 * time.Hour},
+	{maxAge: 90 * 24 * time.Hour, interval: 7 * 24 * time.Hour},
+}
+
+// dateTime compares with DATETIME columns, which hold UTC times in the
+// "YYYY-MM-DD HH:MM:SS" format of CURRENT_TIMESTAMP.
+func dateTime(t time.Time) sqlite.TimestampExpression {
+	return sqlite.TimestampExp(sqlite.String(t.UTC().Format(time.DateTime)))
+}
+
+// GetVideosDueForStatsRefresh returns the IDs of up to limit available
+// videos whose stats are due to be read again at now, those waiting longest
+// first.
+func (r *Repository) GetVideosDueForStatsRefresh(ctx context.Context, now time.Time, limit int64) ([]string, error) {
+	// A video falls in every tier it is young enough for; the first,
+	// shortest interval is the one that makes it due.
+	due := make([]sqlite.BoolExpression, len(statsRefreshTiers))
+	for i, tier := range statsRefreshTiers {
+		due[i] = Videos.PublishedAt.GT(dateTime(now.Add(-tier.maxAge))).AND(
+			Videos.StatsUpdatedAt.IS_NULL().
+				OR(Videos.StatsUpdatedAt.LT_EQ(dateTime(now.Add(-tier.interval)))),
+		)
+	}
+
+	var rows []struct {
+		ID string `alias:"videos.id"`
+	}
+
+	stmt := sqlite.SELECT(Videos.ID).
+		FROM(Videos).
+		WHERE(
+			Videos.Status.EQ(sqlite.String(model.VideoStatusAvailable)).
+				AND(sqlite.OR(due...)),
+		).
+		ORDER_BY(Videos.StatsUpdatedAt.ASC(), Videos.PublishedAt.DESC()).
+		LIMIT(limit)
+
+	err := stmt.QueryContext(ctx, r.ex, &rows)
+	if err != nil {
+		return nil, FormatError("get videos due for stats refresh", err)
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	return ids, nil
+}
+
+// RecordVideoStats stores a snapshot of each video's counts and makes them
+// its latest. The videos in missing were looked up at checkedAt but had no
+// stats; they are left alone until they are next due.
+func (r *Repository) RecordVideoStats(ctx context.Context, snapshots []repoModel.VideoStats, missing []string, checkedAt time.Time) error {
+	return r.Transaction(ctx, func(tx *Repository) error {
+		for _, snapshot := range snapshots {
+			if err := tx.insertVideoStats(ctx, snapshot); err != nil {
+				return err
+			}
+
+			stmt := Videos.UPDATE(Videos.ViewCount, Videos.LikeCount, Videos.CommentCount, Videos.StatsUpdatedAt).
+				SET(snapshot.ViewCount, snapshot.LikeCount, snapshot.CommentCount, snapshot.CapturedAt).
+				WHERE(Videos.ID.EQ(sqlite.String(snapshot.VideoID)))
+
+			if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+				return FormatError("record video stats", err)
+			}
+		}
+
+		if len(missing) == 0 {
+			return nil
+		}
+
+		stmt := Videos.UPDATE(Videos.StatsUpdatedAt).
+			SET(checkedAt).
+			WHERE(Videos.ID.IN(stringList(missing)...))
+
+		if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+			return FormatError("record video stats", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) insertVideoStats(ctx context.Context, snapshot repoModel.VideoStats) error {
+	stmt := VideoStats.INSERT(VideoStats.AllColumns).
+		MODEL(snapshot).
+		ON_CONFLICT(VideoStats.VideoID, VideoStats.CapturedAt).
+		DO_NOTHING()
+
+	if _, err := stmt.ExecContext(ctx, r.ex); err != nil {
+		return FormatError("insert video stats", err)
+	}
+
+	return nil
+}
+
+// GetVideoStats returns the stats snapshots of a video, oldest first.
+func (r *Repository) GetVideoStats(ctx context.Context, videoID string) ([]repoModel.VideoStats, error) {
+	stats := []repoModel.VideoStats{}
+
+	stmt := sqlite.SELECT(VideoStats.AllColumns).
+		FROM(VideoStats).
+		WHERE(VideoStats.VideoID.EQ(sqlite.String(videoID))).
+		ORDER_BY(VideoStats.CapturedAt.ASC())
+
+	err := stmt.QueryContext(ctx, r.ex, &stats)
+	if err != nil {
+		return nil, FormatError("get video stats", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+func TestGetVideosDueForStatsRefresh(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	videos := []struct {
+		id             string
+		age            time.Duration
+		statsUpdatedAt time.Duration // before now; 0 for never
+	}{
+		{"new-never", time.Hour, 0},
+		{"new-stale", day, 2 * time.Hour},
+		{"new-fresh", day, 30 * time.Minute},
+		{"week-stale", 5 * day, 7 * time.Hour},
+		{"week-fresh", 5 * day, 2 * time.Hour},
+		{"month-stale", 20 * day, 25 * time.Hour},
+		{"month-fresh", 20 * day, 7 * time.Hour},
+		{"quarter-stale", 60 * day, 8 * day},
+		{"quarter-fresh", 60 * day, 2 * day},
+		{"old-never", 200 * day, 0},
+	}
+
+	for _, v := range videos {
+		video := repoModel.Videos{ID: &v.id, Title: v.id, PublishedAt: now.Add(-v.age)}
+		if v.statsUpdatedAt != 0 {
+			at := now.Add(-v.statsUpdatedAt)
+			video.StatsUpdatedAt = &at
+		}
+		if err := r.CreateVideo(ctx, video); err != nil {
+			t.Fatalf("CreateVideo(%s): %v", v.id, err)
+		}
+	}
+
+	due, err := r.GetVideosDueForStatsRefresh(ctx, now, 100)
+	if err != nil {
+		t.Fatalf("GetVideosDueForStatsRefresh: %v", err)
+	}
+
+	want := []string{"new-never", "quarter-stale", "month-stale", "week-stale", "new-stale"}
+	if !slices.Equal(due, want) {
+		t.Fatalf("due = %v, want %v", due, want)
+	}
+
+	due, err = r.GetVideosDueForStatsRefresh(ctx, now, 2)
+	if err != nil {
+		t.Fatalf("GetVideosDueForStatsRefresh: %v", err)
+	}
+	if !slices.Equal(due, want[:2]) {
+		t.Fatalf("due = %v, want %v", due, want[:2])
+	}
+}
+
+func TestRecordVideoStats(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestVideo(t, r, "vid1", "Video 1", time.Now())
+	createTestVideo(t, r, "gone", "Deleted video", time.Now())
+
+	views := func(n int64) *int64 { return &n }
+	first := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	second := first.Add(time.Hour)
+
+	for _, at := range []time.Time{first, second} {
+		snapshot := repoModel.VideoStats{VideoID: "vid1", CapturedAt: at, ViewCount: views(at.Unix() - first.Unix() + 100)}
+		if err := r.RecordVideoStats(ctx, []repoModel.VideoStats{snapshot}, []string{"gone"}, at); err != nil {
+			t.Fatalf("RecordVideoStats: %v", err)
+		}
+	}
+
+	stats, err := r.GetVideoStats(ctx, "vid1")
+	if err != nil {
+		t.Fatalf("GetVideoStats: %v", err)
+	}
+	if len(stats) != 2 || *stats[0].ViewCount != 100 || *stats[1].ViewCount != 3700 || !stats[1].CapturedAt.Equal(second) {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	video, err := r.GetVideoByID(ctx, "vid1")
+	if err != nil {
+		t.Fatalf("GetVideoByID: %v", err)
+	}
+	if video.ViewCount == nil || *video.ViewCount != 3700 {
+		t.Fatalf("view_count = %v, want the latest snapshot", video.ViewCount)
+	}
+
+	// A video without stats isn't due again until its next interval.
+	due, err := r.GetVideosDueForStatsRefresh(ctx, second, 10)
+	if err != nil {
+		t.Fatalf("GetVideosDueForStatsRefresh: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("due = %v, want none", due)
+	}
+	if stats, _ := r.GetVideoStats(ctx, "gone"); len(stats) != 0 {
+		t.Fatalf("gone has %d snapshots, want 0", len(stats))
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/go-jet/jet/v2/sqlite"
@@ -13,49 +15,168 @@ import (
 
 type VideoWithGame struct {
 	repoModel.Videos
-	Game *repoModel.Games `alias:"game"`
+	Game  *repoModel.Games `alias:"game"`
+	Match searchMatch      `alias:"match"`
 }
 
-func searchTable() sqlite.ReadableTable {
-	return Videos.LEFT_JOIN(Games, Games.ID.EQ(Videos.GameID))
+// videoRank scores videos_fts matches, weighting title over game names over
+// description. The video_id column isn't indexed and gets no weight.
+var videoRank = sqlite.RawFloat("bm25(videos_fts, 0.0, 10.0, 1.0, 5.0)")
+
+func searchTable(terms searchTerms) sqlite.ReadableTable {
+	table := Videos.LEFT_JOIN(Games, Games.ID.EQ(Videos.GameID))
+	if !terms.empty() {
+		table = table.INNER_JOIN(VideosFts, VideosFts.VideoID.EQ(Videos.ID))
+	}
+	return table
 }
 
-func selectVideos() sqlite.SelectStatement {
-	return sqlite.SELECT(
-		Videos.AllColumns,
+func selectVideos(terms searchTerms, ranked bool) sqlite.SelectStatement {
+	columns := []sqlite.Projection{
 		Games.ID.AS("game.id"),
 		Games.Name.AS("game.name"),
 		Games.URL.AS("game.url"),
-	).FROM(searchTable())
+	}
+
+	if ranked && terms.match != "" {
+		columns = append(columns,
+			videoRank.AS("match.rank"),
+			ftsHighlight("videos_fts", 1, false).AS("match.title"),
+			ftsHighlight("videos_fts", 2, true).AS("match.description"),
+			ftsHighlight("videos_fts", 3, false).AS("match.games"),
+		)
+	} else if ranked {
+		columns = append(columns, sqlite.Float(0).AS("match.rank"))
+	}
+
+	return sqlite.SELECT(Videos.AllColumns, columns...).FROM(searchTable(terms))
 }
 
-func searchExpression(search string) *sqlite.BoolExpression {
-	if search == "" {
+func searchExpression(terms searchTerms) *sqlite.BoolExpression {
+	if terms.empty() {
 		return nil
 	}
 
-	searchPattern := sqlite.String("%" + search + "%")
-	exp := sqlite.OR(
-		Videos.Title.LIKE(searchPattern),
-		Games.Name.LIKE(searchPattern),
-	)
+	exp := ftsExpression("videos_fts", terms, VideosFts.Title, VideosFts.Description, VideosFts.Games)
+	return &exp
+}
+
+// filterExpression combines the search and the other filters, or returns
+// nil if there is nothing to filter on.
+func filterExpression(terms searchTerms, filter model.VideoFilter) *sqlite.BoolExpression {
+	conditions := append(videoConditions(terms, filter), gameConditions(filter)...)
+
+	if len(conditions) == 0 {
+		return nil
+	}
 
+	exp := sqlite.AND(conditions...)
 	return &exp
 }
 
-func (r *Repository) GetVideos(ctx context.Context, query model.Offset, search string) ([]model.VideoResponse, error) {
+// videoConditions are the conditions of the search and the filters on the
+// videos themselves.
+func videoConditions(terms searchTerms, filter model.VideoFilter) []sqlite.BoolExpression {
+	var conditions []sqlite.BoolExpression
+
+	if exp := searchExpression(terms); exp != nil {
+		conditions = append(conditions, *exp)
+	}
+	if !filter.IncludeUnavailable {
+		conditions = append(conditions, Videos.Status.EQ(sqlite.String(model.VideoStatusAvailable)))
+	}
+	if filter.ChannelID != "" {
+		conditions = append(conditions, Videos.ChannelID.EQ(sqlite.String(filter.ChannelID)))
+	}
+	if filter.MinDuration > 0 {
+		conditions = append(conditions, Videos.DurationSeconds.GT_EQ(sqlite.Int(int64(filter.MinDuration))))
+	}
+	if filter.MaxDuration > 0 {
+		conditions = append(conditions, Videos.DurationSeconds.LT_EQ(sqlite.Int(int64(filter.MaxDuration))))
+	}
+	if filter.IsLive != nil {
+		// Videos that haven't been looked up have no live_status and match
+		// neither.
+		none := sqlite.String(model.LiveStatusNone)
+		if *filter.IsLive {
+			conditions = append(conditions, Videos.LiveStatus.NOT_EQ(none))
+		} else {
+			conditions = append(conditions, Videos.LiveStatus.EQ(none))
+		}
+	}
+
+	return conditions
+}
+
+// gameConditions are the conditions of the filters on the games of the
+// videos.
+func gameConditions(filter model.VideoFilter) []sqlite.BoolExpression {
+	var conditions []sqlite.BoolExpression
+
+	if filter.GenreID > 0 {
+		conditions = append(conditions, videoHasGame(GameGenres, GameGenres.GameID, GameGenres.GenreID.EQ(sqlite.Int(filter.GenreID))))
+	}
+	if filter.PlatformID > 0 {
+		conditions = append(conditions, videoHasGame(GamePlatforms, GamePlatforms.GameID, GamePlatforms.PlatformID.EQ(sqlite.Int(filter.PlatformID))))
+	}
+	if filter.DeveloperID > 0 {
+		conditions = append(conditions, videoHasGame(GameCompanies, GameCompanies.GameID,
+			GameCompanies.CompanyID.EQ(sqlite.Int(filter.DeveloperID)).AND(GameCompanies.Developer.IS_TRUE())))
+	}
+	if filter.MinReleaseYear > 0 || filter.MaxReleaseYear > 0 {
+		released := []sqlite.BoolExpression{Games.FirstReleaseDate.IS_NOT_NULL()}
+		if filter.MinReleaseYear > 0 {
+			released = append(released, Games.FirstReleaseDate.GT_EQ(dateTime(yearStart(filter.MinReleaseYear))))
+		}
+		if filter.MaxReleaseYear > 0 {
+			released = append(released, Games.FirstReleaseDate.LT(dateTime(yearStart(filter.MaxReleaseYear+1))))
+		}
+		conditions = append(conditions, videoHasGame(Games, Games.ID, sqlite.AND(released...)))
+	}
+	if filter.Matched != nil {
+		if *filter.Matched {
+			conditions = append(conditions, Videos.GameID.IS_NOT_NULL())
+		} else {
+			conditions = append(conditions, Videos.GameID.IS_NULL())
+		}
+	}
+
+	return conditions
+}
+
+// videoHasGame matches videos with any game whose rows in table, joined by
+// gameID, meet condition.
+func videoHasGame(table sqlite.ReadableTable, gameID sqlite.ColumnInteger, condition sqlite.BoolExpression) sqlite.BoolExpression {
+	return sqlite.EXISTS(
+		sqlite.SELECT(VideoGames.GameID).
+			FROM(VideoGames.INNER_JOIN(table, gameID.EQ(VideoGames.GameID))).
+			WHERE(VideoGames.VideoID.EQ(Videos.ID).AND(condition)),
+	)
+}
+
+func yearStart(year int) time.Time {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// GetVideos lists videos, newest first. Ranked searches are ordered by
+// relevance instead.
+func (r *Repository) GetVideos(ctx context.Context, query model.Offset, filter model.VideoFilter) ([]model.VideoResponse, error) {
 	var videos []VideoWithGame
 
-	stmt := selectVideos()
+	terms := parseSearch(filter.Search.Query)
+	stmt := selectVideos(terms, filter.Search.Ranked)
 
-	if search != "" {
-		stmt = stmt.WHERE(
-			*searchExpression(search),
-		)
+	if exp := filterExpression(terms, filter); exp != nil {
+		stmt = stmt.WHERE(*exp)
+	}
+
+	if filter.Search.Ranked && terms.match != "" {
+		stmt = stmt.ORDER_BY(videoRank.ASC(), Videos.PublishedAt.DESC())
+	} else {
+		stmt = stmt.ORDER_BY(Videos.PublishedAt.DESC())
 	}
 
 	stmt = stmt.
-		ORDER_BY(Videos.PublishedAt.DESC()).
 		LIMIT(query.Limit).
 		OFFSET(query.Offset)
 
@@ -64,13 +185,18 @@ func (r *Repository) GetVideos(ctx context.Context, query model.Offset, search s
 		return nil, FormatError("get videos", err)
 	}
 
-	return convertToVideoResponses(videos), nil
+	responses := convertToVideoResponses(videos)
+	if err := r.attachVideoGames(ctx, responses); err != nil {
+		return nil, err
+	}
+
+	return responses, nil
 }
 
 func (r *Repository) GetVideoByID(ctx context.Context, id string) (*model.VideoResponse, error) {
 	var video VideoWithGame
 
-	stmt := selectVideos().WHERE(Videos.ID.EQ(sqlite.String(id)))
+	stmt := selectVideos(searchTerms{}, false).WHERE(Videos.ID.EQ(sqlite.String(id)))
 
 	err := stmt.QueryContext(ctx, r.ex, &video)
 	if err != nil {
@@ -82,71 +208,338 @@ func (r *Repository) GetVideoByID(ctx context.Context, id string) (*model.VideoR
 		return nil, FormatError("get video by id", err)
 	}
 
+	if err := r.attachVideoGames(ctx, responses); err != nil {
+		return nil, err
+	}
+
 	return &responses[0], nil
 }
 
-func (r *Repository) GetVideoTotalItems(ctx context.Context, search string) (int64, error) {
-	expression := searchExpression(search)
+func (r *Repository) GetVideoTotalItems(ctx context.Context, filter model.VideoFilter) (int64, error) {
+	terms := parseSearch(filter.Search.Query)
 
-	return TotalItems(ctx, r.ex, Videos.ID, searchTable(), expression)
+	return TotalItems(ctx, r.ex, Videos.ID, searchTable(terms), filterExpression(terms, filter))
 }
 
+// UpdateVideoGame makes gameID the primary game of a video, adding it first
+// if needed. Other games keep their order behind it.
 func (r *Repository) UpdateVideoGame(ctx context.Context, videoID string, gameID int64) error {
-	stmt := Videos.UPDATE(Videos.GameID, Videos.UpdatedAt).
-		SET(
-			sqlite.Int(gameID),
-			sqlite.CURRENT_TIMESTAMP(),
-		).
-		WHERE(Videos.ID.EQ(sqlite.String(videoID)))
+	return r.Transaction(ctx, func(tx *Repository) error {
+		shift := VideoGames.UPDATE(VideoGames.Position).
+			SET(VideoGames.Position.ADD(sqlite.Int(1))).
+			WHERE(
+				VideoGames.VideoID.EQ(sqlite.String(videoID)).
+					AND(VideoGames.GameID.NOT_EQ(sqlite.Int(gameID))),
+			)
+
+		if _, err := shift.ExecContext(ctx, tx.ex); err != nil {
+			return FormatError("update video game", err)
+		}
+
+		stmt := VideoGames.INSERT(VideoGames.VideoID, VideoGames.GameID, VideoGames.Position).
+			VALUES(videoID, gameID, 0).
+			ON_CONFLICT(VideoGames.VideoID, VideoGames.GameID).
+			DO_UPDATE(sqlite.SET(
+				VideoGames.Position.SET(sqlite.Int(0)),
+			))
+
+		if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+			return FormatError("update video game", err)
+		}
+
+		return tx.syncPrimaryGame(ctx, videoID)
+	})
+}
+
+// DeleteVideoGame removes the primary game of a video; the next game, if
+// any, becomes primary.
+func (r *Repository) DeleteVideoGame(ctx context.Context, videoID string) error {
+	return r.Transaction(ctx, func(tx *Repository) error {
+		stmt := VideoGames.DELETE().WHERE(
+			VideoGames.VideoID.EQ(sqlite.String(videoID)).
+				AND(VideoGames.GameID.IN(
+					sqlite.SELECT(VideoGames.GameID).
+						FROM(VideoGames).
+						WHERE(VideoGames.VideoID.EQ(sqlite.String(videoID))).
+						ORDER_BY(VideoGames.Position.ASC(), VideoGames.CreatedAt.ASC()).
+						LIMIT(1),
+				)),
+		)
+
+		if _, err := stmt.ExecContext(ctx, tx.ex); err != nil {
+			return FormatError("delete video game", err)
+		}
+
+		return tx.syncPrimaryGame(ctx, videoID)
+	})
+}
+
+// videoDetailColumns are the columns filled in from videos.list.
+var videoDetailColumns = sqlite.ColumnList{
+	Videos.DurationSeconds,
+	Videos.ViewCount,
+	Videos.LikeCount,
+	Videos.CommentCount,
+	Videos.Tags,
+	Videos.CategoryID,
+	Videos.LiveStatus,
+	Videos.DefaultLanguage,
+	Videos.DetailsUpdatedAt,
+	Videos.StatsUpdatedAt,
+}
+
+// CreateVideo inserts a video. A non-nil GameID becomes its primary game.
+func (r *Repository) CreateVideo(ctx context.Context, video repoModel.Videos) error {
+	return r.Transaction(ctx, func(tx *Repository) error {
+		stmt := Videos.INSERT(Videos.ID, Videos.Title, Videos.Description, Videos.Thumbnail, Videos.PublishedAt, Videos.ChannelID, Videos.GameID, Videos.CreatedAt, Videos.UpdatedAt, videoDetailColumns).
+			VALUES(
+				video.ID,
+				video.Title,
+				video.Description,
+				video.Thumbnail,
+				video.PublishedAt,
+				video.ChannelID,
+				video.GameID,
+				time.Now(),
+				time.Now(),
+				video.DurationSeconds,
+				video.ViewCount,
+				video.LikeCount,
+				video.CommentCount,
+				video.Tags,
+				video.CategoryID,
+				video.LiveStatus,
+				video.DefaultLanguage,
+				video.DetailsUpdatedAt,
+				video.StatsUpdatedAt,
+			)
+
+		_, err := stmt.ExecContext(ctx, tx.ex)
+		if err != nil {
+			return FormatError("create video", err)
+		}
+
+		if err := tx.insertFirstVideoStats(ctx, video); err != nil {
+			return err
+		}
+
+		if video.GameID == nil || video.ID == nil {
+			return nil
+		}
 
-	_, err := stmt.ExecContext(ctx, r.ex)
+		return tx.UpdateVideoGame(ctx, *video.ID, int64(*video.GameID))
+	})
+}
+
+// upsertVideosQuery stores a page of videos, passed as a JSON array, in a
+// single statement: D1 binds at most 100 parameters a statement, which
+// isn't enough for the values of more than a few videos. A stored video
+// only takes the new title, thumbnail and status, and its details if it has
+// never been looked up; one that wouldn't change is left alone. Every video
+// written is returned, with whether it was inserted: stored is read before
+// any video is written, since the videos being inserted are joined with it.
+// #looked_up stands for a stored video being looked up for the first time.
+var upsertVideosQuery = strings.ReplaceAll(`
+WITH page AS (
+	SELECT value FROM json_each(#videos)
+),
+stored AS MATERIALIZED (
+	SELECT id FROM videos WHERE id IN (SELECT value ->> 'id' FROM page)
+)
+INSERT INTO videos (
+	id, title, description, thumbnail, published_at, channel_id, status,
+	duration_seconds, view_count, like_count, comment_count, tags, category_id,
+	live_status, default_language, details_updated_at, stats_updated_at,
+	created_at, updated_at
+)
+SELECT
+	value ->> 'id', value ->> 'title', value ->> 'description',
+	value ->> 'thumbnail', datetime(value ->> 'published_at'),
+	value ->> 'channel_id', value ->> 'status',
+	value ->> 'duration_seconds', value ->> 'view_count',
+	value ->> 'like_count', value ->> 'comment_count', value ->> 'tags',
+	value ->> 'category_id', value ->> 'live_status',
+	value ->> 'default_language', datetime(value ->> 'details_updated_at'),
+	datetime(value ->> 'stats_updated_at'), #now, #now
+FROM page
+LEFT JOIN stored ON stored.id = value ->> 'id'
+WHERE true
+ON CONFLICT (id) DO UPDATE SET
+	title = excluded.title,
+	thumbnail = excluded.thumbnail,
+	status = excluded.status,
+	description = iif(#looked_up, coalesce(excluded.description, videos.description), videos.description),
+	duration_seconds = iif(#looked_up, excluded.duration_seconds, videos.duration_seconds),
+	view_count = iif(#looked_up, excluded.view_count, videos.view_count),
+	like_count = iif(#looked_up, excluded.like_count, videos.like_count),
+	comment_count = iif(#looked_up, excluded.comment_count, videos.comment_count),
+	tags = iif(#looked_up, excluded.tags, videos.tags),
+	category_id = iif(#looked_up, excluded.category_id, videos.category_id),
+	live_status = iif(#looked_up, excluded.live_status, videos.live_status),
+	default_language = iif(#looked_up, excluded.default_language, videos.default_language),
+	details_updated_at = iif(#looked_up, excluded.details_updated_at, videos.details_updated_at),
+	stats_updated_at = iif(#looked_up, excluded.stats_updated_at, videos.stats_updated_at),
+	updated_at = excluded.updated_at
+WHERE videos.title IS NOT excluded.title
+	OR videos.thumbnail IS NOT excluded.thumbnail
+	OR videos.status IS NOT excluded.status
+	OR #looked_up
+RETURNING id, id NOT IN stored AS inserted`,
+	"#looked_up", "(videos.details_updated_at IS NULL AND excluded.details_updated_at IS NOT NULL)",
+)
+
+// UpsertVideos stores a page of videos in one round trip: the new ones are
+// inserted, without games, and the stored ones updated as the sync found
+// them. It returns the IDs it inserted and those it updated; stored videos
+// that didn't change are in neither.
+func (r *Repository) UpsertVideos(ctx context.Context, videos []repoModel.Videos) (inserted, updated []string, err error) {
+	if len(videos) == 0 {
+		return nil, nil, nil
+	}
+
+	page, err := json.Marshal(videos)
 	if err != nil {
-		return FormatError("update video game", err)
+		return nil, nil, FormatError("upsert videos", err)
 	}
 
-	return nil
+	stmt := sqlite.RawStatement(upsertVideosQuery, sqlite.RawArgs{"#videos": string(page), "#now": time.Now()})
+
+	var rows []struct {
+		ID       string `alias:"id"`
+		Inserted bool   `alias:"inserted"`
+	}
+
+	if err := stmt.QueryContext(ctx, r.ex, &rows); err != nil {
+		return nil, nil, FormatError("upsert videos", err)
+	}
+
+	for _, row := range rows {
+		if row.Inserted {
+			inserted = append(inserted, row.ID)
+		} else {
+			updated = append(updated, row.ID)
+		}
+	}
+
+	return inserted, updated, nil
 }
 
-func (r *Repository) DeleteVideoGame(ctx context.Context, videoID string) error {
-	stmt := Videos.UPDATE(Videos.GameID, Videos.UpdatedAt).
+// UpdateVideoDetails stores the details of a video looked up with
+// videos.list, along with its description.
+func (r *Repository) UpdateVideoDetails(ctx context.Context, video repoModel.Videos) error {
+	return r.Transaction(ctx, func(tx *Repository) error {
+		if err := tx.updateVideoDetails(ctx, video); err != nil {
+			return err
+		}
+		return tx.insertFirstVideoStats(ctx, video)
+	})
+}
+
+// insertFirstVideoStats records the counts a video was stored with as its
+// first stats snapshot.
+func (r *Repository) insertFirstVideoStats(ctx context.Context, video repoModel.Videos) error {
+	if video.ID == nil || video.StatsUpdatedAt == nil {
+		return nil
+	}
+
+	return r.insertVideoStats(ctx, repoModel.VideoStats{
+		VideoID:      *video.ID,
+		CapturedAt:   *video.StatsUpdatedAt,
+		ViewCount:    video.ViewCount,
+		LikeCount:    video.LikeCount,
+		CommentCount: video.CommentCount,
+	})
+}
+
+func (r *Repository) updateVideoDetails(ctx context.Context, video repoModel.Videos) error {
+	stmt := Videos.UPDATE(Videos.Description, Videos.UpdatedAt, videoDetailColumns).
 		SET(
-			sqlite.NULL,
-			sqlite.CURRENT_TIMESTAMP(),
+			video.Description,
+			time.Now(),
+			video.DurationSeconds,
+			video.ViewCount,
+			video.LikeCount,
+			video.CommentCount,
+			video.Tags,
+			video.CategoryID,
+			video.LiveStatus,
+			video.DefaultLanguage,
+			video.DetailsUpdatedAt,
+			video.StatsUpdatedAt,
 		).
-		WHERE(Videos.ID.EQ(sqlite.String(videoID)))
+		WHERE(Videos.ID.EQ(sqlite.String(*video.ID)))
 
-	_, err := stmt.ExecContext(ctx, r.ex)
-	if err != nil {
-		return FormatError("delete video game", err)
+	if _, err := stmt.ExecContext(ctx, r.ex); err != nil {
+		return FormatError("update video details", err)
 	}
 
 	return nil
 }
 
-func (r *Repository) CreateVideo(ctx context.Context, video repoModel.Videos) error {
-	stmt := Videos.INSERT(Videos.ID, Videos.Title, Videos.Thumbnail, Videos.PublishedAt, Videos.GameID, Videos.CreatedAt, Videos.UpdatedAt).
-		VALUES(
-			video.ID,
-			video.Title,
-			video.Thumbnail,
-			video.PublishedAt,
-			video.GameID,
-			time.Now(),
-			time.Now(),
-		)
+// GetStoredVideos returns the stored videos among ids, by ID.
+func (r *Repository) GetStoredVideos(ctx context.Context, ids []string) (map[string]repoModel.Videos, error) {
+	stored := make(map[string]repoModel.Videos, len(ids))
+	if len(ids) == 0 {
+		return stored, nil
+	}
+
+	var videos []repoModel.Videos
 
-	_, err := stmt.ExecContext(ctx, r.ex)
+	stmt := sqlite.SELECT(Videos.AllColumns).
+		FROM(Videos).
+		WHERE(Videos.ID.IN(stringList(ids)...))
+
+	err := stmt.QueryContext(ctx, r.ex, &videos)
 	if err != nil {
-		return FormatError("create video", err)
+		return nil, FormatError("get stored videos", err)
 	}
 
-	return nil
+	for _, video := range videos {
+		stored[*video.ID] = video
+	}
+
+	return stored, nil
+}
+
+// GetExistingVideoIDs returns which of ids are already stored.
+func (r *Repository) GetExistingVideoIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	existing := make(map[string]bool, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	var rows []struct {
+		ID string `alias:"videos.id"`
+	}
+
+	stmt := sqlite.SELECT(Videos.ID).
+		FROM(Videos).
+		WHERE(Videos.ID.IN(stringList(ids)...))
+
+	err := stmt.QueryContext(ctx, r.ex, &rows)
+	if err != nil {
+		return nil, FormatError("get existing video ids", err)
+	}
+
+	for _, row := range rows {
+		existing[row.ID] = true
+	}
+
+	return existing, nil
+}
+
+func stringList(values []string) []sqlite.Expression {
+	list := make([]sqlite.Expression, len(values))
+	for i, v := range values {
+		list[i] = sqlite.String(v)
+	}
+	return list
 }
 
 func (r *Repository) GetVideoByYouTubeID(ctx context.Context, youtubeID string) (*model.VideoResponse, error) {
 	var video VideoWithGame
 
-	stmt := selectVideos().WHERE(Videos.ID.EQ(sqlite.String(youtubeID)))
+	stmt := selectVideos(searchTerms{}, false).WHERE(Videos.ID.EQ(sqlite.String(youtubeID)))
 
 	err := stmt.QueryContext(ctx, r.ex, &video)
 	if err != nil {
@@ -168,10 +561,29 @@ func convertToVideoResponses(videos []VideoWithGame) []model.VideoResponse {
 		response := model.VideoResponse{
 			ID:          *v.ID,
 			Title:       v.Title,
+			Description: v.Description,
 			Thumbnail:   v.Thumbnail,
 			PublishedAt: v.PublishedAt,
+			ChannelID:   v.ChannelID,
+			Status:      v.Status,
+			Episode:     v.Episode,
 			CreatedAt:   v.CreatedAt,
 			UpdatedAt:   v.UpdatedAt,
+			Match:       v.Match.response(),
+
+			DurationSeconds: v.DurationSeconds,
+			ViewCount:       v.ViewCount,
+			LikeCount:       v.LikeCount,
+			CommentCount:    v.CommentCount,
+			Tags:            decodeTags(v.Tags),
+			CategoryID:      v.CategoryID,
+			LiveStatus:      v.LiveStatus,
+			DefaultLanguage: v.DefaultLanguage,
+		}
+
+		if v.SeriesID != nil {
+			seriesID := int64(*v.SeriesID)
+			response.SeriesID = &seriesID
 		}
 
 		if v.Game != nil && v.Game.ID != nil {
@@ -187,3 +599,17 @@ func convertToVideoResponses(videos []VideoWithGame) []model.VideoResponse {
 
 	return responses
 }
+
+// decodeTags reads the JSON array in videos.tags, returning nil if there is
+// none or it can't be read.
+func decodeTags(tags *string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	var decoded []string
+	if err := json.Unmarshal([]byte(*tags), &decoded); err != nil {
+		return nil
+	}
+	return decoded
+}
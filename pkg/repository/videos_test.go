@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/K0ng2/zeedzad/db"
+	"github.com/K0ng2/zeedzad/db/d1test"
+	"github.com/K0ng2/zeedzad/model"
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+)
+
+func TestGetVideosScansJoinedColumns(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 119133, "elden-ring")
+	createTestVideo(t, r, "vid1", "Elden Ring EP.1", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	createTestVideo(t, r, "vid2", "Random vlog", time.Date(2024, 2, 1, 8, 30, 0, 0, time.UTC))
+	if err := r.UpdateVideoGame(ctx, "vid1", 119133); err != nil {
+		t.Fatalf("UpdateVideoGame: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		videos, err := r.GetVideos(ctx, model.Offset{Limit: 24}, model.VideoFilter{})
+		if err != nil {
+			t.Fatalf("GetVideos: %v", err)
+		}
+		if len(videos) != 2 {
+			t.Fatalf("got %d videos, want 2", len(videos))
+		}
+
+		matched := videos[0]
+		if matched.ID != "vid1" || matched.Title != "Elden Ring EP.1" {
+			t.Fatalf("video = %s %q, want vid1 \"Elden Ring EP.1\"", matched.ID, matched.Title)
+		}
+		if want := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC); !matched.PublishedAt.Equal(want) {
+			t.Fatalf("published_at = %v, want %v", matched.PublishedAt, want)
+		}
+		if matched.Game == nil {
+			t.Fatal("expected game to be set")
+		}
+		if matched.Game.ID != 119133 || matched.Game.Name != "elden-ring" {
+			t.Fatalf("game = %d %q, want 119133 \"elden-ring\"", matched.Game.ID, matched.Game.Name)
+		}
+		if matched.Game.URL == nil || *matched.Game.URL != "https://www.igdb.com/games/elden-ring" {
+			t.Fatalf("game url = %v", matched.Game.URL)
+		}
+
+		unmatched := videos[1]
+		if unmatched.ID != "vid2" || unmatched.Thumbnail != nil {
+			t.Fatalf("video = %s thumbnail %v, want vid2 without thumbnail", unmatched.ID, unmatched.Thumbnail)
+		}
+		if unmatched.Game != nil {
+			t.Fatalf("game = %+v, want nil", unmatched.Game)
+		}
+	}
+}
+
+func TestGetVideosSearch(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 1942, "the-witcher-3")
+	createTestVideo(t, r, "vid1", "Wild Hunt EP.1", time.Now())
+	createTestVideo(t, r, "vid2", "Stardew Valley EP.1", time.Now())
+	if err := r.UpdateVideoGame(ctx, "vid1", 1942); err != nil {
+		t.Fatalf("UpdateVideoGame: %v", err)
+	}
+
+	tests := []struct {
+		search string
+		want   int64
+	}{
+		{"", 2},
+		{"stardew", 1},
+		{"witcher", 1},
+		{"EP.1", 2},
+		{"it's", 0},
+	}
+
+	for _, tt := range tests {
+		videos, err := r.GetVideos(ctx, model.Offset{Limit: 24}, model.VideoFilter{Search: model.Search{Query: tt.search}})
+		if err != nil {
+			t.Fatalf("GetVideos(%q): %v", tt.search, err)
+		}
+		if int64(len(videos)) != tt.want {
+			t.Errorf("GetVideos(%q) returned %d videos, want %d", tt.search, len(videos), tt.want)
+		}
+
+		total, err := r.GetVideoTotalItems(ctx, model.VideoFilter{Search: model.Search{Query: tt.search}})
+		if err != nil {
+			t.Fatalf("GetVideoTotalItems(%q): %v", tt.search, err)
+		}
+		if total != tt.want {
+			t.Errorf("GetVideoTotalItems(%q) = %d, want %d", tt.search, total, tt.want)
+		}
+	}
+}
+
+func TestDeleteVideoGame(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	createTestGame(t, r, 7346, "zelda")
+	createTestVideo(t, r, "vid1", "Zelda EP.1", time.Now())
+	if err := r.UpdateVideoGame(ctx, "vid1", 7346); err != nil {
+		t.Fatalf("UpdateVideoGame: %v", err)
+	}
+	if err := r.DeleteVideoGame(ctx, "vid1"); err != nil {
+		t.Fatalf("DeleteVideoGame: %v", err)
+	}
+
+	video, err := r.GetVideoByYouTubeID(ctx, "vid1")
+	if err != nil {
+		t.Fatalf("GetVideoByYouTubeID: %v", err)
+	}
+	if video.Game != nil {
+		t.Fatalf("game = %+v, want nil", video.Game)
+	}
+}
+
+func TestUpsertVideos(t *testing.T) {
+	ctx := context.Background()
+	srv := d1test.NewServer(t)
+	r := NewRepository(srv.NewDatabase(t))
+
+	publishedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	createTestVideo(t, r, "vid1", "Hades EP.1", publishedAt)
+	createTestVideo(t, r, "vid2", "Hades EP.2", publishedAt)
+	createTestVideo(t, r, "vid3", "Hades EP.3", publishedAt)
+
+	duration := int32(3600)
+	lookedUp := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	channelID := "UCsGx1qSnAS2P1YCJPYnYVUg"
+	ptr := func(s string) *string { return &s }
+	page := []repoModel.Videos{
+		{ID: ptr("vid1"), Title: "Hades EP.1", PublishedAt: publishedAt, Status: model.VideoStatusAvailable},
+		{ID: ptr("vid2"), Title: "Hades II EP.2", PublishedAt: publishedAt, Status: model.VideoStatusAvailable},
+		{ID: ptr("vid3"), Title: "Hades EP.3", PublishedAt: publishedAt, Status: model.VideoStatusAvailable, DurationSeconds: &duration, DetailsUpdatedAt: &lookedUp},
+		{ID: ptr("vid4"), Title: "Hades EP.4", PublishedAt: publishedAt.AddDate(0, 0, 1), ChannelID: &channelID, Status: model.VideoStatusAvailable, DurationSeconds: &duration, DetailsUpdatedAt: &lookedUp},
+	}
+
+	before := srv.Requests()
+	inserted, updated, err := r.UpsertVideos(ctx, page)
+	if err != nil {
+		t.Fatalf("UpsertVideos: %v", err)
+	}
+	if requests := srv.Requests() - before; requests != 1 {
+		t.Fatalf("UpsertVideos made %d requests, want 1", requests)
+	}
+	if !slices.Equal(inserted, []string{"vid4"}) {
+		t.Fatalf("inserted = %v, want vid4", inserted)
+	}
+	slices.Sort(updated)
+	if !slices.Equal(updated, []string{"vid2", "vid3"}) {
+		t.Fatalf("updated = %v, want the renamed and looked up videos", updated)
+	}
+
+	stored, err := r.GetStoredVideos(ctx, []string{"vid2", "vid3", "vid4"})
+	if err != nil {
+		t.Fatalf("GetStoredVideos: %v", err)
+	}
+	if stored["vid2"].Title != "Hades II EP.2" {
+		t.Fatalf("vid2 title = %q, want the new title", stored["vid2"].Title)
+	}
+	if d := stored["vid3"].DurationSeconds; d == nil || *d != duration {
+		t.Fatalf("vid3 duration = %v, want the looked up duration", d)
+	}
+	vid4 := stored["vid4"]
+	if !vid4.PublishedAt.Equal(publishedAt.AddDate(0, 0, 1)) || vid4.ChannelID == nil || vid4.DetailsUpdatedAt == nil || !vid4.DetailsUpdatedAt.Equal(lookedUp) {
+		t.Fatalf("vid4 = %+v, want it stored as given", vid4)
+	}
+
+	// Details are only filled in once; the page's stale copy doesn't
+	// overwrite them.
+	page[2].DurationSeconds = nil
+	if _, updated, err := r.UpsertVideos(ctx, page); err != nil || len(updated) != 0 {
+		t.Fatalf("UpsertVideos again: updated %v, %v; want nothing", updated, err)
+	}
+
+	// The upsert is a write: a transient failure is returned rather than
+	// retried, as the page may have been stored already.
+	srv.Fail(1, http.StatusServiceUnavailable, 0, "unavailable")
+	before = srv.Requests()
+	if _, _, err := r.UpsertVideos(ctx, page); !errors.Is(err, db.ErrTransient) {
+		t.Fatalf("UpsertVideos on a transient failure: %v, want ErrTransient", err)
+	}
+	if requests := srv.Requests() - before; requests != 1 {
+		t.Fatalf("failing UpsertVideos made %d requests, want 1", requests)
+	}
+}
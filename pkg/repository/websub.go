@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-jet/jet/v2/sqlite"
+
+	repoModel "github.com/K0ng2/zeedzad/repository/model"
+	. "github.com/K0ng2/zeedzad/repository/table"
+)
+
+// GetWebSubSubscriptions returns the WebSub subscription of every channel
+// that has one.
+func (r *Repository) GetWebSubSubscriptions(ctx context.Context) ([]repoModel.WebsubSubscriptions, error) {
+	subscriptions := []repoModel.WebsubSubscriptions{}
+
+	stmt := sqlite.SELECT(WebsubSubscriptions.AllColumns).
+		FROM(WebsubSubscriptions)
+
+	err := stmt.QueryContext(ctx, r.ex, &subscriptions)
+	if err != nil {
+		return nil, FormatError("get websub subscriptions", err)
+	}
+
+	return subscriptions, nil
+}
+
+// GetWebSubSubscription returns the WebSub subscription of a channel.
+func (r *Repository) GetWebSubSubscription(ctx context.Context, channelID string) (*repoModel.WebsubSubscriptions, error) {
+	var subscription repoModel.WebsubSubscriptions
+
+	stmt := sqlite.SELECT(WebsubSubscriptions.AllColumns).
+		FROM(WebsubSubscriptions).
+		WHERE(WebsubSubscriptions.ChannelID.EQ(sqlite.String(channelID)))
+
+	err := stmt.QueryContext(ctx, r.ex, &subscription)
+	if err != nil {
+		return nil, FormatError("get websub subscription", err)
+	}
+
+	return &subscription, nil
+}
+
+// SaveWebSubSubscription creates or replaces the WebSub subscription of a
+// channel.
+func (r *Repository) SaveWebSubSubscription(ctx context.Context, subscription repoModel.WebsubSubscriptions) error {
+	stmt := WebsubSubscriptions.INSERT(WebsubSubscriptions.AllColumns).
+		MODEL(subscription).
+		ON_CONFLICT(WebsubSubscriptions.ChannelID).
+		DO_UPDATE(sqlite.SET(
+			WebsubSubscriptions.Topic.SET(WebsubSubscriptions.EXCLUDED.Topic),
+			WebsubSubscriptions.Status.SET(WebsubSubscriptions.EXCLUDED.Status),
+			WebsubSubscriptions.LeaseSeconds.SET(WebsubSubscriptions.EXCLUDED.LeaseSeconds),
+			WebsubSubscriptions.RequestedAt.SET(WebsubSubscriptions.EXCLUDED.RequestedAt),
+			WebsubSubscriptions.VerifiedAt.SET(WebsubSubscriptions.EXCLUDED.VerifiedAt),
+			WebsubSubscriptions.ExpiresAt.SET(WebsubSubscriptions.EXCLUDED.ExpiresAt),
+			WebsubSubscriptions.LastNotifiedAt.SET(WebsubSubscriptions.EXCLUDED.LastNotifiedAt),
+		))
+
+	if _, err := stmt.ExecContext(ctx, r.ex); err != nil {
+		return FormatError("save websub subscription", err)
+	}
+
+	return nil
+}
+
+// DeleteWebSubSubscription forgets the WebSub subscription of a channel.
+func (r *Repository) DeleteWebSubSubscription(ctx context.Context, channelID string) error {
+	stmt := WebsubSubscriptions.DELETE().
+		WHERE(WebsubSubscriptions.ChannelID.EQ(sqlite.String(channelID)))
+
+	if _, err := stmt.ExecContext(ctx, r.ex); err != nil {
+		return FormatError("delete websub subscription", err)
+	}
+
+	return nil
+}
@@ -15,11 +15,12 @@ import (
 
 	"github.com/K0ng2/zeedzad/docs"
 	"github.com/K0ng2/zeedzad/handler"
+	"github.com/K0ng2/zeedzad/metrics"
 	"github.com/K0ng2/zeedzad/web"
 )
 
 // @BasePath /api
-func NewRouter(handler *handler.Handler) *fiber.App {
+func NewRouter(handler *handler.Handler, m *metrics.Metrics) *fiber.App {
 	app := fiber.New()
 
 	// Set up the Fiber app with middlewares
@@ -27,8 +28,10 @@ func NewRouter(handler *handler.Handler) *fiber.App {
 	app.Use(logger.New())
 	app.Use(recover.New())
 	app.Get(healthcheck.StartupEndpoint, healthcheck.New())
+	app.Get("/metrics", m.Handler())
 
 	api := app.Group("api")
+	api.Use(m.Middleware())
 
 	api.Get("/swagger/*", adaptor.HTTPHandler(httpSwagger.Handler(
 		httpSwagger.InstanceName(docs.SwaggerInfo.InfoInstanceName),
@@ -40,15 +43,57 @@ func NewRouter(handler *handler.Handler) *fiber.App {
 	// Video routes
 	api.Get("/videos", handler.GetVideos)
 	api.Post("/videos/sync", handler.SyncYouTubeVideos)
+	api.Post("/videos/stats/refresh", handler.RefreshVideoStats)
 	api.Get("/videos/:id", handler.GetVideoByID)
+	api.Get("/videos/:id/stats", handler.GetVideoStats)
+	api.Get("/videos/:id/edits", handler.GetVideoEdits)
 	api.Put("/videos/:id/game", handler.UpdateVideoGame)
 	api.Delete("/videos/:id/game", handler.DeleteVideoGame)
+	api.Post("/videos/:id/games", handler.AddVideoGame)
+	api.Put("/videos/:id/games/order", handler.ReorderVideoGames)
+	api.Delete("/videos/:id/games/:gameId", handler.RemoveVideoGame)
+
+	// Channel routes
+	api.Get("/channels", handler.GetChannels)
+	api.Post("/channels", handler.CreateChannel)
+	api.Get("/channels/:id", handler.GetChannelByID)
+	api.Put("/channels/:id", handler.UpdateChannel)
+	api.Delete("/channels/:id", handler.DeleteChannel)
+	api.Post("/channels/:id/sync", handler.SyncChannel)
+
+	// Sync history and job routes
+	api.Get("/sync/runs", handler.GetSyncRuns)
+	api.Get("/sync/runs/:id", handler.GetSyncRunByID)
+	api.Get("/sync/jobs", handler.GetSyncJobs)
+	api.Get("/sync/jobs/:id", handler.GetSyncJob)
+	api.Get("/sync/jobs/:id/events", handler.StreamSyncJob)
+	api.Delete("/sync/jobs/:id", handler.CancelSyncJob)
+
+	// Admin routes
+	api.Get("/admin/quota", handler.GetQuotaUsage)
+
+	// WebSub callbacks
+	api.Get("/websub/callback/:id", handler.VerifyWebSub)
+	api.Post("/websub/callback/:id", handler.ReceiveWebSub)
 
 	// Game routes
 	api.Get("/games", handler.GetGames)
 	api.Get("/games/:id", handler.GetGameByID)
 	api.Post("/games", handler.CreateGame)
 	api.Get("/games/igdb/search", handler.SearchIGDBGames)
+	api.Post("/games/enrich", handler.EnrichGames)
+	api.Post("/games/:id/metadata", handler.RefreshGameMetadata)
+
+	// Match review routes
+	api.Get("/match-suggestions", handler.GetMatchSuggestions)
+	api.Post("/match-suggestions/review", handler.ReviewMatchSuggestions)
+	api.Post("/match-suggestions/:id/accept", handler.AcceptMatchSuggestion)
+	api.Post("/match-suggestions/:id/reject", handler.RejectMatchSuggestion)
+
+	// Series routes
+	api.Get("/series", handler.GetSeries)
+	api.Post("/series/infer", handler.InferSeries)
+	api.Get("/series/:id", handler.GetSeriesByID)
 
 	fSys, err := fs.Sub(web.EmbeddedFiles, "public")
 	if err != nil {
@@ -0,0 +1,321 @@
+// Package websubtest provides an in-process stand-in for YouTube's WebSub
+// (PubSubHubbub) hub, for use in tests.
+//
+// The hub takes subscribe and unsubscribe requests, verifies them with the
+// subscriber's callback and keeps the subscriptions it verified.
+// Hub.Publish delivers a notification to the subscribers of a topic, signed
+// with their secret, and Feed and DeletedFeed build notifications like the
+// ones YouTube sends.
+package websubtest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DefaultLeaseSeconds is the lease the hub grants when none is asked for,
+// as on YouTube's hub.
+const DefaultLeaseSeconds = 5 * 24 * 60 * 60
+
+// Hub is a fake WebSub hub.
+type Hub struct {
+	*httptest.Server
+
+	// Subscribers sends verification requests and notifications to the
+	// callbacks, so tests can route them to an app that isn't listening.
+	Subscribers *http.Client
+	// MaxLeaseSeconds caps the leases the hub grants, if set.
+	MaxLeaseSeconds int
+
+	mu            sync.Mutex
+	subscriptions map[subscriptionKey]Subscription
+	requests      int
+}
+
+type subscriptionKey struct {
+	callback string
+	topic    string
+}
+
+// Subscription is a verified subscription.
+type Subscription struct {
+	Callback     string
+	Topic        string
+	Secret       string
+	LeaseSeconds int
+	ExpiresAt    time.Time
+}
+
+// NewHub starts a fake hub that is shut down when the test ends.
+func NewHub(tb testing.TB) *Hub {
+	tb.Helper()
+
+	h := &Hub{
+		Subscribers:   http.DefaultClient,
+		subscriptions: map[subscriptionKey]Subscription{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /subscribe", h.subscribe)
+
+	h.Server = httptest.NewServer(mux)
+	tb.Cleanup(h.Close)
+
+	return h
+}
+
+// SubscribeURL is the URL subscribers send their requests to.
+func (h *Hub) SubscribeURL() string {
+	return h.URL + "/subscribe"
+}
+
+// ChannelTopic is the feed of a channel's uploads, the topic subscribers
+// ask for.
+func ChannelTopic(channelID string) string {
+	return "https://www.youtube.com/xml/feeds/videos.xml?channel_id=" + url.QueryEscape(channelID)
+}
+
+// Subscriptions returns the verified subscriptions.
+func (h *Hub) Subscriptions() []Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscriptions := make([]Subscription, 0, len(h.subscriptions))
+	for _, s := range h.subscriptions {
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions
+}
+
+// Requests returns the number of subscribe and unsubscribe requests the hub
+// has received.
+func (h *Hub) Requests() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.requests
+}
+
+// subscribe handles a subscribe or unsubscribe request. Unlike a real hub,
+// it verifies the request before it answers, so tests needn't wait.
+func (h *Hub) subscribe(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.requests++
+	h.mu.Unlock()
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mode, topic, callback := r.PostForm.Get("hub.mode"), r.PostForm.Get("hub.topic"), r.PostForm.Get("hub.callback")
+	if mode != "subscribe" && mode != "unsubscribe" {
+		http.Error(w, "hub.mode must be subscribe or unsubscribe", http.StatusBadRequest)
+		return
+	}
+	if topic == "" || callback == "" {
+		http.Error(w, "hub.topic and hub.callback are required", http.StatusBadRequest)
+		return
+	}
+
+	lease := DefaultLeaseSeconds
+	if s := r.PostForm.Get("hub.lease_seconds"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid hub.lease_seconds", http.StatusBadRequest)
+			return
+		}
+		lease = n
+	}
+	if h.MaxLeaseSeconds > 0 {
+		lease = min(lease, h.MaxLeaseSeconds)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+
+	if !h.verify(mode, topic, callback, lease) {
+		return
+	}
+
+	key := subscriptionKey{callback: callback, topic: topic}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if mode == "unsubscribe" {
+		delete(h.subscriptions, key)
+		return
+	}
+	h.subscriptions[key] = Subscription{
+		Callback:     callback,
+		Topic:        topic,
+		Secret:       r.PostForm.Get("hub.secret"),
+		LeaseSeconds: lease,
+		ExpiresAt:    time.Now().Add(time.Duration(lease) * time.Second),
+	}
+}
+
+// verify asks the subscriber to confirm a request by echoing a challenge.
+func (h *Hub) verify(mode, topic, callback string, lease int) bool {
+	challenge := rand.Text()
+
+	query := url.Values{
+		"hub.mode":      {mode},
+		"hub.topic":     {topic},
+		"hub.challenge": {challenge},
+	}
+	if mode == "subscribe" {
+		query.Set("hub.lease_seconds", strconv.Itoa(lease))
+	}
+
+	resp, err := h.Subscribers.Get(withQuery(callback, query))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	return err == nil && resp.StatusCode/100 == 2 && string(body) == challenge
+}
+
+// Deny drops a subscription and tells the subscriber the hub refused it.
+func (h *Hub) Deny(callback, topic, reason string) error {
+	h.mu.Lock()
+	delete(h.subscriptions, subscriptionKey{callback: callback, topic: topic})
+	h.mu.Unlock()
+
+	resp, err := h.Subscribers.Get(withQuery(callback, url.Values{
+		"hub.mode":   {"denied"},
+		"hub.topic":  {topic},
+		"hub.reason": {reason},
+	}))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Publish delivers a notification to every subscriber of topic, with an
+// X-Hub-Signature if the subscription has a secret. It returns an error for
+// each subscriber that didn't acknowledge it.
+func (h *Hub) Publish(topic string, feed []byte) error {
+	var errs []error
+
+	for _, s := range h.Subscriptions() {
+		if s.Topic != topic {
+			continue
+		}
+
+		req, err := http.NewRequest(http.MethodPost, s.Callback, bytes.NewReader(feed))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		req.Header.Set("Content-Type", "application/atom+xml")
+		if s.Secret != "" {
+			req.Header.Set("X-Hub-Signature", Sign(s.Secret, feed))
+		}
+
+		resp, err := h.Subscribers.Do(req)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode/100 != 2 {
+			errs = append(errs, fmt.Errorf("%s: %s", s.Callback, resp.Status))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Sign returns the X-Hub-Signature of a notification, as YouTube's hub
+// signs it.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func withQuery(callback string, query url.Values) string {
+	if strings.Contains(callback, "?") {
+		return callback + "&" + query.Encode()
+	}
+	return callback + "?" + query.Encode()
+}
+
+// Entry is a video in a notification.
+type Entry struct {
+	VideoID     string
+	ChannelID   string
+	Title       string
+	PublishedAt time.Time
+}
+
+// Feed builds the notification YouTube sends when videos are published or
+// updated.
+func Feed(entries ...Entry) []byte {
+	var b bytes.Buffer
+
+	b.WriteString(`<?xml version='1.0' encoding='UTF-8'?>` + "\n")
+	b.WriteString(`<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns="http://www.w3.org/2005/Atom">` + "\n")
+	b.WriteString(`<link rel="hub" href="https://pubsubhubbub.appspot.com"/>` + "\n")
+	b.WriteString(`<title>YouTube video feed</title>` + "\n")
+	fmt.Fprintf(&b, "<updated>%s</updated>\n", time.Now().UTC().Format(time.RFC3339Nano))
+
+	for _, e := range entries {
+		b.WriteString("<entry>\n")
+		fmt.Fprintf(&b, "<id>yt:video:%s</id>\n", escape(e.VideoID))
+		fmt.Fprintf(&b, "<yt:videoId>%s</yt:videoId>\n", escape(e.VideoID))
+		fmt.Fprintf(&b, "<yt:channelId>%s</yt:channelId>\n", escape(e.ChannelID))
+		fmt.Fprintf(&b, "<title>%s</title>\n", escape(e.Title))
+		fmt.Fprintf(&b, `<link rel="alternate" href="https://www.youtube.com/watch?v=%s"/>`+"\n", escape(e.VideoID))
+		fmt.Fprintf(&b, "<author><name>%s</name><uri>https://www.youtube.com/channel/%s</uri></author>\n", escape(e.ChannelID), escape(e.ChannelID))
+		fmt.Fprintf(&b, "<published>%s</published>\n", e.PublishedAt.UTC().Format(time.RFC3339))
+		fmt.Fprintf(&b, "<updated>%s</updated>\n", time.Now().UTC().Format(time.RFC3339Nano))
+		b.WriteString("</entry>\n")
+	}
+
+	b.WriteString("</feed>\n")
+	return b.Bytes()
+}
+
+// DeletedFeed builds the notification YouTube sends when a video is
+// deleted.
+func DeletedFeed(channelID, videoID string) []byte {
+	var b bytes.Buffer
+
+	b.WriteString(`<?xml version='1.0' encoding='UTF-8'?>` + "\n")
+	b.WriteString(`<feed xmlns:at="http://purl.org/atompub/tombstones/1.0" xmlns="http://www.w3.org/2005/Atom">` + "\n")
+	fmt.Fprintf(&b, `<at:deleted-entry ref="yt:video:%s" when="%s">`+"\n", escape(videoID), time.Now().UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, `<link href="https://www.youtube.com/watch?v=%s"/>`+"\n", escape(videoID))
+	fmt.Fprintf(&b, "<at:by><name>%s</name><uri>https://www.youtube.com/channel/%s</uri></at:by>\n", escape(channelID), escape(channelID))
+	b.WriteString("</at:deleted-entry>\n</feed>\n")
+
+	return b.Bytes()
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,473 @@
+// Package youtubetest provides an in-process stand-in for the YouTube Data
+// API v3, for use in tests.
+//
+// Only the calls the app makes are implemented: channels.list, which looks
+// channels up by ID, handle or username, playlistItems.list, which pages
+// through a channel's uploads newest first, and videos.list. Server.Fail
+// injects API errors, and Server.Hold keeps calls waiting.
+package youtubetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
+)
+
+// DefaultPageSize is the most items a list call returns, as on YouTube.
+const DefaultPageSize = 50
+
+// maxIDs is the most IDs a videos.list call accepts.
+const maxIDs = 50
+
+// Server is a fake YouTube Data API.
+type Server struct {
+	*httptest.Server
+
+	// PageSize caps the number of items per page, so tests can exercise
+	// paging with a handful of videos.
+	PageSize int
+
+	mu       sync.Mutex
+	channels map[string]*channel
+	requests map[string]int
+	faults   map[string][]int
+	holds    map[string]chan struct{}
+}
+
+type channel struct {
+	Channel
+	uploads []Video // newest first
+}
+
+// Channel is a channel as the fake API reports it.
+type Channel struct {
+	ID                string
+	Handle            string // with the leading @
+	Username          string
+	Title             string
+	UploadsPlaylistID string
+}
+
+// Video is an upload as the fake API reports it.
+type Video struct {
+	ID          string
+	Title       string
+	Description string
+	PublishedAt time.Time
+
+	// Details reported by videos.list only.
+	Duration        time.Duration
+	Views           uint64
+	Likes           uint64
+	Comments        uint64
+	Tags            []string
+	CategoryID      string
+	DefaultLanguage string
+	// LiveStatus is "", "upcoming", "live" or "completed".
+	LiveStatus string
+
+	// Privacy is "" for a public video, or "private" or "deleted". The
+	// playlist lists private and deleted videos without their title and
+	// thumbnail, and videos.list doesn't return them.
+	Privacy string
+}
+
+// NewServer starts a fake YouTube API that is shut down when the test ends.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+
+	s := &Server{
+		PageSize: DefaultPageSize,
+		channels: map[string]*channel{},
+		requests: map[string]int{},
+		faults:   map[string][]int{},
+		holds:    map[string]chan struct{}{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /youtube/v3/channels", s.listChannels)
+	mux.HandleFunc("GET /youtube/v3/playlistItems", s.listPlaylistItems)
+	mux.HandleFunc("GET /youtube/v3/videos", s.listVideos)
+
+	s.Server = httptest.NewServer(s.count(mux))
+	tb.Cleanup(s.Close)
+
+	return s
+}
+
+// ClientOptions points a YouTube client at the fake server.
+func (s *Server) ClientOptions() []option.ClientOption {
+	return []option.ClientOption{
+		option.WithEndpoint(s.URL + "/"),
+		option.WithAPIKey("youtubetest-key"),
+		option.WithHTTPClient(s.Client()),
+	}
+}
+
+// AddChannel creates a channel with an empty uploads playlist.
+func (s *Server) AddChannel(ch Channel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channels[ch.ID] = &channel{Channel: ch}
+}
+
+// Upload adds videos to the top of a channel's uploads playlist, in order,
+// so the last one given becomes the newest.
+func (s *Server) Upload(channelID string, videos ...Video) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := s.channels[channelID]
+	for _, v := range videos {
+		ch.uploads = append([]Video{v}, ch.uploads...)
+	}
+}
+
+// Update replaces the upload with the same ID as v, keeping its place in
+// the playlist.
+func (s *Server) Update(v Video) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ch := range s.channels {
+		for i := range ch.uploads {
+			if ch.uploads[i].ID == v.ID {
+				ch.uploads[i] = v
+			}
+		}
+	}
+}
+
+// Remove takes a video out of its channel's uploads playlist.
+func (s *Server) Remove(videoID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ch := range s.channels {
+		ch.uploads = slices.DeleteFunc(ch.uploads, func(v Video) bool { return v.ID == videoID })
+	}
+}
+
+// Fail makes the next n calls to a resource ("channels", "playlistItems", "videos")
+// fail with the given HTTP status.
+func (s *Server) Fail(resource string, n, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for range n {
+		s.faults[resource] = append(s.faults[resource], status)
+	}
+}
+
+// Hold makes calls to a resource wait until release is called, or the
+// client gives up on them. Calls are counted as they arrive.
+func (s *Server) Hold(resource string) (release func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hold := make(chan struct{})
+	s.holds[resource] = hold
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.holds, resource)
+			s.mu.Unlock()
+			close(hold)
+		})
+	}
+}
+
+// Requests returns how many calls have been made to a resource.
+func (s *Server) Requests(resource string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[resource]
+}
+
+func (s *Server) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resource := strings.TrimPrefix(r.URL.Path, "/youtube/v3/")
+
+		s.mu.Lock()
+		s.requests[resource]++
+		var status int
+		if faults := s.faults[resource]; len(faults) > 0 {
+			status, s.faults[resource] = faults[0], faults[1:]
+		}
+		hold := s.holds[resource]
+		s.mu.Unlock()
+
+		if hold != nil {
+			select {
+			case <-hold:
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if status != 0 {
+			writeError(w, status, http.StatusText(status))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) listChannels(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+
+	var matches []*channel
+	switch {
+	case query.Has("forHandle"):
+		handle := "@" + strings.TrimPrefix(query.Get("forHandle"), "@")
+		for _, ch := range s.channels {
+			if strings.EqualFold(ch.Handle, handle) {
+				matches = append(matches, ch)
+			}
+		}
+	case query.Has("forUsername"):
+		for _, ch := range s.channels {
+			if ch.Username != "" && strings.EqualFold(ch.Username, query.Get("forUsername")) {
+				matches = append(matches, ch)
+			}
+		}
+	default:
+		for _, id := range splitIDs(query["id"]) {
+			if ch, ok := s.channels[id]; ok {
+				matches = append(matches, ch)
+			}
+		}
+	}
+
+	response := &youtube.ChannelListResponse{Items: []*youtube.Channel{}}
+	for _, ch := range matches {
+		response.Items = append(response.Items, &youtube.Channel{
+			Id: ch.ID,
+			Snippet: &youtube.ChannelSnippet{
+				Title:     ch.Title,
+				CustomUrl: strings.ToLower(ch.Handle),
+				Thumbnails: &youtube.ThumbnailDetails{
+					High: &youtube.Thumbnail{Url: "https://yt3.ggpht.com/" + ch.ID + "=s800"},
+				},
+			},
+			ContentDetails: &youtube.ChannelContentDetails{
+				RelatedPlaylists: &youtube.ChannelContentDetailsRelatedPlaylists{Uploads: ch.UploadsPlaylistID},
+			},
+		})
+	}
+
+	writeJSON(w, response)
+}
+
+func (s *Server) listPlaylistItems(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+
+	var uploads []Video
+	found := false
+	for _, ch := range s.channels {
+		if ch.UploadsPlaylistID == query.Get("playlistId") {
+			uploads, found = ch.uploads, true
+		}
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, "playlistNotFound")
+		return
+	}
+
+	start := 0
+	if token := query.Get("pageToken"); token != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(token, "page-"))
+		if err != nil || !strings.HasPrefix(token, "page-") || n > len(uploads) {
+			writeError(w, http.StatusBadRequest, "invalidPageToken")
+			return
+		}
+		start = n
+	}
+
+	size := s.PageSize
+	if n, err := strconv.Atoi(query.Get("maxResults")); err == nil && n < size {
+		size = n
+	}
+	end := min(start+size, len(uploads))
+
+	response := &youtube.PlaylistItemListResponse{
+		Items:    []*youtube.PlaylistItem{},
+		PageInfo: &youtube.PageInfo{TotalResults: int64(len(uploads)), ResultsPerPage: int64(size)},
+	}
+	for _, v := range uploads[start:end] {
+		response.Items = append(response.Items, playlistItem(v))
+	}
+	if end < len(uploads) {
+		response.NextPageToken = "page-" + strconv.Itoa(end)
+	}
+
+	writeJSON(w, response)
+}
+
+func thumbnailURL(videoID string) string {
+	return "https://i.ytimg.com/vi/" + videoID + "/hqdefault.jpg"
+}
+
+func playlistItem(v Video) *youtube.PlaylistItem {
+	item := &youtube.PlaylistItem{
+		Id: "item-" + v.ID,
+		Snippet: &youtube.PlaylistItemSnippet{
+			Title:       v.Title,
+			Description: v.Description,
+			PublishedAt: v.PublishedAt.UTC().Format(time.RFC3339),
+			ResourceId:  &youtube.ResourceId{Kind: "youtube#video", VideoId: v.ID},
+			Thumbnails: &youtube.ThumbnailDetails{
+				High: &youtube.Thumbnail{Url: thumbnailURL(v.ID)},
+			},
+		},
+		Status: &youtube.PlaylistItemStatus{PrivacyStatus: "public"},
+	}
+
+	switch v.Privacy {
+	case "private":
+		item.Snippet.Title, item.Snippet.Description, item.Snippet.Thumbnails = "Private video", "This video is private.", &youtube.ThumbnailDetails{}
+		item.Status.PrivacyStatus = "private"
+	case "deleted":
+		item.Snippet.Title, item.Snippet.Description, item.Snippet.Thumbnails = "Deleted video", "This video is unavailable.", &youtube.ThumbnailDetails{}
+		item.Status.PrivacyStatus = "privacyStatusUnspecified"
+	}
+
+	return item
+}
+
+func (s *Server) listVideos(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := splitIDs(r.URL.Query()["id"])
+	if len(ids) > maxIDs {
+		writeError(w, http.StatusBadRequest, "tooManyIds")
+		return
+	}
+
+	videos := map[string]Video{}
+	channelIDs := map[string]string{}
+	for _, ch := range s.channels {
+		for _, v := range ch.uploads {
+			videos[v.ID] = v
+			channelIDs[v.ID] = ch.ID
+		}
+	}
+
+	response := &youtube.VideoListResponse{Items: []*youtube.Video{}}
+	for _, id := range ids {
+		v, ok := videos[id]
+		if !ok || v.Privacy != "" {
+			continue
+		}
+		response.Items = append(response.Items, videoResource(channelIDs[id], v))
+	}
+
+	writeJSON(w, response)
+}
+
+func videoResource(channelID string, v Video) *youtube.Video {
+	video := &youtube.Video{
+		Id: v.ID,
+		Snippet: &youtube.VideoSnippet{
+			ChannelId:            channelID,
+			Title:                v.Title,
+			Description:          v.Description,
+			PublishedAt:          v.PublishedAt.UTC().Format(time.RFC3339),
+			Tags:                 v.Tags,
+			CategoryId:           v.CategoryID,
+			DefaultLanguage:      v.DefaultLanguage,
+			LiveBroadcastContent: "none",
+			Thumbnails: &youtube.ThumbnailDetails{
+				High: &youtube.Thumbnail{Url: thumbnailURL(v.ID)},
+			},
+		},
+		ContentDetails: &youtube.VideoContentDetails{Duration: isoDuration(v.Duration)},
+		Statistics: &youtube.VideoStatistics{
+			ViewCount:    v.Views,
+			LikeCount:    v.Likes,
+			CommentCount: v.Comments,
+		},
+	}
+
+	switch v.LiveStatus {
+	case "upcoming", "live":
+		video.Snippet.LiveBroadcastContent = v.LiveStatus
+		video.ContentDetails.Duration = "P0D"
+		video.LiveStreamingDetails = &youtube.VideoLiveStreamingDetails{ScheduledStartTime: video.Snippet.PublishedAt}
+	case "completed":
+		video.LiveStreamingDetails = &youtube.VideoLiveStreamingDetails{
+			ActualStartTime: video.Snippet.PublishedAt,
+			ActualEndTime:   v.PublishedAt.Add(v.Duration).UTC().Format(time.RFC3339),
+		}
+	}
+
+	return video
+}
+
+// isoDuration formats d the way videos.list does, e.g. PT1H2M3S.
+func isoDuration(d time.Duration) string {
+	if d <= 0 {
+		return "P0D"
+	}
+
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := int(d.Hours()); h > 0 {
+		b.WriteString(strconv.Itoa(h) + "H")
+	}
+	if m := int(d.Minutes()) % 60; m > 0 {
+		b.WriteString(strconv.Itoa(m) + "M")
+	}
+	if s := int(d.Seconds()) % 60; s > 0 {
+		b.WriteString(strconv.Itoa(s) + "S")
+	}
+	return b.String()
+}
+
+// splitIDs reads an id parameter, which may be repeated or hold a
+// comma-separated list.
+func splitIDs(values []string) []string {
+	var ids []string
+	for _, v := range values {
+		ids = append(ids, strings.Split(v, ",")...)
+	}
+	return ids
+}
+
+func writeError(w http.ResponseWriter, status int, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    status,
+			"message": reason,
+			"errors":  []map[string]any{{"reason": reason, "message": reason}},
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -1,11 +1,51 @@
 export interface Video {
 	id: string
 	title: string
+	description?: string
 	thumbnail?: string
+	channel_id?: string
+	duration_seconds?: number
+	view_count?: number
+	like_count?: number
+	comment_count?: number
+	tags?: string[]
+	category_id?: string
+	live_status?: 'none' | 'upcoming' | 'live' | 'completed'
+	default_language?: string
+	status: 'available' | 'private' | 'removed'
 	published_at: string
 	game?: Game
+	games: VideoGame[]
 	created_at: string
 	updated_at: string
+	match?: SearchMatch
+}
+
+export interface VideoEdit {
+	field: 'title' | 'thumbnail' | 'status'
+	old_value?: string
+	new_value?: string
+	changed_at: string
+}
+
+export interface VideoStatsPoint {
+	captured_at: string
+	view_count?: number
+	like_count?: number
+	comment_count?: number
+	views_gained?: number
+	likes_gained?: number
+}
+
+export interface SearchMatch {
+	rank: number
+	highlights: Record<string, string>
+}
+
+export interface VideoGame extends Game {
+	position: number
+	start_seconds?: number
+	end_seconds?: number
 }
 
 export interface Game {